@@ -6,28 +6,53 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 
 	cloudcfg "github.com/hanzoai/agents/control-plane/internal/cloud"
 )
 
+// pricingAPIRegion is the only region the AWS Pricing API is served from
+// regardless of which region the rest of Clients targets.
+const pricingAPIRegion = "us-east-1"
+
 // Clients holds initialized AWS service clients.
 type Clients struct {
-	EC2 *ec2.Client
-	SSM *ssm.Client
+	EC2     *ec2.Client
+	SSM     *ssm.Client
+	STS     *sts.Client
+	Pricing *pricing.Client
+
+	// Resolver built Clients' credentials and can mint additional
+	// role-scoped Clients for cross-account instance targeting. Nil on
+	// the role-scoped Clients that ClientsForRole returns.
+	Resolver *CredentialResolver
 }
 
-// NewClients creates AWS SDK v2 clients for the configured region.
-func NewClients(ctx context.Context, cfg cloudcfg.AWSConfig) (*Clients, error) {
-	awsCfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(cfg.Region),
+// NewClients creates AWS SDK v2 clients for the configured region,
+// authenticating per cfg.Auth via a CredentialResolver. Pass a non-nil
+// mfaTokenProvider if cfg.Auth.MFASerial is set.
+func NewClients(ctx context.Context, cfg cloudcfg.AWSConfig, mfaTokenProvider MFATokenProvider) (*Clients, error) {
+	resolver, err := NewCredentialResolver(ctx, cfg.Region, cfg.Auth, mfaTokenProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build AWS credential resolver: %w", err)
+	}
+	awsCfg := resolver.Config()
+
+	pricingCfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(pricingAPIRegion),
+		config.WithCredentialsProvider(awsCfg.Credentials),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		return nil, fmt.Errorf("failed to load AWS config for pricing API: %w", err)
 	}
 
 	return &Clients{
-		EC2: ec2.NewFromConfig(awsCfg),
-		SSM: ssm.NewFromConfig(awsCfg),
+		EC2:      ec2.NewFromConfig(awsCfg),
+		SSM:      ssm.NewFromConfig(awsCfg),
+		STS:      sts.NewFromConfig(awsCfg),
+		Pricing:  pricing.NewFromConfig(pricingCfg),
+		Resolver: resolver,
 	}, nil
 }
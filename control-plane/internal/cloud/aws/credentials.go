@@ -0,0 +1,202 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	cloudcfg "github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// defaultAssumeRoleSessionName and defaultAssumeRoleDurationSecond are used
+// when AWSAuthConfig leaves SessionName/DurationSeconds unset.
+const (
+	defaultAssumeRoleSessionName    = "hanzo-control-plane"
+	defaultAssumeRoleDurationSecond = 3600
+)
+
+// MFATokenProvider returns the current MFA token code for serial, for
+// AWSAuthConfig.MFASerial. Left nil, an AssumeRole that requires MFA fails.
+type MFATokenProvider func(serial string) (string, error)
+
+// CredentialResolver builds an aws.Config from a declarative AWSAuthConfig,
+// covering every credential source the aws package needs to authenticate:
+// static keys, a shared profile, AssumeRole (with ExternalID/session
+// name/duration/MFA), AssumeRoleWithWebIdentity (IRSA / GitHub OIDC), EC2
+// IMDSv2, and ECS/EKS container credentials. The last three are already
+// handled by the AWS SDK's own default credential chain, so
+// AWSAuthModeDefault (the zero value) covers them without any extra code
+// here; CredentialResolver only needs to add explicit handling for the
+// modes the default chain can't express on its own.
+type CredentialResolver struct {
+	region string
+	auth   cloudcfg.AWSAuthConfig
+
+	mfaTokenProvider MFATokenProvider
+
+	baseCfg awssdk.Config
+	baseSTS *sts.Client
+
+	roleClientsMu sync.Mutex
+	roleClients   map[string]*Clients
+}
+
+// NewCredentialResolver builds the base aws.Config described by cfg.Auth
+// for region. Pass a non-nil mfaTokenProvider if cfg.Auth.MFASerial is set.
+func NewCredentialResolver(ctx context.Context, region string, auth cloudcfg.AWSAuthConfig, mfaTokenProvider MFATokenProvider) (*CredentialResolver, error) {
+	r := &CredentialResolver{
+		region:           region,
+		auth:             auth,
+		mfaTokenProvider: mfaTokenProvider,
+		roleClients:      make(map[string]*Clients),
+	}
+
+	baseCfg, err := r.loadBaseConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.baseCfg = baseCfg
+	r.baseSTS = sts.NewFromConfig(baseCfg)
+
+	if auth.Mode == cloudcfg.AWSAuthModeAssumeRole {
+		assumedCfg, err := r.assumeRoleConfig(baseCfg, auth.AssumeRoleARN, auth.ExternalID, auth.SessionName, auth.DurationSeconds)
+		if err != nil {
+			return nil, fmt.Errorf("aws: failed to assume configured role %s: %w", auth.AssumeRoleARN, err)
+		}
+		r.baseCfg = assumedCfg
+	}
+
+	return r, nil
+}
+
+// loadBaseConfig resolves the aws.Config for everything except
+// AWSAuthModeAssumeRole's role assumption step, which is layered on top in
+// NewCredentialResolver once the base credentials are available.
+func (r *CredentialResolver) loadBaseConfig(ctx context.Context) (awssdk.Config, error) {
+	opts := []func(*config.LoadOptions) error{config.WithRegion(r.region)}
+
+	switch r.auth.Mode {
+	case cloudcfg.AWSAuthModeStatic:
+		opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			r.auth.AccessKeyID, r.auth.SecretAccessKey, r.auth.SessionToken,
+		)))
+
+	case cloudcfg.AWSAuthModeProfile:
+		opts = append(opts, config.WithSharedConfigProfile(r.auth.Profile))
+
+	case cloudcfg.AWSAuthModeAssumeRole:
+		// AssumeRole is layered on top of this base config by the caller, so
+		// the base itself just needs ambient (or profile) credentials to
+		// assume from.
+		if r.auth.Profile != "" {
+			opts = append(opts, config.WithSharedConfigProfile(r.auth.Profile))
+		}
+
+	case cloudcfg.AWSAuthModeWebIdentity:
+		if r.auth.WebIdentityTokenFile == "" || r.auth.WebIdentityRoleARN == "" {
+			return awssdk.Config{}, fmt.Errorf("aws: web_identity auth mode requires web_identity_token_file and web_identity_role_arn")
+		}
+		baseCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(r.region))
+		if err != nil {
+			return awssdk.Config{}, fmt.Errorf("aws: failed to load AWS config: %w", err)
+		}
+		baseCfg.Credentials = awssdk.NewCredentialsCache(stscreds.NewWebIdentityRoleProvider(
+			sts.NewFromConfig(baseCfg), r.auth.WebIdentityRoleARN, stscreds.IdentityTokenFile(r.auth.WebIdentityTokenFile),
+		))
+		return baseCfg, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return awssdk.Config{}, fmt.Errorf("aws: failed to load AWS config: %w", err)
+	}
+	return cfg, nil
+}
+
+// assumeRoleConfig returns a copy of baseCfg whose credentials come from
+// assuming roleARN, refreshed automatically as they near expiry.
+func (r *CredentialResolver) assumeRoleConfig(baseCfg awssdk.Config, roleARN, externalID, sessionName string, durationSeconds int32) (awssdk.Config, error) {
+	if roleARN == "" {
+		return awssdk.Config{}, fmt.Errorf("aws: assume_role auth mode requires assume_role_arn")
+	}
+	if sessionName == "" {
+		sessionName = defaultAssumeRoleSessionName
+	}
+	if durationSeconds <= 0 {
+		durationSeconds = defaultAssumeRoleDurationSecond
+	}
+
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(baseCfg), roleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = sessionName
+		o.Duration = time.Duration(durationSeconds) * time.Second
+		if externalID != "" {
+			o.ExternalID = awssdk.String(externalID)
+		}
+		if r.auth.MFASerial != "" {
+			o.SerialNumber = awssdk.String(r.auth.MFASerial)
+			if r.mfaTokenProvider != nil {
+				serial := r.auth.MFASerial
+				o.TokenProvider = func() (string, error) { return r.mfaTokenProvider(serial) }
+			}
+		}
+	})
+
+	assumed := baseCfg.Copy()
+	assumed.Credentials = awssdk.NewCredentialsCache(provider)
+	return assumed, nil
+}
+
+// Config returns the resolver's base aws.Config, reflecting AWSAuthConfig
+// but not any per-instance role assumption.
+func (r *CredentialResolver) Config() awssdk.Config {
+	return r.baseCfg
+}
+
+// ClientsForRole returns EC2/SSM/STS clients scoped to assuming roleARN on
+// top of the resolver's base credentials, for operators targeting instances
+// in a different AWS account. externalID is passed through as sts:ExternalId
+// when the target role's trust policy requires it; pass "" when it doesn't.
+// Clients are cached per role ARN + externalID pair; the underlying
+// credentials refresh themselves automatically as they near expiry, so
+// callers never need to re-resolve. An empty roleARN returns the resolver's
+// own base clients.
+func (r *CredentialResolver) ClientsForRole(ctx context.Context, roleARN, externalID string) (*Clients, error) {
+	if roleARN == "" {
+		return &Clients{
+			EC2: ec2.NewFromConfig(r.baseCfg),
+			SSM: ssm.NewFromConfig(r.baseCfg),
+			STS: r.baseSTS,
+		}, nil
+	}
+
+	cacheKey := roleARN + "|" + externalID
+
+	r.roleClientsMu.Lock()
+	defer r.roleClientsMu.Unlock()
+
+	if clients, ok := r.roleClients[cacheKey]; ok {
+		return clients, nil
+	}
+
+	assumedCfg, err := r.assumeRoleConfig(r.baseCfg, roleARN, externalID, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("aws: failed to assume role %s for instance targeting: %w", roleARN, err)
+	}
+
+	clients := &Clients{
+		EC2: ec2.NewFromConfig(assumedCfg),
+		SSM: ssm.NewFromConfig(assumedCfg),
+		STS: sts.NewFromConfig(assumedCfg),
+	}
+	r.roleClients[cacheKey] = clients
+	return clients, nil
+}
@@ -0,0 +1,134 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	cloudcfg "github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// stubSTSServer answers sts:AssumeRole requests with a fixed set of
+// credentials, recording the ExternalId form value from each request so
+// tests can assert on what ClientsForRole actually sent.
+func stubSTSServer(gotExternalID *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*gotExternalID = r.Form.Get("ExternalId")
+
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, `<?xml version="1.0"?>
+<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIASTUBBED</AccessKeyId>
+      <SecretAccessKey>stub-secret</SecretAccessKey>
+      <SessionToken>stub-session-token</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/cross-account/hanzo-control-plane</Arn>
+      <AssumedRoleId>AROASTUBBED:hanzo-control-plane</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`)
+	}))
+}
+
+// testResolver builds a CredentialResolver whose base config resolves every
+// service endpoint to endpoint, so assumeRoleConfig's internal STS calls hit
+// a stub server instead of real AWS.
+func testResolver(endpoint string) *CredentialResolver {
+	cfg := awssdk.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIABASE", "basesecret", ""),
+		EndpointResolverWithOptions: awssdk.EndpointResolverWithOptionsFunc(
+			func(service, region string, options ...interface{}) (awssdk.Endpoint, error) {
+				return awssdk.Endpoint{URL: endpoint, SigningRegion: region}, nil
+			}),
+	}
+	return &CredentialResolver{
+		auth:        cloudcfg.AWSAuthConfig{},
+		baseCfg:     cfg,
+		baseSTS:     sts.NewFromConfig(cfg),
+		roleClients: make(map[string]*Clients),
+	}
+}
+
+func TestClientsForRole_ThreadsExternalID(t *testing.T) {
+	var gotExternalID string
+	server := stubSTSServer(&gotExternalID)
+	defer server.Close()
+
+	r := testResolver(server.URL)
+
+	clients, err := r.ClientsForRole(context.Background(), "arn:aws:iam::123456789012:role/cross-account", "expected-external-id")
+	if err != nil {
+		t.Fatalf("ClientsForRole: %v", err)
+	}
+
+	// Credentials are resolved lazily; retrieving them is what actually
+	// sends the AssumeRole request to the stub server above.
+	if _, err := clients.EC2.Options().Credentials.Retrieve(context.Background()); err != nil {
+		t.Fatalf("retrieve assumed role credentials: %v", err)
+	}
+
+	if gotExternalID != "expected-external-id" {
+		t.Errorf("ExternalId = %q, want %q", gotExternalID, "expected-external-id")
+	}
+}
+
+func TestClientsForRole_EmptyExternalIDOmitsParam(t *testing.T) {
+	var gotExternalID string
+	server := stubSTSServer(&gotExternalID)
+	defer server.Close()
+
+	r := testResolver(server.URL)
+
+	clients, err := r.ClientsForRole(context.Background(), "arn:aws:iam::123456789012:role/cross-account", "")
+	if err != nil {
+		t.Fatalf("ClientsForRole: %v", err)
+	}
+	if _, err := clients.EC2.Options().Credentials.Retrieve(context.Background()); err != nil {
+		t.Fatalf("retrieve assumed role credentials: %v", err)
+	}
+
+	if gotExternalID != "" {
+		t.Errorf("ExternalId = %q, want empty", gotExternalID)
+	}
+}
+
+func TestClientsForRole_CachesPerRoleAndExternalID(t *testing.T) {
+	r := testResolver("http://127.0.0.1:0")
+
+	const roleARN = "arn:aws:iam::123456789012:role/cross-account"
+
+	a, err := r.ClientsForRole(context.Background(), roleARN, "id-a")
+	if err != nil {
+		t.Fatalf("ClientsForRole(id-a): %v", err)
+	}
+	aAgain, err := r.ClientsForRole(context.Background(), roleARN, "id-a")
+	if err != nil {
+		t.Fatalf("ClientsForRole(id-a) again: %v", err)
+	}
+	if a != aAgain {
+		t.Error("expected the same roleARN+externalID pair to return cached clients")
+	}
+
+	b, err := r.ClientsForRole(context.Background(), roleARN, "id-b")
+	if err != nil {
+		t.Fatalf("ClientsForRole(id-b): %v", err)
+	}
+	if a == b {
+		t.Error("expected distinct externalIDs for the same role to get distinct clients")
+	}
+}
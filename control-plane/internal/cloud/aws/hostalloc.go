@@ -0,0 +1,237 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	smithy "github.com/aws/smithy-go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// azCounters tracks round-robin placement state. The single hostAZCounters
+// value is package-level, so every Provisioner in the process shares one
+// rotation. Kept separate from Provisioner itself since it's only needed
+// by the macOS Dedicated Host auto-allocation path.
+type azCounters struct {
+	mu   sync.Mutex
+	next int
+}
+
+var hostAZCounters = &azCounters{}
+
+// selectAvailabilityZones returns the candidate AZs to try an AllocateHosts
+// call against, in the order they should be attempted. The chosen strategy
+// only affects which AZ is tried first; callers fail over through the rest
+// of the list on InsufficientHostCapacity.
+func (p *Provisioner) selectAvailabilityZones(ctx context.Context) ([]string, error) {
+	zones := p.awsCfg.AvailabilityZones
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("no AvailabilityZones configured for dedicated host auto-allocation")
+	}
+
+	switch p.awsCfg.MacOS.AZStrategy {
+	case "least-loaded":
+		return p.leastLoadedZones(ctx, zones)
+	case "spread":
+		return zones, nil
+	default: // "round-robin"
+		hostAZCounters.mu.Lock()
+		start := hostAZCounters.next % len(zones)
+		hostAZCounters.next++
+		hostAZCounters.mu.Unlock()
+
+		ordered := make([]string, 0, len(zones))
+		for i := 0; i < len(zones); i++ {
+			ordered = append(ordered, zones[(start+i)%len(zones)])
+		}
+		return ordered, nil
+	}
+}
+
+// leastLoadedZones orders zones by ascending current host count, so
+// allocation prefers whichever AZ has the fewest Dedicated Hosts today.
+func (p *Provisioner) leastLoadedZones(ctx context.Context, zones []string) ([]string, error) {
+	hosts, err := p.store.ListDedicatedHosts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dedicated hosts for placement: %w", err)
+	}
+
+	counts := make(map[string]int, len(zones))
+	for _, z := range zones {
+		counts[z] = 0
+	}
+	for _, h := range hosts {
+		if h.State != "released" {
+			counts[h.AvailabilityZone]++
+		}
+	}
+
+	ordered := append([]string(nil), zones...)
+	for i := 1; i < len(ordered); i++ {
+		for j := i; j > 0 && counts[ordered[j]] < counts[ordered[j-1]]; j-- {
+			ordered[j], ordered[j-1] = ordered[j-1], ordered[j]
+		}
+	}
+	return ordered, nil
+}
+
+// allocateNewHost brings up a new EC2 Dedicated Host for instanceID,
+// respecting AWSMacOSConfig.MaxHosts and failing over across
+// AvailabilityZones on InsufficientHostCapacity.
+func (p *Provisioner) allocateNewHost(ctx context.Context, instanceID string) (*cloud.DedicatedHost, error) {
+	maxHosts := p.awsCfg.MacOS.MaxHosts
+	if maxHosts <= 0 {
+		return nil, cloud.ErrNoAvailableHost
+	}
+
+	existing, err := p.store.ListDedicatedHosts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dedicated hosts: %w", err)
+	}
+	active := 0
+	for _, h := range existing {
+		if h.State != "released" {
+			active++
+		}
+	}
+	if active >= maxHosts {
+		return nil, cloud.ErrHostCeilingReached
+	}
+
+	zones, err := p.selectAvailabilityZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, az := range zones {
+		hostID, err := p.allocateHostInZone(ctx, az)
+		if err == nil {
+			now := time.Now().UTC()
+			host := &cloud.DedicatedHost{
+				ID:               fmt.Sprintf("dh-%s", hostID),
+				HostID:           hostID,
+				InstanceType:     p.awsCfg.MacOS.InstanceType,
+				State:            "allocated",
+				CurrentInstanceID: instanceID,
+				AvailabilityZone: az,
+				AutoAllocated:    true,
+				AllocatedAt:      &now,
+				MinAllocation:    p.awsCfg.MacOS.MinHostAllocation,
+				CreatedAt:        now,
+				UpdatedAt:        now,
+			}
+			if err := p.store.CreateDedicatedHost(ctx, host); err != nil {
+				return nil, fmt.Errorf("failed to record auto-allocated host %s: %w", hostID, err)
+			}
+
+			log.Info().Str("host_id", hostID).Str("az", az).Msg("auto-allocated dedicated host")
+			return host, nil
+		}
+
+		lastErr = err
+		if !isInsufficientHostCapacity(err) {
+			return nil, err
+		}
+		log.Warn().Str("az", az).Err(err).Msg("dedicated host capacity exhausted, trying next AZ")
+	}
+
+	return nil, fmt.Errorf("failed to allocate dedicated host in any configured AZ: %w", lastErr)
+}
+
+// allocateHostInZone calls ec2.AllocateHosts for a single host in az and
+// returns the allocated HostId.
+func (p *Provisioner) allocateHostInZone(ctx context.Context, az string) (string, error) {
+	var out *ec2.AllocateHostsOutput
+	err := p.withRetry(ctx, "AllocateHosts", func() error {
+		var callErr error
+		out, callErr = p.clients.EC2.AllocateHosts(ctx, &ec2.AllocateHostsInput{
+			AvailabilityZone: aws.String(az),
+			InstanceType:     aws.String(p.awsCfg.MacOS.InstanceType),
+			Quantity:         aws.Int32(1),
+			AutoPlacement:    ec2types.AutoPlacementOff,
+			TagSpecifications: []ec2types.TagSpecification{
+				{
+					ResourceType: ec2types.ResourceTypeDedicatedHost,
+					Tags: []ec2types.Tag{
+						{Key: aws.String("hanzo.ai/auto-allocated"), Value: aws.String("true")},
+					},
+				},
+			},
+		})
+		return callErr
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(out.HostIds) == 0 {
+		return "", fmt.Errorf("AllocateHosts returned no host IDs")
+	}
+	return out.HostIds[0], nil
+}
+
+// ReconcileOrphanedHosts implements cloud.HostReconciler. It releases any
+// Dedicated Host whose CurrentInstanceID is set but not present in
+// liveInstanceIDs back to the available pool, so a host doesn't stay stuck
+// "allocated" to an instance that crashed or was terminated outside of our
+// own TerminateInstance path, quietly billing while unusable. This only
+// flips the DB state back to "available" for reuse; it does not call
+// ec2.ReleaseHosts, since a host with no live instance is still a perfectly
+// good host for the next one, and HostReaper already handles returning
+// truly idle auto-allocated hosts to AWS.
+func (p *Provisioner) ReconcileOrphanedHosts(ctx context.Context, liveInstanceIDs map[string]bool) (int, error) {
+	if p.store == nil {
+		return 0, nil
+	}
+
+	hosts, err := p.store.ListDedicatedHosts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list dedicated hosts: %w", err)
+	}
+
+	released := 0
+	for _, host := range hosts {
+		if host.State != "allocated" || host.CurrentInstanceID == "" {
+			continue
+		}
+		if liveInstanceIDs[host.CurrentInstanceID] {
+			continue
+		}
+
+		log.Warn().
+			Str("host_id", host.HostID).
+			Str("instance_id", host.CurrentInstanceID).
+			Msg("reconcile: dedicated host orphaned by missing instance, releasing to pool")
+
+		now := time.Now().UTC()
+		host.State = "available"
+		host.CurrentInstanceID = ""
+		host.ReleasedAt = &now
+		if err := p.store.UpdateDedicatedHost(ctx, host); err != nil {
+			log.Error().Err(err).Str("host_id", host.HostID).Msg("reconcile: failed to release orphaned host")
+			continue
+		}
+		released++
+	}
+
+	return released, nil
+}
+
+// isInsufficientHostCapacity reports whether err is the AWS error AllocateHosts
+// returns when a zone has no free Dedicated Host capacity for the requested
+// instance type.
+func isInsufficientHostCapacity(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.ErrorCode() == "InsufficientHostCapacity" || apiErr.ErrorCode() == "InsufficientCapacityOnHost"
+}
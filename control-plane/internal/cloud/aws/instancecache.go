@@ -0,0 +1,293 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// instanceCacheTTL bounds how long a cached DescribeInstances result is
+// reused before a lookup is treated as a miss.
+const instanceCacheTTL = 10 * time.Second
+
+// instanceCacheCoalesceWindow is how long Get waits to batch concurrent
+// misses for other instance IDs into the same DescribeInstances call,
+// trading a small amount of latency for far fewer API round-trips when
+// many lookups land close together (e.g. a reconcile loop).
+const instanceCacheCoalesceWindow = 15 * time.Millisecond
+
+type cachedInstance struct {
+	instance  ec2types.Instance
+	fetchedAt time.Time
+}
+
+// instanceLookup is a pending Get() call waiting on a batched
+// DescribeInstances result for its instance ID.
+type instanceLookup struct {
+	instanceID string
+	resultCh   chan instanceLookupResult
+}
+
+type instanceLookupResult struct {
+	instance *ec2types.Instance
+	err      error
+}
+
+// instanceCache caches EC2 instances by the hanzo.ai/cloud-instance tag
+// and coalesces concurrent misses into a single batched DescribeInstances
+// call, so a serial reconcile of N instances costs a handful of API calls
+// instead of N.
+type instanceCache struct {
+	provisioner *Provisioner
+
+	mu      sync.Mutex
+	entries map[string]cachedInstance
+
+	pendingMu sync.Mutex
+	pending   []instanceLookup
+	timer     *time.Timer
+}
+
+func newInstanceCache(p *Provisioner) *instanceCache {
+	return &instanceCache{
+		provisioner: p,
+		entries:     make(map[string]cachedInstance),
+	}
+}
+
+// Get returns the EC2 instance for instanceID, from cache if still fresh,
+// otherwise by joining (or starting) the next batched DescribeInstances
+// call.
+func (c *instanceCache) Get(ctx context.Context, instanceID string) (*ec2types.Instance, error) {
+	if inst, ok := c.fromCache(instanceID); ok {
+		c.provisioner.recordCacheLookup("hit")
+		return inst, nil
+	}
+	c.provisioner.recordCacheLookup("miss")
+
+	resultCh := make(chan instanceLookupResult, 1)
+	c.enqueue(instanceLookup{instanceID: instanceID, resultCh: resultCh})
+
+	select {
+	case res := <-resultCh:
+		return res.instance, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Invalidate drops a cached instance, used after a state-changing call
+// (Start/Stop/Terminate) so the next Get reflects it instead of serving a
+// stale cached state.
+func (c *instanceCache) Invalidate(instanceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, instanceID)
+}
+
+// Refresh re-populates the cache for every Hanzo-managed instance from one
+// paginated DescribeInstances listing (one call per page), satisfying the
+// background refresher and Provisioner.SyncAll.
+func (c *instanceCache) Refresh(ctx context.Context) ([]ec2types.Instance, error) {
+	var instances []ec2types.Instance
+	paginator := ec2.NewDescribeInstancesPaginator(c.provisioner.clients.EC2, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{Name: awssdk.String("tag:hanzo.ai/cloud-instance"), Values: []string{"*"}},
+		},
+	})
+	for paginator.HasMorePages() {
+		var page *ec2.DescribeInstancesOutput
+		err := c.provisioner.withRetry(ctx, "DescribeInstances", func() error {
+			var callErr error
+			page, callErr = paginator.NextPage(ctx)
+			return callErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instances: %w", err)
+		}
+		for _, res := range page.Reservations {
+			instances = append(instances, res.Instances...)
+		}
+	}
+
+	now := time.Now().UTC()
+	c.mu.Lock()
+	for _, inst := range instances {
+		id := instanceCacheKey(inst)
+		if id == "" {
+			continue
+		}
+		c.entries[id] = cachedInstance{instance: inst, fetchedAt: now}
+	}
+	c.mu.Unlock()
+
+	return instances, nil
+}
+
+func (c *instanceCache) fromCache(instanceID string) (*ec2types.Instance, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[instanceID]
+	if !ok || time.Since(entry.fetchedAt) > instanceCacheTTL {
+		return nil, false
+	}
+	inst := entry.instance
+	return &inst, true
+}
+
+func (c *instanceCache) enqueue(lookup instanceLookup) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	c.pending = append(c.pending, lookup)
+	if c.timer == nil {
+		c.timer = time.AfterFunc(instanceCacheCoalesceWindow, c.flush)
+	}
+}
+
+func (c *instanceCache) flush() {
+	c.pendingMu.Lock()
+	batch := c.pending
+	c.pending = nil
+	c.timer = nil
+	c.pendingMu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(batch))
+	seen := make(map[string]bool, len(batch))
+	for _, lookup := range batch {
+		if !seen[lookup.instanceID] {
+			seen[lookup.instanceID] = true
+			ids = append(ids, lookup.instanceID)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	found := make(map[string]ec2types.Instance, len(ids))
+	var batchErr error
+	err := c.provisioner.withRetry(ctx, "DescribeInstances", func() error {
+		out, callErr := c.provisioner.clients.EC2.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+			Filters: []ec2types.Filter{
+				{Name: awssdk.String("tag:hanzo.ai/cloud-instance"), Values: ids},
+				{Name: awssdk.String("instance-state-name"), Values: []string{"pending", "running", "stopping", "stopped"}},
+			},
+		})
+		if callErr != nil {
+			return callErr
+		}
+		for _, res := range out.Reservations {
+			for _, inst := range res.Instances {
+				if id := instanceCacheKey(inst); id != "" {
+					found[id] = inst
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		batchErr = fmt.Errorf("failed to describe instances: %w", err)
+	}
+
+	now := time.Now().UTC()
+	c.mu.Lock()
+	for id, inst := range found {
+		c.entries[id] = cachedInstance{instance: inst, fetchedAt: now}
+	}
+	c.mu.Unlock()
+
+	for _, lookup := range batch {
+		inst, ok := found[lookup.instanceID]
+		switch {
+		case batchErr != nil:
+			lookup.resultCh <- instanceLookupResult{err: batchErr}
+		case !ok:
+			lookup.resultCh <- instanceLookupResult{err: cloud.ErrInstanceNotFound}
+		default:
+			instCopy := inst
+			lookup.resultCh <- instanceLookupResult{instance: &instCopy}
+		}
+	}
+}
+
+func instanceCacheKey(inst ec2types.Instance) string {
+	for _, tag := range inst.Tags {
+		if awssdk.ToString(tag.Key) == "hanzo.ai/cloud-instance" {
+			return awssdk.ToString(tag.Value)
+		}
+	}
+	return ""
+}
+
+// SyncAll reconciles the entire fleet of Hanzo-managed instances from one
+// paginated DescribeInstances listing instead of one call per instance,
+// returning every
+// instance's current state keyed by cloud instance ID.
+func (p *Provisioner) SyncAll(ctx context.Context) (map[string]*cloud.CloudInstance, error) {
+	ec2Instances, err := p.instances().Refresh(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sync all: %w", err)
+	}
+
+	out := make(map[string]*cloud.CloudInstance, len(ec2Instances))
+	for _, ec2Inst := range ec2Instances {
+		id := instanceCacheKey(ec2Inst)
+		if id == "" {
+			continue
+		}
+		out[id] = ec2InstanceToCloudInstance(id, &ec2Inst, p.awsCfg.Region)
+	}
+	return out, nil
+}
+
+// StartInstanceCacheRefresher runs a background loop that repopulates the
+// instance cache every interval, so most Get calls are served from cache
+// even without a recent in-flight lookup to coalesce onto. Call this once
+// during server wiring; the cache works fine without it, lookups just
+// always pay for an API round-trip on a miss.
+func (p *Provisioner) StartInstanceCacheRefresher(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := p.instances().Refresh(ctx); err != nil {
+					log.Warn().Err(err).Msg("instance cache refresher: failed to refresh fleet")
+				}
+			}
+		}
+	}()
+}
+
+// instances lazily initializes the provisioner's instance cache.
+func (p *Provisioner) instances() *instanceCache {
+	p.cacheOnce.Do(func() {
+		p.instanceCache = newInstanceCache(p)
+	})
+	return p.instanceCache
+}
+
+func (p *Provisioner) recordCacheLookup(result string) {
+	p.metrics.recordCacheLookup(result)
+}
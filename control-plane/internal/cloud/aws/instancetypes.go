@@ -0,0 +1,425 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	pricingtypes "github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// reservedMemoryGiB approximates the kubelet/OS/ENI overhead subtracted
+// from an instance type's advertised memory before comparing it against
+// InstanceRequirements.Min/MaxMemoryGiB, mirroring how Karpenter and EKS's
+// own allocatable calculation reserve a slice of host memory for the node
+// itself rather than workloads.
+const reservedMemoryGiB = 0.75
+
+// instanceTypeCatalogTTL bounds how long a region's DescribeInstanceTypes
+// catalog is cached before being refreshed.
+const instanceTypeCatalogTTL = time.Hour
+
+// instanceTypeCandidate is one EC2 instance type's shape, as cached from
+// DescribeInstanceTypes.
+type instanceTypeCandidate struct {
+	Name         string
+	VCPU         int
+	MemoryGiB    float64
+	GPUCount     int
+	Architecture string
+}
+
+// instanceTypeCatalog caches DescribeInstanceTypes results, refreshed at
+// most once per instanceTypeCatalogTTL.
+type instanceTypeCatalog struct {
+	mu         sync.Mutex
+	fetchedAt  time.Time
+	candidates []instanceTypeCandidate
+}
+
+// onDemandPriceCache caches the Pricing API's hourly on-demand price per
+// instance type, since GetProducts is a slow call and the price rarely
+// changes within a session.
+type onDemandPriceCache struct {
+	mu     sync.Mutex
+	prices map[string]int // instance type -> cents/hour
+}
+
+// SelectInstanceType implements cloud.InstanceTypeProvider: it enumerates
+// current-generation EC2 instance types matching requirements, available
+// in the configured subnets' availability zones, and returns whichever is
+// cheapest by on-demand price (or latest spot price, when CapacityType is
+// "spot").
+func (p *Provisioner) SelectInstanceType(ctx context.Context, requirements cloud.InstanceRequirements) (string, error) {
+	candidates, err := p.catalog().list(ctx, p.clients.EC2)
+	if err != nil {
+		return "", fmt.Errorf("list instance types: %w", err)
+	}
+
+	var fit []instanceTypeCandidate
+	for _, c := range candidates {
+		if requirements.Architecture != "" && c.Architecture != requirements.Architecture {
+			continue
+		}
+		if requirements.MinVCPU > 0 && c.VCPU < requirements.MinVCPU {
+			continue
+		}
+		if requirements.MaxVCPU > 0 && c.VCPU > requirements.MaxVCPU {
+			continue
+		}
+		if requirements.GPUCount > 0 && c.GPUCount < requirements.GPUCount {
+			continue
+		}
+
+		usableMemoryGiB := c.MemoryGiB - reservedMemoryGiB
+		if requirements.MinMemoryGiB > 0 && usableMemoryGiB < requirements.MinMemoryGiB {
+			continue
+		}
+		if requirements.MaxMemoryGiB > 0 && usableMemoryGiB > requirements.MaxMemoryGiB {
+			continue
+		}
+
+		fit = append(fit, c)
+	}
+	if len(fit) == 0 {
+		return "", fmt.Errorf("no instance type satisfies the given requirements")
+	}
+
+	available, err := p.filterByAZAvailability(ctx, fit)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to filter instance types by AZ availability; considering all candidates")
+		available = fit
+	}
+	if len(available) == 0 {
+		return "", fmt.Errorf("no instance type satisfying requirements is available in the configured AZs")
+	}
+
+	spot := requirements.CapacityType == "spot"
+
+	var best instanceTypeCandidate
+	bestCents := -1
+	for _, c := range available {
+		cents, ok := p.priceCentsFor(ctx, c.Name, spot)
+		if !ok {
+			continue
+		}
+		if bestCents == -1 || cents < bestCents {
+			best, bestCents = c, cents
+		}
+	}
+
+	if bestCents == -1 {
+		// Every pricing lookup failed (e.g. Pricing API unreachable); fall
+		// back to the smallest fit rather than failing provisioning
+		// outright over a pricing hiccup.
+		best = available[0]
+		for _, c := range available {
+			if c.VCPU < best.VCPU {
+				best = c
+			}
+		}
+	}
+
+	return best.Name, nil
+}
+
+func (p *Provisioner) catalog() *instanceTypeCatalog {
+	if p.typeCatalog == nil {
+		p.typeCatalog = &instanceTypeCatalog{}
+	}
+	return p.typeCatalog
+}
+
+func (c *instanceTypeCatalog) list(ctx context.Context, client *ec2.Client) ([]instanceTypeCandidate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.candidates) > 0 && time.Since(c.fetchedAt) < instanceTypeCatalogTTL {
+		return c.candidates, nil
+	}
+
+	var candidates []instanceTypeCandidate
+	paginator := ec2.NewDescribeInstanceTypesPaginator(client, &ec2.DescribeInstanceTypesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("current-generation"), Values: []string{"true"}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range page.InstanceTypes {
+			candidates = append(candidates, instanceTypeCandidateFrom(it))
+		}
+	}
+
+	c.candidates = candidates
+	c.fetchedAt = time.Now()
+	return candidates, nil
+}
+
+func instanceTypeCandidateFrom(it ec2types.InstanceTypeInfo) instanceTypeCandidate {
+	var vcpu int
+	if it.VCpuInfo != nil && it.VCpuInfo.DefaultVCpus != nil {
+		vcpu = int(*it.VCpuInfo.DefaultVCpus)
+	}
+
+	var memoryGiB float64
+	if it.MemoryInfo != nil && it.MemoryInfo.SizeInMiB != nil {
+		memoryGiB = float64(*it.MemoryInfo.SizeInMiB) / 1024
+	}
+
+	var gpuCount int
+	if it.GpuInfo != nil {
+		for _, g := range it.GpuInfo.Gpus {
+			if g.Count != nil {
+				gpuCount += int(*g.Count)
+			}
+		}
+	}
+
+	arch := "x86_64"
+	if it.ProcessorInfo != nil {
+		for _, a := range it.ProcessorInfo.SupportedArchitectures {
+			if a == ec2types.ArchitectureTypeArm64 {
+				arch = "arm64"
+			}
+		}
+	}
+
+	return instanceTypeCandidate{
+		Name:         string(it.InstanceType),
+		VCPU:         vcpu,
+		MemoryGiB:    memoryGiB,
+		GPUCount:     gpuCount,
+		Architecture: arch,
+	}
+}
+
+// filterByAZAvailability keeps only candidates offered in at least one of
+// the configured subnets' availability zones, falling back to checking
+// regional availability if the subnet AZs can't be resolved.
+func (p *Provisioner) filterByAZAvailability(ctx context.Context, candidates []instanceTypeCandidate) ([]instanceTypeCandidate, error) {
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.Name
+	}
+
+	locationType := ec2types.LocationTypeRegion
+	locations := []string{p.awsCfg.Region}
+	if azs, err := p.subnetAvailabilityZones(ctx); err == nil && len(azs) > 0 {
+		locationType = ec2types.LocationTypeAvailabilityZone
+		locations = azs
+	}
+
+	offered := make(map[string]struct{})
+	for _, loc := range locations {
+		out, err := p.clients.EC2.DescribeInstanceTypeOfferings(ctx, &ec2.DescribeInstanceTypeOfferingsInput{
+			LocationType: locationType,
+			Filters: []ec2types.Filter{
+				{Name: aws.String("location"), Values: []string{loc}},
+				{Name: aws.String("instance-type"), Values: names},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range out.InstanceTypeOfferings {
+			offered[string(o.InstanceType)] = struct{}{}
+		}
+	}
+
+	var out []instanceTypeCandidate
+	for _, c := range candidates {
+		if _, ok := offered[c.Name]; ok {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// subnetAvailabilityZones resolves the configured subnet IDs to their
+// availability zones.
+func (p *Provisioner) subnetAvailabilityZones(ctx context.Context) ([]string, error) {
+	if len(p.awsCfg.SubnetIDs) == 0 {
+		return nil, nil
+	}
+
+	out, err := p.clients.EC2.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: p.awsCfg.SubnetIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var azs []string
+	for _, s := range out.Subnets {
+		az := aws.ToString(s.AvailabilityZone)
+		if az == "" {
+			continue
+		}
+		if _, ok := seen[az]; !ok {
+			seen[az] = struct{}{}
+			azs = append(azs, az)
+		}
+	}
+	return azs, nil
+}
+
+// priceCentsFor returns instanceType's hourly price in cents, true if a
+// price could be determined.
+func (p *Provisioner) priceCentsFor(ctx context.Context, instanceType string, spot bool) (int, bool) {
+	if spot {
+		if p.spotPrices != nil {
+			if cents, ok := p.spotPrices.PriceCents(instanceType); ok {
+				return cents, true
+			}
+		}
+		cents, err := p.fetchSpotPrice(ctx, instanceType)
+		if err != nil {
+			log.Debug().Err(err).Str("instance_type", instanceType).Msg("failed to fetch spot price for instance type selection")
+			return 0, false
+		}
+		return cents, true
+	}
+
+	if p.onDemandPrices == nil {
+		p.onDemandPrices = &onDemandPriceCache{prices: make(map[string]int)}
+	}
+
+	p.onDemandPrices.mu.Lock()
+	cents, ok := p.onDemandPrices.prices[instanceType]
+	p.onDemandPrices.mu.Unlock()
+	if ok {
+		return cents, true
+	}
+
+	cents, err := p.fetchOnDemandPrice(ctx, instanceType)
+	if err != nil {
+		log.Debug().Err(err).Str("instance_type", instanceType).Msg("failed to fetch on-demand price for instance type selection")
+		return 0, false
+	}
+
+	p.onDemandPrices.mu.Lock()
+	p.onDemandPrices.prices[instanceType] = cents
+	p.onDemandPrices.mu.Unlock()
+
+	return cents, true
+}
+
+func (p *Provisioner) fetchSpotPrice(ctx context.Context, instanceType string) (int, error) {
+	out, err := p.clients.EC2.DescribeSpotPriceHistory(ctx, &ec2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       []ec2types.InstanceType{ec2types.InstanceType(instanceType)},
+		ProductDescriptions: []string{"Linux/UNIX"},
+		MaxResults:          aws.Int32(1),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(out.SpotPriceHistory) == 0 {
+		return 0, fmt.Errorf("no spot price history for %s", instanceType)
+	}
+
+	return dollarsToCents(aws.ToString(out.SpotPriceHistory[0].SpotPrice))
+}
+
+// pricingLocationNames maps common EC2 region codes to the Pricing API's
+// location name, which GetProducts requires in place of the region code.
+// Covers the regions this deployment commonly uses; an unmapped region
+// falls back to the region code itself, which simply yields no results,
+// causing SelectInstanceType to fall back to the smallest-vCPU candidate.
+var pricingLocationNames = map[string]string{
+	"us-east-1":      "US East (N. Virginia)",
+	"us-east-2":      "US East (Ohio)",
+	"us-west-1":      "US West (N. California)",
+	"us-west-2":      "US West (Oregon)",
+	"eu-west-1":      "EU (Ireland)",
+	"eu-central-1":   "EU (Frankfurt)",
+	"ap-southeast-1": "Asia Pacific (Singapore)",
+	"ap-southeast-2": "Asia Pacific (Sydney)",
+	"ap-northeast-1": "Asia Pacific (Tokyo)",
+}
+
+func (p *Provisioner) fetchOnDemandPrice(ctx context.Context, instanceType string) (int, error) {
+	location := pricingLocationNames[p.awsCfg.Region]
+	if location == "" {
+		location = p.awsCfg.Region
+	}
+
+	out, err := p.clients.Pricing.GetProducts(ctx, &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonEC2"),
+		Filters: []pricingtypes.Filter{
+			{Type: pricingtypes.FilterTypeTermMatch, Field: aws.String("instanceType"), Value: aws.String(instanceType)},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: aws.String("location"), Value: aws.String(location)},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: aws.String("operatingSystem"), Value: aws.String("Linux")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: aws.String("tenancy"), Value: aws.String("Shared")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: aws.String("preInstalledSw"), Value: aws.String("NA")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: aws.String("capacitystatus"), Value: aws.String("Used")},
+		},
+		MaxResults: aws.Int32(1),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(out.PriceList) == 0 {
+		return 0, fmt.Errorf("no pricing data for %s in %s", instanceType, location)
+	}
+
+	return parseOnDemandPriceList(out.PriceList[0])
+}
+
+// onDemandPriceDocument is the subset of the Pricing API's deeply nested
+// PriceList JSON document needed to pull out the hourly USD price.
+type onDemandPriceDocument struct {
+	Terms struct {
+		OnDemand map[string]struct {
+			PriceDimensions map[string]struct {
+				PricePerUnit struct {
+					USD string `json:"USD"`
+				} `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+func parseOnDemandPriceList(raw string) (int, error) {
+	var doc onDemandPriceDocument
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return 0, fmt.Errorf("parse pricing document: %w", err)
+	}
+
+	for _, term := range doc.Terms.OnDemand {
+		for _, dim := range term.PriceDimensions {
+			if cents, err := dollarsToCents(dim.PricePerUnit.USD); err == nil {
+				return cents, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("no onDemand price dimension found")
+}
+
+func dollarsToCents(s string) (int, error) {
+	dollars, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	cents := int(dollars*100 + 0.5)
+	if cents < 1 {
+		cents = 1
+	}
+	return cents, nil
+}
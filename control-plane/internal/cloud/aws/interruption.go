@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// interruptionMetadataPath is the curl command that reads the
+// instance-metadata path which starts returning a termination time once
+// AWS has issued a 2-minute spot interruption warning for the instance.
+// -S keeps curl's error message (including the HTTP status) on stderr. See:
+// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/spot-instance-termination-notices.html
+const interruptionMetadataPath = "curl -sS -f http://169.254.169.254/latest/meta-data/spot/instance-action"
+
+// InterruptionWatcher polls tracked spot instances for the instance-action
+// metadata that appears once AWS has scheduled them for interruption,
+// using SSM RunCommand rather than a data-channel agent on the instance
+// itself. EventBridge's "EC2 Spot Instance Interruption Warning" event
+// would avoid the polling latency, but wiring an EventBridge rule/target
+// is environment-specific infrastructure setup outside this provisioner's
+// reach, so SSM polling is used as the portable default.
+type InterruptionWatcher struct {
+	provisioner *Provisioner
+	interval    time.Duration
+	onWarning   func(ctx context.Context, instanceID string)
+
+	mu        sync.Mutex
+	tracked   map[string]struct{}
+	announced map[string]struct{}
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewInterruptionWatcher creates a watcher that invokes onWarning once per
+// instance the first time its interruption notice appears.
+func NewInterruptionWatcher(p *Provisioner, interval time.Duration, onWarning func(ctx context.Context, instanceID string)) *InterruptionWatcher {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &InterruptionWatcher{
+		provisioner: p,
+		interval:    interval,
+		onWarning:   onWarning,
+		tracked:     make(map[string]struct{}),
+		announced:   make(map[string]struct{}),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Track adds a spot instance to the set polled on each tick. Safe to call
+// repeatedly; duplicates are no-ops.
+func (w *InterruptionWatcher) Track(instanceID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tracked[instanceID] = struct{}{}
+}
+
+// Untrack removes an instance once it's terminated or replaced, so it's no
+// longer polled.
+func (w *InterruptionWatcher) Untrack(instanceID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.tracked, instanceID)
+	delete(w.announced, instanceID)
+}
+
+// Start runs the poll loop until Stop is called.
+func (w *InterruptionWatcher) Start() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", w.interval).Msg("spot interruption watcher started")
+
+	for {
+		select {
+		case <-w.stopCh:
+			log.Info().Msg("spot interruption watcher stopped")
+			return
+		case <-ticker.C:
+			w.tick()
+		}
+	}
+}
+
+// Stop terminates the poll loop.
+func (w *InterruptionWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+	})
+}
+
+func (w *InterruptionWatcher) tick() {
+	w.mu.Lock()
+	instanceIDs := make([]string, 0, len(w.tracked))
+	for id := range w.tracked {
+		instanceIDs = append(instanceIDs, id)
+	}
+	w.mu.Unlock()
+
+	for _, instanceID := range instanceIDs {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		interrupted, err := w.checkInterruption(ctx, instanceID)
+		cancel()
+		if err != nil {
+			log.Debug().Err(err).Str("instance_id", instanceID).Msg("interruption watcher: check failed")
+			continue
+		}
+		if !interrupted {
+			continue
+		}
+
+		w.mu.Lock()
+		_, already := w.announced[instanceID]
+		w.announced[instanceID] = struct{}{}
+		w.mu.Unlock()
+		if already {
+			continue
+		}
+
+		log.Warn().Str("instance_id", instanceID).Msg("spot interruption notice detected")
+		if w.onWarning != nil {
+			w.onWarning(context.Background(), instanceID)
+		}
+	}
+}
+
+// checkInterruption returns true once the instance-action metadata path
+// starts returning a termination action instead of a 404.
+func (w *InterruptionWatcher) checkInterruption(ctx context.Context, instanceID string) (bool, error) {
+	out, err := RunCommand(ctx, w.provisioner.clients.SSM, instanceID, interruptionMetadataPath, "linux")
+	if err != nil {
+		// A 404 from the metadata endpoint surfaces as a non-zero curl
+		// exit, which RunCommand reports as an error; that's the normal
+		// not-yet-interrupted case, not a failure worth logging loudly.
+		if strings.Contains(err.Error(), "404") {
+			return false, nil
+		}
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+// Drain implements cloud.Drainer. AWS's actual interruption handling
+// (stopping rather than terminating, provisioning an on-demand
+// replacement) is already driven by CloudManager.HandleInterruptionWarning;
+// there's no additional provider-specific cleanup the EC2 API needs here
+// before that runs, so this just records the drain for observability.
+func (p *Provisioner) Drain(ctx context.Context, instanceID string) error {
+	log.Info().Str("instance_id", instanceID).Msg("draining AWS instance ahead of spot interruption")
+	return nil
+}
@@ -0,0 +1,166 @@
+package aws
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"sync"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// windowsKeyName is the EC2 key pair name KeyManager generates and manages
+// on behalf of every Windows instance, so GetPasswordData's RDP password
+// blob can always be decrypted server-side.
+const windowsKeyName = "hanzo-agent-windows"
+
+// windowsKeySecretName is the key KeyManager stores the sealed private key
+// PEM under in its SecretStore.
+const windowsKeySecretName = "aws/windows-rdp-key"
+
+// KMSProvider seals and unseals a managed private key at rest, independent
+// of where the sealed bytes are persisted (see SecretStore).
+// Implementations might call out to AWS KMS or Vault's transit engine; the
+// default LocalKMSProvider just wraps AES-GCM with a locally-held key, for
+// local/dev use.
+type KMSProvider interface {
+	Seal(ctx context.Context, plaintext []byte) ([]byte, error)
+	Unseal(ctx context.Context, sealed []byte) ([]byte, error)
+}
+
+// SecretStore persists a sealed blob for KeyManager. Implementations might
+// write to a local file, an S3 object, Vault's KV store, etc.
+type SecretStore interface {
+	// Get returns the stored value for key, or nil, nil if it doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, value []byte) error
+}
+
+// KeyManager generates (or imports) the EC2 key pair used for Windows
+// instances, seals its private key with a KMSProvider, and persists the
+// sealed blob in a SecretStore — so the control plane can decrypt
+// GetPasswordData's RDP password without a human ever holding the private
+// key on their own machine.
+type KeyManager struct {
+	clients *Clients
+	kms     KMSProvider
+	secrets SecretStore
+
+	mu     sync.Mutex
+	cached *rsa.PrivateKey
+}
+
+// NewKeyManager creates a KeyManager backed by kms/secrets. Both must be
+// non-nil; there is no no-op default because a Windows instance launched
+// without a manageable key pair can never have its password decrypted.
+func NewKeyManager(clients *Clients, kms KMSProvider, secrets SecretStore) *KeyManager {
+	return &KeyManager{clients: clients, kms: kms, secrets: secrets}
+}
+
+// EnsureKeyPair returns the EC2 key name to use for a Windows launch,
+// generating and importing a new key pair (and sealing its private key)
+// on first use.
+func (km *KeyManager) EnsureKeyPair(ctx context.Context) (string, error) {
+	if _, err := km.privateKey(ctx); err != nil {
+		return "", err
+	}
+	return windowsKeyName, nil
+}
+
+// DecryptPassword base64-decodes and PKCS#1 v1.5 RSA-decrypts encryptedPasswordData
+// (the raw PasswordData field from ec2.GetPasswordDataOutput) using the
+// managed private key, returning the plaintext administrator password.
+func (km *KeyManager) DecryptPassword(ctx context.Context, encryptedPasswordData string) (string, error) {
+	priv, err := km.privateKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encryptedPasswordData)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode password data: %w", err)
+	}
+
+	plaintext, err := rsa.DecryptPKCS1v15(nil, priv, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt password: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// privateKey returns the managed private key, loading it from the
+// SecretStore (or generating and importing a fresh one) on first use.
+func (km *KeyManager) privateKey(ctx context.Context) (*rsa.PrivateKey, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	if km.cached != nil {
+		return km.cached, nil
+	}
+
+	sealed, err := km.secrets.Get(ctx, windowsKeySecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read managed key secret: %w", err)
+	}
+	if sealed != nil {
+		priv, err := km.unsealPrivateKey(ctx, sealed)
+		if err != nil {
+			return nil, err
+		}
+		km.cached = priv
+		return priv, nil
+	}
+
+	priv, err := km.generateAndImport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	km.cached = priv
+	return priv, nil
+}
+
+func (km *KeyManager) unsealPrivateKey(ctx context.Context, sealed []byte) (*rsa.PrivateKey, error) {
+	pemBytes, err := km.kms.Unseal(ctx, sealed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal managed key: %w", err)
+	}
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode managed key PEM")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+func (km *KeyManager) generateAndImport(ctx context.Context) (*rsa.PrivateKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, sshKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate managed key pair: %w", err)
+	}
+
+	wire := sshWireFormatRSAPublicKey(&priv.PublicKey)
+	_, err = km.clients.EC2.ImportKeyPair(ctx, &ec2.ImportKeyPairInput{
+		KeyName:           awssdk.String(windowsKeyName),
+		PublicKeyMaterial: wire,
+	})
+	if err != nil && !isKeyPairAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to import managed key pair: %w", err)
+	}
+
+	privDER := x509.MarshalPKCS1PrivateKey(priv)
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: privDER})
+
+	sealed, err := km.kms.Seal(ctx, privPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal managed key: %w", err)
+	}
+	if err := km.secrets.Put(ctx, windowsKeySecretName, sealed); err != nil {
+		return nil, fmt.Errorf("failed to persist managed key secret: %w", err)
+	}
+
+	return priv, nil
+}
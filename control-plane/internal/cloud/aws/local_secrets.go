@@ -0,0 +1,113 @@
+package aws
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalKMSProvider seals a KeyManager private key with AES-256-GCM under a
+// locally-held 32-byte key, for local/dev deployments that don't have a
+// real KMS or Vault available. Production deployments should supply a
+// KMSProvider backed by the cloud provider's KMS instead.
+type LocalKMSProvider struct {
+	key []byte // 32 bytes
+}
+
+// NewLocalKMSProvider creates a LocalKMSProvider from a 32-byte AES-256 key.
+func NewLocalKMSProvider(key []byte) (*LocalKMSProvider, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("local KMS key must be 32 bytes, got %d", len(key))
+	}
+	return &LocalKMSProvider{key: key}, nil
+}
+
+// Seal encrypts plaintext with AES-256-GCM, prefixing the ciphertext with
+// its nonce.
+func (l *LocalKMSProvider) Seal(ctx context.Context, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(l.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Unseal reverses Seal.
+func (l *LocalKMSProvider) Unseal(ctx context.Context, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(l.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, fmt.Errorf("sealed value too short")
+	}
+	nonce, ciphertext := sealed[:nonceSize], sealed[nonceSize:]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// LocalSealedFileStore persists sealed secrets as files under a base
+// directory, one file per key. Suitable for single-node/dev deployments;
+// multi-node deployments should supply a SecretStore backed by shared
+// storage (S3, Vault's KV store, etc).
+type LocalSealedFileStore struct {
+	baseDir string
+}
+
+// NewLocalSealedFileStore creates a LocalSealedFileStore rooted at baseDir,
+// creating it if necessary.
+func NewLocalSealedFileStore(baseDir string) (*LocalSealedFileStore, error) {
+	if err := os.MkdirAll(baseDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create secret store directory: %w", err)
+	}
+	return &LocalSealedFileStore{baseDir: baseDir}, nil
+}
+
+func (l *LocalSealedFileStore) path(key string) string {
+	return filepath.Join(l.baseDir, filepath.FromSlash(key)+".sealed")
+}
+
+// Get returns the sealed bytes stored for key, or nil, nil if no file
+// exists yet.
+func (l *LocalSealedFileStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(l.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return data, nil
+}
+
+// Put writes value to key's file, creating parent directories as needed.
+func (l *LocalSealedFileStore) Put(ctx context.Context, key string, value []byte) error {
+	path := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create secret directory: %w", err)
+	}
+	if err := os.WriteFile(path, value, 0o600); err != nil {
+		return fmt.Errorf("failed to write secret file: %w", err)
+	}
+	return nil
+}
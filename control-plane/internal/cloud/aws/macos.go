@@ -18,20 +18,33 @@ import (
 func (p *Provisioner) launchMacOSInstance(ctx context.Context, req *cloud.ProvisionRequest, instanceID string) (*cloud.CloudInstance, error) {
 	cfg := p.awsCfg.MacOS
 
+	imageID := cfg.AMIID
+	if req.ImageOverride != "" {
+		imageID = req.ImageOverride
+	}
+
 	// Acquire a dedicated host.
 	host, err := p.acquireDedicatedHost(ctx, instanceID)
 	if err != nil {
 		return nil, err
 	}
 
+	bootstrapToken, err := p.bootstrap.Mint(instanceID)
+	if err != nil {
+		_ = p.releaseDedicatedHost(ctx, host.ID)
+		return nil, fmt.Errorf("failed to mint bootstrap token: %w", err)
+	}
+
 	userData, err := RenderUserData("macos", UserDataParams{
 		ControlPlaneURL: p.serverURL,
-		APIKey:          p.apiKey,
+		BootstrapToken:  bootstrapToken,
 		InstanceID:      instanceID,
 		BotPackage:      req.BotPackage,
 		BotVersion:      req.BotVersion,
+		ExtraTags:       req.Tags,
 	})
 	if err != nil {
+		_ = p.releaseDedicatedHost(ctx, host.ID)
 		return nil, fmt.Errorf("failed to render macOS userdata: %w", err)
 	}
 
@@ -46,9 +59,18 @@ func (p *Provisioner) launchMacOSInstance(ctx context.Context, req *cloud.Provis
 	for k, v := range req.Tags {
 		tags = append(tags, ec2types.Tag{Key: aws.String("hanzo.ai/tag-" + k), Value: aws.String(v)})
 	}
+	if req.ConnectionMode != "" {
+		tags = append(tags, ec2types.Tag{Key: aws.String("hanzo.ai/connection-mode"), Value: aws.String(string(req.ConnectionMode))})
+	}
+	if req.AssumeRoleARN != "" {
+		tags = append(tags, ec2types.Tag{Key: aws.String("hanzo.ai/assume-role-arn"), Value: aws.String(req.AssumeRoleARN)})
+	}
+	if req.ExternalID != "" {
+		tags = append(tags, ec2types.Tag{Key: aws.String("hanzo.ai/assume-role-external-id"), Value: aws.String(req.ExternalID)})
+	}
 
 	input := &ec2.RunInstancesInput{
-		ImageId:      aws.String(cfg.AMIID),
+		ImageId:      aws.String(imageID),
 		InstanceType: ec2types.InstanceType(cfg.InstanceType),
 		MinCount:     aws.Int32(1),
 		MaxCount:     aws.Int32(1),
@@ -64,6 +86,17 @@ func (p *Provisioner) launchMacOSInstance(ctx context.Context, req *cloud.Provis
 		},
 	}
 
+	var generatedPrivateKeyPEM string
+	if req.ConnectionMode == cloud.ConnectionModeSSHDirect || req.ConnectionMode == cloud.ConnectionModeSSHViaSSM {
+		keyPair, privPEM, err := p.acquireKeyPair(ctx, req.TeamID, req.SSHPublicKey)
+		if err != nil {
+			_ = p.releaseDedicatedHost(ctx, host.ID)
+			return nil, fmt.Errorf("failed to acquire SSH key pair: %w", err)
+		}
+		input.KeyName = aws.String(keyPair.KeyName)
+		generatedPrivateKeyPEM = privPEM
+	}
+
 	if len(p.awsCfg.SubnetIDs) > 0 {
 		input.SubnetId = aws.String(p.awsCfg.SubnetIDs[0])
 	}
@@ -75,8 +108,14 @@ func (p *Provisioner) launchMacOSInstance(ctx context.Context, req *cloud.Provis
 			Name: aws.String(p.awsCfg.IAMInstanceProfile),
 		}
 	}
+	input.MetadataOptions = p.imdsv2MetadataOptions(defaultIMDSHopLimit)
 
-	out, err := p.clients.EC2.RunInstances(ctx, input)
+	var out *ec2.RunInstancesOutput
+	err = p.withRetry(ctx, "RunInstances", func() error {
+		var callErr error
+		out, callErr = p.clients.EC2.RunInstances(ctx, input)
+		return callErr
+	})
 	if err != nil {
 		// Release host on failure.
 		_ = p.releaseDedicatedHost(ctx, host.ID)
@@ -102,6 +141,17 @@ func (p *Provisioner) launchMacOSInstance(ctx context.Context, req *cloud.Provis
 		_ = p.store.UpdateDedicatedHost(ctx, host)
 	}
 
+	var connInfo *cloud.ConnectionInfo
+	if generatedPrivateKeyPEM != "" {
+		// The generated private key is only ever surfaced here, at the
+		// moment it's created; it isn't persisted, so this is the caller's
+		// one chance to capture it.
+		connInfo = &cloud.ConnectionInfo{
+			Protocol: cloud.ConnectionProtocolSSH,
+			Extra:    map[string]string{"private_key_pem": generatedPrivateKeyPEM},
+		}
+	}
+
 	now := time.Now().UTC()
 	return &cloud.CloudInstance{
 		ID:              instanceID,
@@ -110,13 +160,14 @@ func (p *Provisioner) launchMacOSInstance(ctx context.Context, req *cloud.Provis
 		Provider:        "aws",
 		InstanceID:      ec2ID,
 		InstanceType:    cfg.InstanceType,
-		ImageID:         cfg.AMIID,
+		ImageID:         imageID,
 		Region:          p.awsCfg.Region,
 		BotPackage:      req.BotPackage,
 		BotVersion:      req.BotVersion,
 		TeamID:          req.TeamID,
 		DedicatedHostID: host.HostID,
 		Tags:            req.Tags,
+		ConnectionInfo:  connInfo,
 		RequestedAt:     now,
 		CreatedAt:       now,
 		UpdatedAt:       now,
@@ -143,7 +194,9 @@ func (p *Provisioner) acquireDedicatedHost(ctx context.Context, instanceID strin
 		return host, nil
 	}
 
-	return nil, cloud.ErrNoAvailableHost
+	// Nothing available in the pool; auto-allocate a new host if config
+	// allows it and we're still under the region's MaxHosts ceiling.
+	return p.allocateNewHost(ctx, instanceID)
 }
 
 // releaseDedicatedHost marks a Dedicated Host as available.
@@ -0,0 +1,51 @@
+package aws
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds the Prometheus collectors used to instrument the
+// provisioner's EC2 API calls. A Metrics is always present on a Provisioner
+// (NewProvisioner defaults to one with a nil registerer) so call sites never
+// need to nil-check it.
+type Metrics struct {
+	requestsTotal     *prometheus.CounterVec
+	throttledSeconds  prometheus.Counter
+	cacheLookupsTotal *prometheus.CounterVec
+}
+
+// NewMetrics builds the EC2 API metrics and, if reg is non-nil, registers
+// them against it. Pass nil to get working counters that are simply never
+// exposed to a scraper.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hanzo_cloud_ec2_requests_total",
+			Help: "Total EC2 API calls made by the AWS provisioner, by operation and outcome.",
+		}, []string{"op", "outcome"}),
+		throttledSeconds: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "hanzo_cloud_ec2_throttled_seconds_total",
+			Help: "Cumulative seconds spent backed off waiting out EC2 API throttling.",
+		}),
+		cacheLookupsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hanzo_cloud_ec2_instance_cache_lookups_total",
+			Help: "Instance cache lookups by the AWS provisioner, by result (hit/miss).",
+		}, []string{"result"}),
+	}
+
+	if reg != nil {
+		reg.MustRegister(m.requestsTotal, m.throttledSeconds, m.cacheLookupsTotal)
+	}
+
+	return m
+}
+
+func (m *Metrics) recordRequest(op, outcome string) {
+	m.requestsTotal.WithLabelValues(op, outcome).Inc()
+}
+
+func (m *Metrics) recordThrottleWait(seconds float64) {
+	m.throttledSeconds.Add(seconds)
+}
+
+func (m *Metrics) recordCacheLookup(result string) {
+	m.cacheLookupsTotal.WithLabelValues(result).Inc()
+}
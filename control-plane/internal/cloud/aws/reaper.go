@@ -0,0 +1,106 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+)
+
+// hostReaperInterval is how often HostReaper checks for idle hosts to release.
+const hostReaperInterval = 5 * time.Minute
+
+// HostReaper releases auto-allocated macOS Dedicated Hosts back to AWS once
+// they've sat idle past MinAllocation+IdleGracePeriod, so the mac1/mac2
+// 24-hour minimum-billing window doesn't turn into an indefinite one.
+// Pre-seeded hosts (AWSMacOSConfig.DedicatedHostIDs) are never released by
+// this: whoever put them in config owns their lifecycle.
+type HostReaper struct {
+	provisioner *Provisioner
+	store       storage.StorageProvider
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewHostReaper creates a reaper for p's auto-allocated Dedicated Hosts.
+func NewHostReaper(p *Provisioner, store storage.StorageProvider) *HostReaper {
+	return &HostReaper{
+		provisioner: p,
+		store:       store,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start runs the reaper loop until Stop is called. Intended to be run in its
+// own goroutine by the caller.
+func (r *HostReaper) Start() {
+	ticker := time.NewTicker(hostReaperInterval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", hostReaperInterval).Msg("dedicated host reaper started")
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Info().Msg("dedicated host reaper stopped")
+			return
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+// Stop terminates the reaper loop.
+func (r *HostReaper) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+func (r *HostReaper) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	hosts, err := r.store.ListDedicatedHosts(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("host reaper: failed to list dedicated hosts")
+		return
+	}
+
+	grace := r.provisioner.awsCfg.MacOS.IdleGracePeriod
+	for _, host := range hosts {
+		if !host.AutoAllocated || host.State != "available" || host.ReleasedAt == nil {
+			continue
+		}
+
+		minEnd := host.AllocatedAt.Add(host.MinAllocation)
+		idleDeadline := host.ReleasedAt.Add(grace)
+		if time.Now().Before(minEnd) || time.Now().Before(idleDeadline) {
+			continue
+		}
+
+		err := r.provisioner.withRetry(ctx, "ReleaseHosts", func() error {
+			_, callErr := r.provisioner.clients.EC2.ReleaseHosts(ctx, &ec2.ReleaseHostsInput{
+				HostIds: []string{host.HostID},
+			})
+			return callErr
+		})
+		if err != nil {
+			log.Error().Err(err).Str("host_id", host.HostID).Msg("host reaper: failed to release dedicated host")
+			continue
+		}
+
+		host.State = "released"
+		if err := r.store.UpdateDedicatedHost(ctx, host); err != nil {
+			log.Error().Err(err).Str("host_id", host.HostID).Msg("host reaper: failed to record host release")
+			continue
+		}
+
+		log.Info().Str("host_id", host.HostID).Msg("released idle auto-allocated dedicated host back to AWS")
+	}
+}
@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	smithy "github.com/aws/smithy-go"
+)
+
+const (
+	throttleDelayMin   = 1 * time.Second
+	throttleDelayMax   = 60 * time.Second
+	throttleMaxRetries = 8
+)
+
+// throttleErrorCodes are the EC2 error codes that mean "back off and retry",
+// as opposed to a genuine failure the caller should see immediately.
+var throttleErrorCodes = map[string]bool{
+	"RequestLimitExceeded":         true,
+	"Throttling":                   true,
+	"InsufficientInstanceCapacity": true,
+}
+
+// withRetry runs fn, retrying with capped exponential backoff and jitter
+// whenever it fails with an AWS throttling error, and records request/
+// throttle metrics against p.metrics. op is the EC2 operation name, used
+// only as a metrics label (e.g. "RunInstances").
+func (p *Provisioner) withRetry(ctx context.Context, op string, fn func() error) error {
+	delay := throttleDelayMin
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			p.metrics.recordRequest(op, "success")
+			return nil
+		}
+
+		if !isThrottleError(err) || attempt >= throttleMaxRetries {
+			p.metrics.recordRequest(op, "error")
+			return err
+		}
+		p.metrics.recordRequest(op, "throttled")
+
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2+1)))
+		p.metrics.recordThrottleWait(wait.Seconds())
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if delay > throttleDelayMax {
+			delay = throttleDelayMax
+		}
+	}
+}
+
+// isThrottleError reports whether err is an AWS API error whose code
+// indicates the request should be retried with backoff.
+func isThrottleError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return throttleErrorCodes[apiErr.ErrorCode()]
+}
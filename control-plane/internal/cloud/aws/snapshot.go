@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/rs/zerolog/log"
+)
+
+// SnapshotInstance creates an AMI from the given instance's current disk
+// state, satisfying cloud.Snapshotter. Used by the image builder to turn a
+// provisioned, provisioned-over instance into a reusable golden image.
+// CreateImage is asynchronous; the returned image ID is usable immediately
+// as a future RunInstances ImageId, but will not itself be "available" until
+// AWS finishes the underlying snapshot.
+func (p *Provisioner) SnapshotInstance(ctx context.Context, instanceID string, tags map[string]string) (string, error) {
+	ec2Instance, err := p.describeInstanceByTag(ctx, instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	ec2ID := aws.ToString(ec2Instance.InstanceId)
+
+	imageTags := []ec2types.Tag{
+		{Key: aws.String("hanzo.ai/built-from"), Value: aws.String(instanceID)},
+	}
+	for k, v := range tags {
+		imageTags = append(imageTags, ec2types.Tag{Key: aws.String("hanzo.ai/tag-" + k), Value: aws.String(v)})
+	}
+
+	var out *ec2.CreateImageOutput
+	err = p.withRetry(ctx, "CreateImage", func() error {
+		var callErr error
+		out, callErr = p.clients.EC2.CreateImage(ctx, &ec2.CreateImageInput{
+			InstanceId: aws.String(ec2ID),
+			Name:       aws.String(fmt.Sprintf("hanzo-image-%s-%d", instanceID[:8], time.Now().UTC().Unix())),
+			TagSpecifications: []ec2types.TagSpecification{
+				{
+					ResourceType: ec2types.ResourceTypeImage,
+					Tags:         imageTags,
+				},
+			},
+		})
+		return callErr
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create image from instance %s: %w", ec2ID, err)
+	}
+
+	imageID := aws.ToString(out.ImageId)
+	log.Info().Str("ec2_id", ec2ID).Str("image_id", imageID).Msg("EC2 AMI creation started")
+	return imageID, nil
+}
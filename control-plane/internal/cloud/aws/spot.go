@@ -0,0 +1,122 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// InterruptionHandler is invoked when a watched instance's spot interruption
+// notice is observed.
+type InterruptionHandler func(ctx context.Context, instanceID string) error
+
+// SpotInterruptionPoller periodically checks each watched spot instance for
+// the ~2 minute spot interruption warning via its own instance metadata
+// service, since the control plane has no direct network path to the
+// instance and must go through SSM RunCommand.
+type SpotInterruptionPoller struct {
+	provisioner    *Provisioner
+	onInterruption InterruptionHandler
+	interval       time.Duration
+
+	mu       sync.Mutex
+	watched  map[string]string // cloud instance ID -> EC2 instance ID
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewSpotInterruptionPoller creates a poller for the given provisioner.
+func NewSpotInterruptionPoller(p *Provisioner, onInterruption InterruptionHandler, interval time.Duration) *SpotInterruptionPoller {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &SpotInterruptionPoller{
+		provisioner:    p,
+		onInterruption: onInterruption,
+		interval:       interval,
+		watched:        make(map[string]string),
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Watch adds a spot instance to the poll set.
+func (sp *SpotInterruptionPoller) Watch(instanceID, ec2ID string) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.watched[instanceID] = ec2ID
+}
+
+// StopWatching removes an instance from the poll set.
+func (sp *SpotInterruptionPoller) StopWatching(instanceID string) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	delete(sp.watched, instanceID)
+}
+
+// Start runs the poll loop until Stop is called.
+func (sp *SpotInterruptionPoller) Start() {
+	ticker := time.NewTicker(sp.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", sp.interval).Msg("spot interruption poller started")
+
+	for {
+		select {
+		case <-sp.stopCh:
+			log.Info().Msg("spot interruption poller stopped")
+			return
+		case <-ticker.C:
+			sp.tick()
+		}
+	}
+}
+
+// Stop terminates the poll loop.
+func (sp *SpotInterruptionPoller) Stop() {
+	sp.stopOnce.Do(func() {
+		close(sp.stopCh)
+	})
+}
+
+func (sp *SpotInterruptionPoller) tick() {
+	sp.mu.Lock()
+	watched := make(map[string]string, len(sp.watched))
+	for k, v := range sp.watched {
+		watched[k] = v
+	}
+	sp.mu.Unlock()
+
+	for instanceID, ec2ID := range watched {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		interrupted, err := sp.checkInterruption(ctx, ec2ID)
+		cancel()
+		if err != nil {
+			log.Debug().Err(err).Str("instance_id", instanceID).Msg("spot interruption check failed")
+			continue
+		}
+		if !interrupted {
+			continue
+		}
+
+		sp.StopWatching(instanceID)
+		if err := sp.onInterruption(context.Background(), instanceID); err != nil {
+			log.Error().Err(err).Str("instance_id", instanceID).Msg("spot interruption handler failed")
+		}
+	}
+}
+
+// checkInterruption asks the instance's SSM agent to probe its own instance
+// metadata service. The spot/instance-action endpoint only returns content
+// during the interruption warning window, and 404s otherwise.
+func (sp *SpotInterruptionPoller) checkInterruption(ctx context.Context, ec2ID string) (bool, error) {
+	out, err := RunCommand(ctx, sp.provisioner.clients.SSM, ec2ID,
+		`curl -s -o /dev/null -w "%{http_code}" http://169.254.169.254/latest/meta-data/spot/instance-action`,
+		"linux")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) == "200", nil
+}
@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/rs/zerolog/log"
+)
+
+// SpotPriceTracker periodically refreshes cached EC2 spot prices for the
+// instance types currently in spot use, so BillingAuthorizer and
+// CloudSummary can reflect live market rates instead of the static
+// discount estimate in ProviderHourlyCents.
+type SpotPriceTracker struct {
+	provisioner *Provisioner
+	interval    time.Duration
+
+	mu            sync.RWMutex
+	prices        map[string]int // instance type -> latest spot price in cents/hour
+	instanceTypes map[string]struct{}
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewSpotPriceTracker creates a price tracker for the given provisioner.
+func NewSpotPriceTracker(p *Provisioner, interval time.Duration) *SpotPriceTracker {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	return &SpotPriceTracker{
+		provisioner:   p,
+		interval:      interval,
+		prices:        make(map[string]int),
+		instanceTypes: make(map[string]struct{}),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Track adds an instance type to the set refreshed on each tick. Safe to
+// call repeatedly; duplicates are no-ops.
+func (t *SpotPriceTracker) Track(instanceType string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.instanceTypes[instanceType] = struct{}{}
+}
+
+// Prices returns a copy of the latest cached spot prices, keyed by
+// instance type, satisfying cloud.SpotPricer.
+func (t *SpotPriceTracker) Prices() map[string]int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make(map[string]int, len(t.prices))
+	for k, v := range t.prices {
+		out[k] = v
+	}
+	return out
+}
+
+// PriceCents returns the latest cached spot price for an instance type.
+func (t *SpotPriceTracker) PriceCents(instanceType string) (int, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	cents, ok := t.prices[instanceType]
+	return cents, ok
+}
+
+// Start runs the refresh loop until Stop is called.
+func (t *SpotPriceTracker) Start() {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", t.interval).Msg("spot price tracker started")
+
+	t.tick()
+	for {
+		select {
+		case <-t.stopCh:
+			log.Info().Msg("spot price tracker stopped")
+			return
+		case <-ticker.C:
+			t.tick()
+		}
+	}
+}
+
+// Stop terminates the refresh loop.
+func (t *SpotPriceTracker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stopCh)
+	})
+}
+
+func (t *SpotPriceTracker) tick() {
+	t.mu.RLock()
+	types := make([]string, 0, len(t.instanceTypes))
+	for it := range t.instanceTypes {
+		types = append(types, it)
+	}
+	t.mu.RUnlock()
+
+	if len(types) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, instanceType := range types {
+		cents, err := t.fetchPrice(ctx, instanceType)
+		if err != nil {
+			log.Warn().Err(err).Str("instance_type", instanceType).Msg("spot price tracker: failed to fetch price")
+			continue
+		}
+
+		t.mu.Lock()
+		t.prices[instanceType] = cents
+		t.mu.Unlock()
+	}
+}
+
+func (t *SpotPriceTracker) fetchPrice(ctx context.Context, instanceType string) (int, error) {
+	out, err := t.provisioner.clients.EC2.DescribeSpotPriceHistory(ctx, &ec2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       []ec2types.InstanceType{ec2types.InstanceType(instanceType)},
+		ProductDescriptions: []string{"Windows"},
+		MaxResults:          aws.Int32(1),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(out.SpotPriceHistory) == 0 {
+		return 0, nil
+	}
+
+	dollars, err := strconv.ParseFloat(aws.ToString(out.SpotPriceHistory[0].SpotPrice), 64)
+	if err != nil {
+		return 0, err
+	}
+
+	cents := int(dollars*100 + 0.5)
+	if cents < 1 {
+		cents = 1
+	}
+	return cents, nil
+}
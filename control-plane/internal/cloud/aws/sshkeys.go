@@ -0,0 +1,278 @@
+package aws
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/google/uuid"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+const sshKeyBits = 2048
+
+// acquireKeyPair returns the cached or freshly imported EC2 key pair to use
+// for an instance launch. When pubKey is a user-supplied OpenSSH public key
+// ("ssh-rsa AAAA..."), it is imported (or reused if already seen) instead of
+// generating one. Otherwise the team's most recently cached key pair is
+// reused; the very first launch for a team generates a new RSA key pair and
+// returns its PEM-encoded private key in privateKeyPEM so the caller can
+// hand it back to whoever needs to connect. Later calls return an empty
+// privateKeyPEM, since the private key itself is never persisted.
+func (p *Provisioner) acquireKeyPair(ctx context.Context, teamID, pubKey string) (keyPair *cloud.SSHKeyPair, privateKeyPEM string, err error) {
+	if p.store == nil {
+		return nil, "", fmt.Errorf("storage required for SSH key pair management")
+	}
+
+	if pubKey != "" {
+		wire, fp, fpSHA1, err := parseOpenSSHRSAPublicKey(pubKey)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid SSH public key: %w", err)
+		}
+
+		if existing, err := p.store.GetSSHKeyPairByFingerprint(ctx, fp); err == nil {
+			return existing, "", nil
+		}
+
+		kp, err := p.importKeyPair(ctx, teamID, wire, pubKey, fp, fpSHA1)
+		return kp, "", err
+	}
+
+	if existing, err := p.store.GetSSHKeyPairByTeam(ctx, teamID); err == nil {
+		return existing, "", nil
+	}
+
+	priv, err := rsa.GenerateKey(rand.Reader, sshKeyBits)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate SSH key pair: %w", err)
+	}
+
+	wire := sshWireFormatRSAPublicKey(&priv.PublicKey)
+	fp, err := md5Fingerprint(&priv.PublicKey)
+	if err != nil {
+		return nil, "", err
+	}
+	fpSHA1 := sha1Fingerprint(wire)
+
+	pubKeyAuthorized := "ssh-rsa " + base64.StdEncoding.EncodeToString(wire)
+	kp, err := p.importKeyPair(ctx, teamID, wire, pubKeyAuthorized, fp, fpSHA1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	privDER := x509.MarshalPKCS1PrivateKey(priv)
+	privPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: privDER}))
+
+	return kp, privPEM, nil
+}
+
+// importKeyPair imports wire (the raw OpenSSH wire-format public key bytes)
+// into EC2 and caches the resulting KeyName in storage keyed by fingerprint.
+func (p *Provisioner) importKeyPair(ctx context.Context, teamID string, wire []byte, publicKeyPEM, fingerprint, fingerprintSHA1 string) (*cloud.SSHKeyPair, error) {
+	keyName := fmt.Sprintf("hanzo-ssh-%s", strings.ReplaceAll(fingerprint, ":", "")[:16])
+
+	err := p.withRetry(ctx, "ImportKeyPair", func() error {
+		_, callErr := p.clients.EC2.ImportKeyPair(ctx, &ec2.ImportKeyPairInput{
+			KeyName:           awssdk.String(keyName),
+			PublicKeyMaterial: wire,
+		})
+		return callErr
+	})
+	if err != nil && !isKeyPairAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to import SSH key pair: %w", err)
+	}
+
+	kp := &cloud.SSHKeyPair{
+		ID:              uuid.New().String(),
+		TeamID:          teamID,
+		KeyName:         keyName,
+		Fingerprint:     fingerprint,
+		FingerprintSHA1: fingerprintSHA1,
+		PublicKeyPEM:    publicKeyPEM,
+	}
+	if err := p.store.CreateSSHKeyPair(ctx, kp); err != nil {
+		return nil, fmt.Errorf("failed to cache SSH key pair: %w", err)
+	}
+
+	return kp, nil
+}
+
+// sshWireFormatRSAPublicKey encodes pub in the OpenSSH wire format used both
+// as EC2's ImportKeyPair PublicKeyMaterial and as the input to the OpenSSH
+// SHA-1 fingerprint.
+func sshWireFormatRSAPublicKey(pub *rsa.PublicKey) []byte {
+	var buf []byte
+	buf = appendSSHString(buf, []byte("ssh-rsa"))
+	buf = appendSSHMPInt(buf, big.NewInt(int64(pub.E)))
+	buf = appendSSHMPInt(buf, pub.N)
+	return buf
+}
+
+func appendSSHString(buf, s []byte) []byte {
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(s)))
+	return append(append(buf, length...), s...)
+}
+
+func appendSSHMPInt(buf []byte, n *big.Int) []byte {
+	b := n.Bytes()
+	// A leading 0x80+ byte must be padded with a zero so it isn't read as negative.
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return appendSSHString(buf, b)
+}
+
+// md5Fingerprint computes the EC2-style fingerprint AWS reports for an
+// imported key pair: the MD5 hash of the DER-encoded SubjectPublicKeyInfo,
+// formatted as colon-separated hex pairs.
+func md5Fingerprint(pub *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	sum := md5.Sum(der)
+	return hexColonFingerprint(sum[:]), nil
+}
+
+// sha1Fingerprint computes the classic OpenSSH fingerprint: the SHA-1 hash
+// of the key's wire-format bytes, colon-separated hex (the format
+// `ssh-keygen -l -E sha1` reports).
+func sha1Fingerprint(wire []byte) string {
+	sum := sha1.Sum(wire)
+	return hexColonFingerprint(sum[:])
+}
+
+func hexColonFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// parseOpenSSHRSAPublicKey parses an authorized_keys-style "ssh-rsa AAAA..."
+// line and returns its wire-format bytes plus both fingerprints.
+func parseOpenSSHRSAPublicKey(line string) (wire []byte, md5fp, sha1fp string, err error) {
+	fields := strings.Fields(strings.TrimSpace(line))
+	if len(fields) < 2 || fields[0] != "ssh-rsa" {
+		return nil, "", "", fmt.Errorf("only ssh-rsa keys are supported")
+	}
+
+	wire, err = base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to decode key material: %w", err)
+	}
+
+	pub, err := parseSSHRSAWireFormat(wire)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	md5fp, err = md5Fingerprint(pub)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return wire, md5fp, sha1Fingerprint(wire), nil
+}
+
+func parseSSHRSAWireFormat(wire []byte) (*rsa.PublicKey, error) {
+	algo, rest, err := readSSHString(wire)
+	if err != nil {
+		return nil, err
+	}
+	if string(algo) != "ssh-rsa" {
+		return nil, fmt.Errorf("unsupported key algorithm: %s", algo)
+	}
+
+	eBytes, rest, err := readSSHString(rest)
+	if err != nil {
+		return nil, err
+	}
+	nBytes, _, err := readSSHString(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(big.Int).SetBytes(eBytes)
+	n := new(big.Int).SetBytes(nBytes)
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func readSSHString(buf []byte) (value, rest []byte, err error) {
+	if len(buf) < 4 {
+		return nil, nil, fmt.Errorf("truncated SSH wire data")
+	}
+	length := binary.BigEndian.Uint32(buf[:4])
+	if uint32(len(buf)-4) < length {
+		return nil, nil, fmt.Errorf("truncated SSH wire data")
+	}
+	return buf[4 : 4+length], buf[4+length:], nil
+}
+
+// isKeyPairAlreadyExists reports whether err is EC2's response to importing
+// a key pair whose name is already registered, which we treat as success
+// since the cached KeyName is what we care about recovering.
+func isKeyPairAlreadyExists(err error) bool {
+	return strings.Contains(err.Error(), "InvalidKeyPair.Duplicate")
+}
+
+// getSSHViaSSMConnectionInfo returns a bastion-free SSH endpoint tunneled
+// through an SSM port-forwarding session, for instances on private subnets
+// with no route to a public IP.
+func (p *Provisioner) getSSHViaSSMConnectionInfo(ec2ID string) *cloud.ConnectionInfo {
+	extra := buildSSMConnectionExtra(ec2ID, p.awsCfg.Region)
+	extra["tunnel_cmd"] = strings.Join([]string{
+		"aws", "ssm", "start-session",
+		"--target", ec2ID,
+		"--region", p.awsCfg.Region,
+		"--document-name", "AWS-StartPortForwardingSession",
+		"--parameters", `'{"portNumber":["22"],"localPortNumber":["2222"]}'`,
+	}, " ")
+
+	return &cloud.ConnectionInfo{
+		Protocol: cloud.ConnectionProtocolSSH,
+		Host:     "localhost",
+		Port:     2222,
+		Extra:    extra,
+	}
+}
+
+// getSSHDirectConnectionInfo returns SSH connection info for host using
+// teamID's cached key pair. Only surfaces a KeyName/fingerprint for the
+// caller to match against whatever private key they hold; the private key
+// itself was only ever returned once, at generation time.
+func (p *Provisioner) getSSHDirectConnectionInfo(ctx context.Context, teamID, host string) (*cloud.ConnectionInfo, error) {
+	conn := &cloud.ConnectionInfo{
+		Protocol: cloud.ConnectionProtocolSSH,
+		Host:     host,
+		Port:     22,
+		Extra:    map[string]string{},
+	}
+
+	if p.store == nil {
+		return conn, nil
+	}
+
+	kp, err := p.store.GetSSHKeyPairByTeam(ctx, teamID)
+	if err != nil {
+		return conn, nil
+	}
+
+	conn.Extra["key_name"] = kp.KeyName
+	conn.Extra["fingerprint"] = kp.Fingerprint
+	conn.Extra["fingerprint_sha1"] = kp.FingerprintSHA1
+	return conn, nil
+}
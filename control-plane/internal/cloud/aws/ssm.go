@@ -12,52 +12,300 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// RunCommand executes a command on an EC2 instance via SSM.
-func RunCommand(ctx context.Context, ssmClient *ssm.Client, instanceID, command, platform string) (string, error) {
+// inlineOutputLimit is the size SSM truncates StandardOutputContent and
+// StandardErrorContent at. A command whose output might exceed it needs
+// OutputS3BucketName/CloudWatchOutputConfig configured on the send, which
+// is what StreamOutputConfig controls.
+const inlineOutputLimit = 24 * 1024
+
+// commandPollMinBackoff and commandPollMaxBackoff bound RunCommandStream's
+// adaptive poll interval: it starts quick so short commands return promptly,
+// then backs off so a long-running command doesn't hammer GetCommandInvocation.
+const (
+	commandPollMinBackoff = 500 * time.Millisecond
+	commandPollMaxBackoff = 5 * time.Second
+)
+
+// CommandEventKind identifies what a CommandEvent carries.
+type CommandEventKind int
+
+const (
+	// CommandEventStdout carries a slice of stdout not yet delivered.
+	CommandEventStdout CommandEventKind = iota
+	// CommandEventStderr carries a slice of stderr not yet delivered.
+	CommandEventStderr
+	// CommandEventStatus carries a terminal status transition and closes
+	// the event channel once delivered.
+	CommandEventStatus
+)
+
+// CommandEvent is one incremental update from RunCommandStream: either a
+// slice of output or the command's terminal status.
+type CommandEvent struct {
+	Kind CommandEventKind
+	// Data is the new output slice for CommandEventStdout/CommandEventStderr,
+	// starting at Offset into the command's full stdout/stderr.
+	Data   string
+	Offset int
+	// Status is set on CommandEventStatus.
+	Status ssmtypes.CommandInvocationStatus
+	// Err is set on CommandEventStatus if the stream ended abnormally (ctx
+	// cancellation, a poll error that never resolved).
+	Err error
+}
+
+// StreamOutputConfig tells RunCommandStream where to route output that may
+// exceed inlineOutputLimit. A zero value means inline-only: output larger
+// than SSM's cap is silently truncated by SSM itself, same as the old
+// RunCommand. Setting OutputBucket (and optionally CloudWatchLogGroup)
+// makes RunCommandStream configure the send for S3/CloudWatch output, the
+// same way the AWS CLI's `--output-s3-bucket-name` flag does.
+type StreamOutputConfig struct {
+	OutputBucket       string
+	OutputKeyPrefix    string
+	CloudWatchLogGroup string
+}
+
+// RunCommandStream executes command on instanceID via SSM and returns a
+// channel of CommandEvents as the invocation progresses: CommandEventStdout/
+// CommandEventStderr for output as it's observed, then a single
+// CommandEventStatus with the terminal status before the channel closes.
+// Cancelling ctx calls CancelCommand on the instance and ends the stream with
+// CommandEventStatus{Status: Cancelled, Err: ctx.Err()}.
+//
+// Output beyond inlineOutputLimit is only available once out.OutputBucket is
+// set; RunCommandStream itself only reads GetCommandInvocation's inline
+// StandardOutputContent/StandardErrorContent fields; reading the remainder of
+// a large invocation back out of S3 is left to the caller (out.OutputBucket
+// is passed straight through to SendCommandInput so the data lands there,
+// but nothing in this package has an S3 client to page it back in).
+func RunCommandStream(ctx context.Context, ssmClient *ssm.Client, instanceID, command, platform string, out StreamOutputConfig) (<-chan CommandEvent, error) {
 	docName := "AWS-RunShellScript"
 	if platform == "windows" {
 		docName = "AWS-RunPowerShellScript"
 	}
 
-	out, err := ssmClient.SendCommand(ctx, &ssm.SendCommandInput{
+	input := &ssm.SendCommandInput{
 		InstanceIds:  []string{instanceID},
 		DocumentName: aws.String(docName),
 		Parameters: map[string][]string{
 			"commands": {command},
 		},
 		TimeoutSeconds: int32Ptr(120),
-	})
+	}
+	if out.OutputBucket != "" {
+		input.OutputS3BucketName = aws.String(out.OutputBucket)
+		if out.OutputKeyPrefix != "" {
+			input.OutputS3KeyPrefix = aws.String(out.OutputKeyPrefix)
+		}
+	}
+	if out.CloudWatchLogGroup != "" {
+		input.CloudWatchOutputConfig = &ssmtypes.CloudWatchOutputConfig{
+			CloudWatchLogGroupName:  aws.String(out.CloudWatchLogGroup),
+			CloudWatchOutputEnabled: true,
+		}
+	}
+
+	sendOut, err := ssmClient.SendCommand(ctx, input)
 	if err != nil {
-		return "", fmt.Errorf("SSM send command failed: %w", err)
+		return nil, fmt.Errorf("SSM send command failed: %w", err)
 	}
 
-	commandID := *out.Command.CommandId
+	commandID := aws.ToString(sendOut.Command.CommandId)
 	log.Debug().Str("command_id", commandID).Str("instance", instanceID).Msg("SSM command sent")
 
-	// Poll for result.
-	for i := 0; i < 60; i++ {
-		time.Sleep(2 * time.Second)
+	events := make(chan CommandEvent, 16)
+	go streamCommandInvocation(ctx, ssmClient, commandID, instanceID, events)
+	return events, nil
+}
+
+// streamCommandInvocation polls commandID on instanceID with adaptive
+// backoff, emitting CommandEvents to events until it reaches a terminal
+// status or ctx is cancelled, then closes events.
+func streamCommandInvocation(ctx context.Context, ssmClient *ssm.Client, commandID, instanceID string, events chan<- CommandEvent) {
+	defer close(events)
+
+	backoff := commandPollMinBackoff
+	var stdoutOffset, stderrOffset int
+
+	for {
+		select {
+		case <-ctx.Done():
+			cancelCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if _, err := ssmClient.CancelCommand(cancelCtx, &ssm.CancelCommandInput{
+				CommandId:   aws.String(commandID),
+				InstanceIds: []string{instanceID},
+			}); err != nil {
+				log.Warn().Err(err).Str("command_id", commandID).Msg("failed to cancel SSM command")
+			}
+			cancel()
+			events <- CommandEvent{Kind: CommandEventStatus, Status: ssmtypes.CommandInvocationStatusCancelled, Err: ctx.Err()}
+			return
+		case <-time.After(backoff):
+		}
 
 		inv, err := ssmClient.GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
 			CommandId:  aws.String(commandID),
 			InstanceId: aws.String(instanceID),
 		})
 		if err != nil {
-			continue // may not be ready yet
+			// Not registered yet, or a transient API error; back off and retry.
+			if backoff < commandPollMaxBackoff {
+				backoff *= 2
+				if backoff > commandPollMaxBackoff {
+					backoff = commandPollMaxBackoff
+				}
+			}
+			continue
+		}
+
+		if stdout := aws.ToString(inv.StandardOutputContent); len(stdout) > stdoutOffset {
+			events <- CommandEvent{Kind: CommandEventStdout, Data: stdout[stdoutOffset:], Offset: stdoutOffset}
+			stdoutOffset = len(stdout)
+		}
+		if stderr := aws.ToString(inv.StandardErrorContent); len(stderr) > stderrOffset {
+			events <- CommandEvent{Kind: CommandEventStderr, Data: stderr[stderrOffset:], Offset: stderrOffset}
+			stderrOffset = len(stderr)
 		}
 
 		switch inv.Status {
-		case ssmtypes.CommandInvocationStatusSuccess:
-			return aws.ToString(inv.StandardOutputContent), nil
-		case ssmtypes.CommandInvocationStatusFailed,
+		case ssmtypes.CommandInvocationStatusSuccess,
+			ssmtypes.CommandInvocationStatusFailed,
 			ssmtypes.CommandInvocationStatusTimedOut,
 			ssmtypes.CommandInvocationStatusCancelled:
-			stderr := aws.ToString(inv.StandardErrorContent)
-			return "", fmt.Errorf("SSM command %s: %s", inv.Status, stderr)
+			events <- CommandEvent{Kind: CommandEventStatus, Status: inv.Status}
+			return
+		}
+
+		if backoff < commandPollMaxBackoff {
+			backoff *= 2
+			if backoff > commandPollMaxBackoff {
+				backoff = commandPollMaxBackoff
+			}
 		}
 	}
+}
 
-	return "", fmt.Errorf("SSM command timed out waiting for result")
+// RunCommandStreamFanOut runs command on every instance in instanceIDs as a
+// single SSM send (so they share one CommandId) and returns one
+// RunCommandStream-shaped channel per instance, keyed by instance ID.
+func RunCommandStreamFanOut(ctx context.Context, ssmClient *ssm.Client, instanceIDs []string, command, platform string, out StreamOutputConfig) (map[string]<-chan CommandEvent, error) {
+	docName := "AWS-RunShellScript"
+	if platform == "windows" {
+		docName = "AWS-RunPowerShellScript"
+	}
+
+	input := &ssm.SendCommandInput{
+		InstanceIds:  instanceIDs,
+		DocumentName: aws.String(docName),
+		Parameters: map[string][]string{
+			"commands": {command},
+		},
+		TimeoutSeconds: int32Ptr(120),
+	}
+	if out.OutputBucket != "" {
+		input.OutputS3BucketName = aws.String(out.OutputBucket)
+		if out.OutputKeyPrefix != "" {
+			input.OutputS3KeyPrefix = aws.String(out.OutputKeyPrefix)
+		}
+	}
+	if out.CloudWatchLogGroup != "" {
+		input.CloudWatchOutputConfig = &ssmtypes.CloudWatchOutputConfig{
+			CloudWatchLogGroupName:  aws.String(out.CloudWatchLogGroup),
+			CloudWatchOutputEnabled: true,
+		}
+	}
+
+	sendOut, err := ssmClient.SendCommand(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("SSM send command failed: %w", err)
+	}
+	commandID := aws.ToString(sendOut.Command.CommandId)
+	log.Debug().Str("command_id", commandID).Int("instances", len(instanceIDs)).Msg("SSM command sent to multiple instances")
+
+	streams := make(map[string]<-chan CommandEvent, len(instanceIDs))
+	for _, instanceID := range instanceIDs {
+		events := make(chan CommandEvent, 16)
+		streams[instanceID] = events
+		go streamCommandInvocation(ctx, ssmClient, commandID, instanceID, events)
+	}
+	return streams, nil
+}
+
+// RunCommand executes command on instanceID via SSM and blocks until it
+// finishes, returning the concatenated stdout. It's a synchronous shim over
+// RunCommandStream for callers that just want a result; new code that cares
+// about incremental output, large output, or cancellation should call
+// RunCommandStream directly.
+func RunCommand(ctx context.Context, ssmClient *ssm.Client, instanceID, command, platform string) (string, error) {
+	events, err := RunCommandStream(ctx, ssmClient, instanceID, command, platform, StreamOutputConfig{})
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr strings.Builder
+	var status ssmtypes.CommandInvocationStatus
+	var streamErr error
+	for ev := range events {
+		switch ev.Kind {
+		case CommandEventStdout:
+			stdout.WriteString(ev.Data)
+		case CommandEventStderr:
+			stderr.WriteString(ev.Data)
+		case CommandEventStatus:
+			status = ev.Status
+			streamErr = ev.Err
+		}
+	}
+
+	switch status {
+	case ssmtypes.CommandInvocationStatusSuccess:
+		return stdout.String(), nil
+	case "":
+		if streamErr != nil {
+			return "", streamErr
+		}
+		return "", fmt.Errorf("SSM command ended without a final status")
+	default:
+		return "", fmt.Errorf("SSM command %s: %s", status, stderr.String())
+	}
+}
+
+// SessionStream holds the WebSocket stream details StartSession gets back
+// from SSM for an interactive session, for a caller (e.g. the control
+// plane's in-browser terminal) to speak the Session Manager data-channel
+// protocol over. Actually speaking that protocol - framing, handshake,
+// keepalives - isn't implemented here; it belongs to whatever proxies
+// StreamURL to the browser.
+type SessionStream struct {
+	SessionID  string
+	StreamURL  string
+	TokenValue string
+}
+
+// StartSession opens an interactive Session Manager session on instanceID,
+// equivalent to `aws ssm start-session --target instanceID`, and returns the
+// stream details needed to open the session's WebSocket.
+func StartSession(ctx context.Context, ssmClient *ssm.Client, instanceID string) (*SessionStream, error) {
+	out, err := ssmClient.StartSession(ctx, &ssm.StartSessionInput{
+		Target: aws.String(instanceID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SSM start session failed: %w", err)
+	}
+	return &SessionStream{
+		SessionID:  aws.ToString(out.SessionId),
+		StreamURL:  aws.ToString(out.StreamUrl),
+		TokenValue: aws.ToString(out.TokenValue),
+	}, nil
+}
+
+// TerminateSession ends a session StartSession opened.
+func TerminateSession(ctx context.Context, ssmClient *ssm.Client, sessionID string) error {
+	_, err := ssmClient.TerminateSession(ctx, &ssm.TerminateSessionInput{
+		SessionId: aws.String(sessionID),
+	})
+	return err
 }
 
 // WaitForSSMReady polls until the instance registers with SSM.
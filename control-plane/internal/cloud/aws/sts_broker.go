@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// assumeRoleSessionDuration is how long each vended session token is valid
+// for. CredentialRefresher rotates well before this expires.
+const assumeRoleSessionDuration = 3600
+
+// STSCredentialBroker implements cloud.CredentialBroker by exchanging the
+// control plane's own credentials for a short-lived session via STS
+// AssumeRole, scoped to AWSConfig.AgentAssumeRoleARN.
+type STSCredentialBroker struct {
+	sts     *sts.Client
+	roleARN string
+}
+
+// NewSTSCredentialBroker creates a new STS-backed credential broker.
+func NewSTSCredentialBroker(clients *Clients, cfg cloud.AWSConfig) *STSCredentialBroker {
+	return &STSCredentialBroker{
+		sts:     clients.STS,
+		roleARN: cfg.AgentAssumeRoleARN,
+	}
+}
+
+// IssueCredentials assumes AgentAssumeRoleARN with a session name derived
+// from instanceID and returns the resulting short-lived credentials.
+func (b *STSCredentialBroker) IssueCredentials(ctx context.Context, instanceID string) (*cloud.Credentials, error) {
+	if b.roleARN == "" {
+		return nil, fmt.Errorf("aws: AgentAssumeRoleARN is not configured")
+	}
+
+	out, err := b.sts.AssumeRole(ctx, &sts.AssumeRoleInput{
+		RoleArn:         awssdk.String(b.roleARN),
+		RoleSessionName: awssdk.String("hanzo-agent-" + instanceID),
+		DurationSeconds: awssdk.Int32(assumeRoleSessionDuration),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume role for instance %s: %w", instanceID, err)
+	}
+
+	creds := out.Credentials
+	return &cloud.Credentials{
+		AccessKeyID:     awssdk.ToString(creds.AccessKeyId),
+		SecretAccessKey: awssdk.ToString(creds.SecretAccessKey),
+		SessionToken:    awssdk.ToString(creds.SessionToken),
+		Expiry:          awssdk.ToTime(creds.Expiration),
+	}, nil
+}
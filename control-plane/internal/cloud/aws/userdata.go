@@ -10,18 +10,73 @@ import (
 // UserDataParams holds the template parameters for instance bootstrap scripts.
 type UserDataParams struct {
 	ControlPlaneURL string
-	APIKey          string
-	InstanceID      string
-	BotPackage      string
-	BotVersion      string
+	// BootstrapToken is a short-lived, single-use JWT (see
+	// cloud.BootstrapIssuer) the instance exchanges at
+	// POST /api/v1/cloud/bootstrap/exchange for its real
+	// HANZO_AGENTS_API_KEY. The real key is never baked into userdata,
+	// which any process on the instance can read via instance metadata or
+	// the console.
+	BootstrapToken string
+	InstanceID     string
+	BotPackage     string
+	BotVersion     string
+
+	// HopLimit is the IMDSv2 response hop limit
+	// (http-put-response-hop-limit) the instance is expected to run with.
+	// Containerized workloads that proxy metadata requests through an extra
+	// network hop need this above the default of 1; left zero,
+	// RenderUserData fills in defaultIMDSHopLimit. The bootstrap templates
+	// do not reference it; the limit itself is set on the EC2 launch by
+	// Provisioner.imdsv2MetadataOptions.
+	HopLimit int32
+	// ExtraTags are written into the instance's environment as
+	// HANZO_AGENTS_TAG_<KEY>=<value> pairs, for bot code that wants to read
+	// back tags it was launched with without an API call.
+	ExtraTags map[string]string
 }
 
+// defaultIMDSHopLimit is used when UserDataParams.HopLimit is left zero.
+const defaultIMDSHopLimit = 2
+
+// imdsv2BashHelper fetches an IMDSv2 session token and aborts the
+// bootstrap script if the metadata service won't issue one, since IMDSv1
+// (no token required) is a known SSRF vector and bootstrap shouldn't
+// silently fall back to it.
+const imdsv2BashHelper = `
+IMDS_TOKEN=$(curl -sf -X PUT "http://169.254.169.254/latest/api/token" \
+  -H "X-aws-ec2-metadata-token-ttl-seconds: 21600")
+if [ -z "$IMDS_TOKEN" ]; then
+  echo "bootstrap: failed to obtain IMDSv2 token, refusing to fall back to IMDSv1" >&2
+  exit 1
+fi
+imds() { curl -sf -H "X-aws-ec2-metadata-token: $IMDS_TOKEN" "http://169.254.169.254/latest/meta-data/$1"; }
+
+export HANZO_AGENTS_EC2_INSTANCE_ID="$(imds instance-id)"
+export HANZO_AGENTS_EC2_REGION="$(imds placement/region)"
+`
+
+// exchangeBootstrapTokenBash trades the one-time bootstrap token for the
+// real agent API key, which never otherwise appears in userdata.
+const exchangeBootstrapTokenBash = `
+HANZO_AGENTS_API_KEY=$(curl -sf -X POST "{{.ControlPlaneURL}}/api/v1/cloud/bootstrap/exchange" \
+  -H "Content-Type: application/json" \
+  -d "{\"token\":\"{{.BootstrapToken}}\"}" | python3 -c 'import json,sys; print(json.load(sys.stdin)["api_key"])')
+if [ -z "$HANZO_AGENTS_API_KEY" ]; then
+  echo "bootstrap: failed to exchange bootstrap token for an API key" >&2
+  exit 1
+fi
+export HANZO_AGENTS_API_KEY
+`
+
 var linuxBootstrapTmpl = template.Must(template.New("linux").Parse(`#!/bin/bash
 set -euo pipefail
-
+` + imdsv2BashHelper + `
 export HANZO_AGENTS_SERVER_URL="{{.ControlPlaneURL}}"
-export HANZO_AGENTS_API_KEY="{{.APIKey}}"
 export HANZO_AGENTS_INSTANCE_ID="{{.InstanceID}}"
+` + exchangeBootstrapTokenBash + `
+{{- range $k, $v := .ExtraTags}}
+export HANZO_AGENTS_TAG_{{$k}}="{{$v}}"
+{{- end}}
 
 # Install Python and agent SDK
 if ! command -v python3 &>/dev/null; then
@@ -37,10 +92,13 @@ hanzo-agents run "{{.BotPackage}}" &
 
 var macOSBootstrapTmpl = template.Must(template.New("macos").Parse(`#!/bin/bash
 set -euo pipefail
-
+` + imdsv2BashHelper + `
 export HANZO_AGENTS_SERVER_URL="{{.ControlPlaneURL}}"
-export HANZO_AGENTS_API_KEY="{{.APIKey}}"
 export HANZO_AGENTS_INSTANCE_ID="{{.InstanceID}}"
+` + exchangeBootstrapTokenBash + `
+{{- range $k, $v := .ExtraTags}}
+export HANZO_AGENTS_TAG_{{$k}}="{{$v}}"
+{{- end}}
 
 # Enable Screen Sharing for VNC access
 sudo /System/Library/CoreServices/RemoteManagement/ARDAgent.app/Contents/Resources/kickstart \
@@ -57,14 +115,35 @@ hanzo-agents run "{{.BotPackage}}" &
 var windowsBootstrapTmpl = template.Must(template.New("windows").Parse(`<powershell>
 $ErrorActionPreference = "Stop"
 
+$imdsToken = (Invoke-RestMethod -Method PUT -Uri "http://169.254.169.254/latest/api/token" -Headers @{"X-aws-ec2-metadata-token-ttl-seconds" = "21600"})
+if (-not $imdsToken) {
+    Write-Error "bootstrap: failed to obtain IMDSv2 token, refusing to fall back to IMDSv1"
+    exit 1
+}
+function Get-IMDS($path) {
+    Invoke-RestMethod -Headers @{"X-aws-ec2-metadata-token" = $imdsToken} -Uri "http://169.254.169.254/latest/meta-data/$path"
+}
+$env:HANZO_AGENTS_EC2_INSTANCE_ID = Get-IMDS "instance-id"
+$env:HANZO_AGENTS_EC2_REGION = Get-IMDS "placement/region"
+
 $env:HANZO_AGENTS_SERVER_URL = "{{.ControlPlaneURL}}"
-$env:HANZO_AGENTS_API_KEY = "{{.APIKey}}"
 $env:HANZO_AGENTS_INSTANCE_ID = "{{.InstanceID}}"
 
+$exchangeBody = @{ token = "{{.BootstrapToken}}" } | ConvertTo-Json
+$exchangeResp = Invoke-RestMethod -Method POST -Uri "{{.ControlPlaneURL}}/api/v1/cloud/bootstrap/exchange" -ContentType "application/json" -Body $exchangeBody
+$env:HANZO_AGENTS_API_KEY = $exchangeResp.api_key
+if (-not $env:HANZO_AGENTS_API_KEY) {
+    Write-Error "bootstrap: failed to exchange bootstrap token for an API key"
+    exit 1
+}
+
 # Set persistent env vars
 [Environment]::SetEnvironmentVariable("HANZO_AGENTS_SERVER_URL", "{{.ControlPlaneURL}}", "Machine")
-[Environment]::SetEnvironmentVariable("HANZO_AGENTS_API_KEY", "{{.APIKey}}", "Machine")
+[Environment]::SetEnvironmentVariable("HANZO_AGENTS_API_KEY", $env:HANZO_AGENTS_API_KEY, "Machine")
 [Environment]::SetEnvironmentVariable("HANZO_AGENTS_INSTANCE_ID", "{{.InstanceID}}", "Machine")
+{{- range $k, $v := .ExtraTags}}
+[Environment]::SetEnvironmentVariable("HANZO_AGENTS_TAG_{{$k}}", "{{$v}}", "Machine")
+{{- end}}
 
 # Install Python if not present
 if (-not (Get-Command python -ErrorAction SilentlyContinue)) {
@@ -96,6 +175,10 @@ func RenderUserData(platform string, params UserDataParams) (string, error) {
 		return "", fmt.Errorf("unsupported platform for userdata: %s", platform)
 	}
 
+	if params.HopLimit == 0 {
+		params.HopLimit = defaultIMDSHopLimit
+	}
+
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, params); err != nil {
 		return "", fmt.Errorf("failed to render userdata template: %w", err)
@@ -2,7 +2,6 @@ package aws
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"time"
 
@@ -18,17 +17,28 @@ import (
 func (p *Provisioner) launchWindowsInstance(ctx context.Context, req *cloud.ProvisionRequest, instanceID string) (*cloud.CloudInstance, error) {
 	cfg := p.awsCfg.Windows
 
+	imageID := cfg.AMIID
+	if req.ImageOverride != "" {
+		imageID = req.ImageOverride
+	}
+
 	instanceType := ec2types.InstanceType(cfg.DefaultInstanceType)
 	if req.InstanceType != "" {
 		instanceType = ec2types.InstanceType(req.InstanceType)
 	}
 
+	bootstrapToken, err := p.bootstrap.Mint(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint bootstrap token: %w", err)
+	}
+
 	userData, err := RenderUserData("windows", UserDataParams{
 		ControlPlaneURL: p.serverURL,
-		APIKey:          p.apiKey,
+		BootstrapToken:  bootstrapToken,
 		InstanceID:      instanceID,
 		BotPackage:      req.BotPackage,
 		BotVersion:      req.BotVersion,
+		ExtraTags:       req.Tags,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to render Windows userdata: %w", err)
@@ -44,9 +54,15 @@ func (p *Provisioner) launchWindowsInstance(ctx context.Context, req *cloud.Prov
 	for k, v := range req.Tags {
 		tags = append(tags, ec2types.Tag{Key: aws.String("hanzo.ai/tag-" + k), Value: aws.String(v)})
 	}
+	if req.AssumeRoleARN != "" {
+		tags = append(tags, ec2types.Tag{Key: aws.String("hanzo.ai/assume-role-arn"), Value: aws.String(req.AssumeRoleARN)})
+	}
+	if req.ExternalID != "" {
+		tags = append(tags, ec2types.Tag{Key: aws.String("hanzo.ai/assume-role-external-id"), Value: aws.String(req.ExternalID)})
+	}
 
 	input := &ec2.RunInstancesInput{
-		ImageId:      aws.String(cfg.AMIID),
+		ImageId:      aws.String(imageID),
 		InstanceType: instanceType,
 		MinCount:     aws.Int32(1),
 		MaxCount:     aws.Int32(1),
@@ -71,11 +87,32 @@ func (p *Provisioner) launchWindowsInstance(ctx context.Context, req *cloud.Prov
 			Name: aws.String(p.awsCfg.IAMInstanceProfile),
 		}
 	}
+	input.MetadataOptions = p.imdsv2MetadataOptions(defaultIMDSHopLimit)
+	input.InstanceMarketOptions = spotMarketOptions(req)
+	if req.UseSpot && p.spotPrices != nil {
+		p.spotPrices.Track(string(instanceType))
+	}
 
-	// Enable password retrieval via GetPasswordData
-	input.KeyName = aws.String("hanzo-agent-windows")
+	// Enable password retrieval via GetPasswordData. Prefer the managed key
+	// pair so the control plane can decrypt the password server-side;
+	// without a KeyManager, fall back to the fixed, unmanaged key name used
+	// before (the password is then only ever returned encrypted).
+	keyName := windowsKeyName
+	if p.keyManager != nil {
+		managed, err := p.keyManager.EnsureKeyPair(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to ensure Windows key pair: %w", err)
+		}
+		keyName = managed
+	}
+	input.KeyName = aws.String(keyName)
 
-	out, err := p.clients.EC2.RunInstances(ctx, input)
+	var out *ec2.RunInstancesOutput
+	err = p.withRetry(ctx, "RunInstances", func() error {
+		var callErr error
+		out, callErr = p.clients.EC2.RunInstances(ctx, input)
+		return callErr
+	})
 	if err != nil {
 		return nil, &cloud.ProvisionError{
 			InstanceID: instanceID,
@@ -92,6 +129,10 @@ func (p *Provisioner) launchWindowsInstance(ctx context.Context, req *cloud.Prov
 		Str("type", string(instanceType)).
 		Msg("Windows EC2 instance launched")
 
+	if req.UseSpot && p.interruptionWatcher != nil {
+		p.interruptionWatcher.Track(ec2ID)
+	}
+
 	now := time.Now().UTC()
 	return &cloud.CloudInstance{
 		ID:           instanceID,
@@ -100,7 +141,7 @@ func (p *Provisioner) launchWindowsInstance(ctx context.Context, req *cloud.Prov
 		Provider:     "aws",
 		InstanceID:   ec2ID,
 		InstanceType: string(instanceType),
-		ImageID:      cfg.AMIID,
+		ImageID:      imageID,
 		Region:       p.awsCfg.Region,
 		BotPackage:   req.BotPackage,
 		BotVersion:   req.BotVersion,
@@ -112,7 +153,12 @@ func (p *Provisioner) launchWindowsInstance(ctx context.Context, req *cloud.Prov
 	}, nil
 }
 
-// getWindowsConnectionInfo returns RDP connection info for a Windows instance.
+// getWindowsConnectionInfo returns RDP connection info for a Windows
+// instance. GetPasswordData returns an empty PasswordData until Windows
+// finishes generating the administrator password (usually 4-15 minutes
+// after launch) — this is a single best-effort check, not a blocking wait;
+// WindowsPasswordPoller is what notices the transition to ready and fires
+// EventWindowsPasswordReady.
 func (p *Provisioner) getWindowsConnectionInfo(ctx context.Context, ec2ID, publicIP string) (*cloud.ConnectionInfo, error) {
 	conn := &cloud.ConnectionInfo{
 		Protocol: cloud.ConnectionProtocolRDP,
@@ -121,19 +167,40 @@ func (p *Provisioner) getWindowsConnectionInfo(ctx context.Context, ec2ID, publi
 		Username: "Administrator",
 		Extra:    buildSSMConnectionExtra(ec2ID, p.awsCfg.Region),
 	}
-
-	// Try to retrieve the password.
-	passOut, err := p.clients.EC2.GetPasswordData(ctx, &ec2.GetPasswordDataInput{
-		InstanceId: aws.String(ec2ID),
+	conn.Extra["password_ready"] = "false"
+
+	var passOut *ec2.GetPasswordDataOutput
+	err := p.withRetry(ctx, "GetPasswordData", func() error {
+		var callErr error
+		passOut, callErr = p.clients.EC2.GetPasswordData(ctx, &ec2.GetPasswordDataInput{
+			InstanceId: aws.String(ec2ID),
+		})
+		return callErr
 	})
-	if err == nil && passOut.PasswordData != nil && *passOut.PasswordData != "" {
-		// Password is base64-encoded and RSA-encrypted with the key pair.
-		// We store the encrypted blob; client needs the private key to decrypt.
-		decoded, err := base64.StdEncoding.DecodeString(*passOut.PasswordData)
-		if err == nil {
-			conn.Extra["encrypted_password"] = base64.StdEncoding.EncodeToString(decoded)
-		}
+	if err != nil || passOut.PasswordData == nil || *passOut.PasswordData == "" {
+		return conn, nil
+	}
+
+	conn.Extra["password_ready"] = "true"
+
+	// PasswordData is base64-encoded and RSA-encrypted with the launch key
+	// pair. Without a KeyManager we can only hand back the encrypted blob;
+	// the caller then needs the private key themselves to decrypt it.
+	if p.keyManager == nil {
+		conn.Extra["encrypted_password"] = *passOut.PasswordData
+		return conn, nil
+	}
+
+	password, err := p.keyManager.DecryptPassword(ctx, *passOut.PasswordData)
+	if err != nil {
+		log.Warn().Err(err).Str("ec2_id", ec2ID).Msg("failed to decrypt Windows RDP password")
+		conn.Extra["encrypted_password"] = *passOut.PasswordData
+		return conn, nil
 	}
 
+	// Password is returned plaintext here; it's the HTTP handler's job to
+	// strip this field from the response unless the caller holds the
+	// cloud:read_credentials scope.
+	conn.Password = password
 	return conn, nil
 }
@@ -0,0 +1,441 @@
+// Package azure implements the cloud.CloudProvisioner interface for Azure
+// Resource Manager (ARM) virtual machines.
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+const armBaseURL = "https://management.azure.com"
+const armAPIVersion = "2023-09-01"
+
+func init() {
+	cloud.RegisterProviderFactory("azure", func(cfg any) (cloud.CloudProvisioner, error) {
+		azCfg, ok := cfg.(cloud.AzureConfig)
+		if !ok {
+			return nil, fmt.Errorf("azure: unexpected config type %T", cfg)
+		}
+		return NewProvisioner(azCfg), nil
+	})
+}
+
+// Provisioner implements cloud.CloudProvisioner for Azure VMs, provisioned
+// via ARM VM deployments wired into the configured resource group's
+// VNet/subnet/NSG.
+type Provisioner struct {
+	cfg    cloud.AzureConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewProvisioner creates a new Azure ARM provisioner.
+func NewProvisioner(cfg cloud.AzureConfig) *Provisioner {
+	return &Provisioner{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *Provisioner) ProviderName() string { return "azure" }
+
+// CreateInstance deploys a new VM with a public IP, NIC, and disk wired into
+// the configured VNet/subnet/NSG.
+func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRequest) (*cloud.CloudInstance, error) {
+	instanceID := uuid.New().String()
+	vmName := fmt.Sprintf("hanzo-bot-%s", instanceID[:8])
+
+	vmSize := p.cfg.VMSize
+	if req.InstanceType != "" {
+		vmSize = req.InstanceType
+	}
+
+	nicID, publicIPName, err := p.createNetworkStack(ctx, vmName)
+	if err != nil {
+		return nil, &cloud.ProvisionError{InstanceID: instanceID, Platform: cloud.PlatformLinux, Provider: "azure", Err: err}
+	}
+
+	body := map[string]interface{}{
+		"location": p.cfg.Location,
+		"tags": map[string]string{
+			"hanzo-cloud-instance": instanceID,
+			"hanzo-team":           req.TeamID,
+			"hanzo-bot-package":    req.BotPackage,
+		},
+		"properties": map[string]interface{}{
+			"hardwareProfile": map[string]string{"vmSize": vmSize},
+			"storageProfile": map[string]interface{}{
+				"imageReference": parseImageReference(p.cfg.ImageReference),
+			},
+			"networkProfile": map[string]interface{}{
+				"networkInterfaces": []map[string]interface{}{
+					{"id": nicID, "properties": map[string]bool{"primary": true}},
+				},
+			},
+		},
+	}
+
+	var result armResource
+	path := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s",
+		p.cfg.SubscriptionID, p.cfg.ResourceGroup, vmName)
+	if err := p.doRequest(ctx, http.MethodPut, path, body, &result); err != nil {
+		return nil, &cloud.ProvisionError{InstanceID: instanceID, Platform: cloud.PlatformLinux, Provider: "azure", Err: err}
+	}
+
+	log.Info().Str("vm", vmName).Str("public_ip", publicIPName).Str("instance_id", instanceID).Msg("Azure VM deployment started")
+
+	now := time.Now().UTC()
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        cloud.InstanceStateProvisioning,
+		Provider:     "azure",
+		InstanceID:   vmName,
+		InstanceType: vmSize,
+		ImageID:      p.cfg.ImageReference,
+		Region:       p.cfg.Location,
+		BotPackage:   req.BotPackage,
+		BotVersion:   req.BotVersion,
+		TeamID:       req.TeamID,
+		Tags:         req.Tags,
+		RequestedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// createNetworkStack provisions a public IP and NIC wired into the
+// configured VNet/subnet/NSG and returns the NIC's resource ID.
+func (p *Provisioner) createNetworkStack(ctx context.Context, vmName string) (nicID, publicIPName string, err error) {
+	publicIPName = vmName + "-ip"
+	pipPath := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/publicIPAddresses/%s",
+		p.cfg.SubscriptionID, p.cfg.ResourceGroup, publicIPName)
+	pipBody := map[string]interface{}{
+		"location":   p.cfg.Location,
+		"properties": map[string]string{"publicIPAllocationMethod": "Dynamic"},
+	}
+	if err := p.doRequest(ctx, http.MethodPut, pipPath, pipBody, nil); err != nil {
+		return "", "", fmt.Errorf("failed to create public IP: %w", err)
+	}
+
+	subnetID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s/subnets/%s",
+		p.cfg.SubscriptionID, p.cfg.ResourceGroup, p.cfg.VNetName, p.cfg.SubnetName)
+	nsgID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkSecurityGroups/%s",
+		p.cfg.SubscriptionID, p.cfg.ResourceGroup, p.cfg.NSGName)
+
+	nicName := vmName + "-nic"
+	nicPath := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkInterfaces/%s",
+		p.cfg.SubscriptionID, p.cfg.ResourceGroup, nicName)
+	nicBody := map[string]interface{}{
+		"location": p.cfg.Location,
+		"properties": map[string]interface{}{
+			"networkSecurityGroup": map[string]string{"id": nsgID},
+			"ipConfigurations": []map[string]interface{}{
+				{
+					"name": "ipconfig1",
+					"properties": map[string]interface{}{
+						"subnet":                    map[string]string{"id": subnetID},
+						"publicIPAddress":           map[string]string{"id": pipPath},
+						"privateIPAllocationMethod": "Dynamic",
+					},
+				},
+			},
+		},
+	}
+
+	var nic armResource
+	if err := p.doRequest(ctx, http.MethodPut, nicPath, nicBody, &nic); err != nil {
+		return "", "", fmt.Errorf("failed to create network interface: %w", err)
+	}
+
+	return nic.ID, publicIPName, nil
+}
+
+// GetInstance returns the current state of a VM by tag.
+func (p *Provisioner) GetInstance(ctx context.Context, instanceID string) (*cloud.CloudInstance, error) {
+	vm, err := p.findVMByTag(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return vmToInstance(instanceID, vm), nil
+}
+
+// ListInstances returns VMs matching filters.
+func (p *Provisioner) ListInstances(ctx context.Context, filters cloud.InstanceFilters) ([]*cloud.CloudInstance, error) {
+	var result struct {
+		Value []armVM `json:"value"`
+	}
+	path := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines", p.cfg.SubscriptionID, p.cfg.ResourceGroup)
+	if err := p.doRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list Azure VMs: %w", err)
+	}
+
+	var instances []*cloud.CloudInstance
+	for i := range result.Value {
+		vm := &result.Value[i]
+		instanceID := vm.Tags["hanzo-cloud-instance"]
+		if instanceID == "" {
+			continue
+		}
+		if filters.TeamID != nil && vm.Tags["hanzo-team"] != *filters.TeamID {
+			continue
+		}
+		ci := vmToInstance(instanceID, vm)
+		if filters.State != nil && ci.State != *filters.State {
+			continue
+		}
+		instances = append(instances, ci)
+	}
+	return instances, nil
+}
+
+func (p *Provisioner) StartInstance(ctx context.Context, instanceID string) error {
+	vm, err := p.findVMByTag(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	return p.vmAction(ctx, vm.Name, "start")
+}
+
+func (p *Provisioner) StopInstance(ctx context.Context, instanceID string) error {
+	vm, err := p.findVMByTag(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	return p.vmAction(ctx, vm.Name, "deallocate")
+}
+
+// TerminateInstance deletes the VM. The NIC and public IP it owns are left
+// for the caller to garbage-collect along with the resource group, matching
+// how the AWS provisioner leaves EBS/ENI cleanup to EC2 termination.
+func (p *Provisioner) TerminateInstance(ctx context.Context, instanceID string) error {
+	vm, err := p.findVMByTag(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s",
+		p.cfg.SubscriptionID, p.cfg.ResourceGroup, vm.Name)
+	if err := p.doRequest(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete Azure VM %s: %w", vm.Name, err)
+	}
+
+	log.Info().Str("vm", vm.Name).Str("instance_id", instanceID).Msg("Azure VM deleted")
+	return nil
+}
+
+// GetConnectionInfo returns SSH connection details for the VM.
+func (p *Provisioner) GetConnectionInfo(ctx context.Context, instanceID string) (*cloud.ConnectionInfo, error) {
+	vm, err := p.findVMByTag(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloud.ConnectionInfo{
+		Protocol: cloud.ConnectionProtocolSSH,
+		Host:     vm.PublicIP,
+		Port:     22,
+		Username: "hanzo",
+	}, nil
+}
+
+// ExecuteCommand is not supported directly over the ARM API; callers should
+// connect over the SSH info returned by GetConnectionInfo.
+func (p *Provisioner) ExecuteCommand(ctx context.Context, instanceID, command string) (*cloud.CommandResult, error) {
+	return nil, fmt.Errorf("azure: ExecuteCommand requires an SSH connection, see GetConnectionInfo")
+}
+
+// GetLogs is not supported directly over the ARM API.
+func (p *Provisioner) GetLogs(ctx context.Context, instanceID string, lines int) (string, error) {
+	return "", fmt.Errorf("azure: GetLogs requires an SSH connection, see GetConnectionInfo")
+}
+
+func (p *Provisioner) vmAction(ctx context.Context, vmName, action string) error {
+	path := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s/%s",
+		p.cfg.SubscriptionID, p.cfg.ResourceGroup, vmName, action)
+	if err := p.doRequest(ctx, http.MethodPost, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to %s Azure VM %s: %w", action, vmName, err)
+	}
+	return nil
+}
+
+func (p *Provisioner) findVMByTag(ctx context.Context, instanceID string) (*armVM, error) {
+	var result struct {
+		Value []armVM `json:"value"`
+	}
+	path := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines", p.cfg.SubscriptionID, p.cfg.ResourceGroup)
+	if err := p.doRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to look up Azure VM: %w", err)
+	}
+	for i := range result.Value {
+		if result.Value[i].Tags["hanzo-cloud-instance"] == instanceID {
+			return &result.Value[i], nil
+		}
+	}
+	return nil, cloud.ErrInstanceNotFound
+}
+
+// doRequest issues an authenticated ARM request, acquiring a fresh
+// client-credentials token when the cached one is near expiry.
+func (p *Provisioner) doRequest(ctx context.Context, method, path string, payload interface{}, out interface{}) error {
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire Azure AD token: %w", err)
+	}
+
+	var bodyReader io.Reader
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		bodyReader = bytes.NewReader(buf)
+	}
+
+	reqURL := armBaseURL + path + "?api-version=" + armAPIVersion
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, bodyReader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ARM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ARM API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// accessToken returns a cached AAD client-credentials token, refreshing it
+// when it is within a minute of expiry.
+func (p *Provisioner) accessToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Add(time.Minute).Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	tokenURL := fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", p.cfg.TenantID)
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+		"scope":         {"https://management.azure.com/.default"},
+	}
+
+	resp, err := p.client.PostForm(tokenURL, form)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("AAD token endpoint returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode AAD token response: %w", err)
+	}
+
+	p.token = tokenResp.AccessToken
+	p.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	return p.token, nil
+}
+
+// armResource is the minimal shape of an ARM PUT/GET response we read back.
+type armResource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// armVM is the subset of the ARM virtualMachines response we care about.
+type armVM struct {
+	Name       string            `json:"name"`
+	Tags       map[string]string `json:"tags"`
+	PublicIP   string            `json:"-"`
+	Location   string            `json:"location"`
+	Properties struct {
+		ProvisioningState string `json:"provisioningState"`
+		HardwareProfile   struct {
+			VMSize string `json:"vmSize"`
+		} `json:"hardwareProfile"`
+	} `json:"properties"`
+}
+
+func vmToInstance(instanceID string, vm *armVM) *cloud.CloudInstance {
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        armProvisioningStateToState(vm.Properties.ProvisioningState),
+		Provider:     "azure",
+		InstanceID:   vm.Name,
+		InstanceType: vm.Properties.HardwareProfile.VMSize,
+		Region:       vm.Location,
+		PublicIP:     vm.PublicIP,
+		TeamID:       vm.Tags["hanzo-team"],
+		BotPackage:   vm.Tags["hanzo-bot-package"],
+	}
+}
+
+// parseImageReference splits a "publisher:offer:sku:version" string (the
+// convention used by `az vm image list`) into an ARM imageReference object.
+func parseImageReference(ref string) map[string]string {
+	parts := strings.SplitN(ref, ":", 4)
+	for len(parts) < 4 {
+		parts = append(parts, "latest")
+	}
+	return map[string]string{
+		"publisher": parts[0],
+		"offer":     parts[1],
+		"sku":       parts[2],
+		"version":   parts[3],
+	}
+}
+
+func armProvisioningStateToState(state string) cloud.InstanceState {
+	switch state {
+	case "Creating", "Updating":
+		return cloud.InstanceStateProvisioning
+	case "Succeeded":
+		return cloud.InstanceStateRunning
+	case "Deallocating", "Deallocated":
+		return cloud.InstanceStateStopped
+	case "Deleting":
+		return cloud.InstanceStateTerminated
+	default:
+		return cloud.InstanceStateFailed
+	}
+}
@@ -9,12 +9,33 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/services"
 )
 
+// HoldService is the subset of *services.BillingService CloudManager uses to
+// reserve an instance's estimated cost up front (PlaceHold) and settle it
+// once the instance's actual cost is known (CaptureHold/ReleaseHold),
+// instead of only debiting after the fact. Separate from BillingAuthorizer,
+// which checks budget admission against the older bootnode billing API;
+// HoldService is unset (nil) unless a caller wires one in via
+// SetHoldService, in which case CloudManager uses both side by side.
+type HoldService interface {
+	PlaceHold(ctx context.Context, params services.HoldParams) (string, error)
+	CaptureHold(ctx context.Context, holdID string, actualCents int64) (string, error)
+	ReleaseHold(ctx context.Context, holdID string) error
+	// CheckCoverage draws down a prepaid package balance for the given
+	// user/platform/team, so CreateInstance can skip PlaceHold entirely
+	// when a package fully covers the run's estimated cost.
+	CheckCoverage(ctx context.Context, userID, platform, teamID string, estimatedMinutes float64, estimatedTokens int64) (bool, error)
+}
+
 // BillingAuthorizer checks billing authorization before provisioning.
 type BillingAuthorizer interface {
 	// AuthorizeProvisioning checks if a team can provision an instance.
-	AuthorizeProvisioning(ctx context.Context, teamID string, platform Platform, instanceType string) (*BillingAuth, error)
+	// expectedLifetimeHours is used to project the instance's total cost
+	// (hourly rate * expected lifetime) against the team's remaining budget.
+	AuthorizeProvisioning(ctx context.Context, teamID string, platform Platform, instanceType string, expectedLifetimeHours float64) (*BillingAuth, error)
 	// ReportUsage reports compute hours for billing.
 	ReportUsage(ctx context.Context, instanceID string, platform Platform, hours float64, hourlyCents int) error
 	// GetTeamQuota returns the cloud compute quota for a team.
@@ -23,13 +44,28 @@ type BillingAuthorizer interface {
 
 // BillingAuth is the result of an authorization check.
 type BillingAuth struct {
-	Authorized     bool   `json:"authorized"`
-	Tier           string `json:"tier"`
-	HourlyCents    int    `json:"hourly_rate_cents"`
-	Reason         string `json:"reason,omitempty"`
-	BillingAccount string `json:"billing_account_id,omitempty"`
+	Authorized     bool     `json:"authorized"`
+	Tier           string   `json:"tier"`
+	HourlyCents    int      `json:"hourly_rate_cents"`
+	Reason         string   `json:"reason,omitempty"`
+	BillingAccount string   `json:"billing_account_id,omitempty"`
+	// Warnings holds at most one entry, for the highest soft budget
+	// threshold (50/80/95%) the team's projected spend crosses. Provisioning is still authorized when only warnings are
+	// set; Authorized is only set to false once the hard cap is exceeded.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
+// EnforcementMode controls what BudgetMonitor does when a team's hard
+// budget cap is breached.
+type EnforcementMode string
+
+const (
+	EnforcementOff       EnforcementMode = "off"
+	EnforcementWarn      EnforcementMode = "warn"
+	EnforcementStop      EnforcementMode = "stop"
+	EnforcementTerminate EnforcementMode = "terminate"
+)
+
 // CloudQuota holds the cloud compute quota for a team/tier.
 type CloudQuota struct {
 	Tier               string `json:"tier"`
@@ -50,6 +86,10 @@ type BillingConfig struct {
 	Enabled    bool   `yaml:"enabled" mapstructure:"enabled"`
 	ServiceURL string `yaml:"service_url" mapstructure:"service_url"` // bootnode API URL
 	APIKey     string `yaml:"api_key" mapstructure:"api_key"`
+	// EnforcementMode controls what BudgetMonitor does when a team's hard
+	// budget cap is breached: off (no action), warn (events only, default),
+	// stop, or terminate the team's oldest running instance.
+	EnforcementMode EnforcementMode `yaml:"enforcement_mode" mapstructure:"enforcement_mode"`
 }
 
 // HTTPBillingClient calls the bootnode billing API over HTTP.
@@ -70,11 +110,12 @@ func NewHTTPBillingClient(baseURL, apiKey string) *HTTPBillingClient {
 	}
 }
 
-func (c *HTTPBillingClient) AuthorizeProvisioning(ctx context.Context, teamID string, platform Platform, instanceType string) (*BillingAuth, error) {
-	body, _ := json.Marshal(map[string]string{
-		"team_id":       teamID,
-		"platform":      string(platform),
-		"instance_type": instanceType,
+func (c *HTTPBillingClient) AuthorizeProvisioning(ctx context.Context, teamID string, platform Platform, instanceType string, expectedLifetimeHours float64) (*BillingAuth, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"team_id":                 teamID,
+		"platform":                string(platform),
+		"instance_type":           instanceType,
+		"expected_lifetime_hours": expectedLifetimeHours,
 	})
 
 	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/billing/cloud/authorize", bytes.NewReader(body))
@@ -95,13 +136,55 @@ func (c *HTTPBillingClient) AuthorizeProvisioning(ctx context.Context, teamID st
 		return nil, fmt.Errorf("billing response decode failed: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK || !auth.Authorized {
 		return &auth, nil
 	}
 
+	// The bootnode API decides authorization and the base hourly rate, but
+	// hard/soft budget admission is evaluated here against the team's quota
+	// so it stays consistent with BudgetMonitor's burn-rate accounting.
+	quota, err := c.GetTeamQuota(ctx, teamID)
+	if err != nil {
+		log.Warn().Err(err).Str("team", teamID).Msg("budget admission: quota lookup failed, skipping projected-cost check")
+		return &auth, nil
+	}
+
+	auth.Warnings = budgetWarnings(quota, auth.HourlyCents, expectedLifetimeHours)
+
+	if quota.MonthlyBudgetCents > 0 {
+		projected := int(float64(auth.HourlyCents) * expectedLifetimeHours)
+		if quota.UsedBudgetCents+projected > quota.MonthlyBudgetCents {
+			auth.Authorized = false
+			auth.Reason = "projected cost would exceed monthly budget cap"
+		}
+	}
+
 	return &auth, nil
 }
 
+// budgetWarnings returns one warning for the highest of the 50/80/95% soft
+// thresholds of a team's monthly budget that is crossed once the requested
+// instance's projected cost (hourly rate * expected lifetime) is added to
+// what's already been used. Lower thresholds crossed as well get no warning
+// of their own.
+func budgetWarnings(quota *CloudQuota, hourlyCents int, expectedLifetimeHours float64) []string {
+	if quota.MonthlyBudgetCents <= 0 {
+		return nil
+	}
+
+	projected := int(float64(hourlyCents) * expectedLifetimeHours)
+	pct := (quota.UsedBudgetCents + projected) * 100 / quota.MonthlyBudgetCents
+
+	var warnings []string
+	for _, threshold := range []int{95, 80, 50} {
+		if pct >= threshold {
+			warnings = append(warnings, fmt.Sprintf("projected spend crosses %d%% of monthly budget", threshold))
+			break
+		}
+	}
+	return warnings
+}
+
 func (c *HTTPBillingClient) ReportUsage(ctx context.Context, instanceID string, platform Platform, hours float64, hourlyCents int) error {
 	body, _ := json.Marshal(map[string]interface{}{
 		"instance_id":      instanceID,
@@ -155,7 +238,7 @@ func (c *HTTPBillingClient) GetTeamQuota(ctx context.Context, teamID string) (*C
 // NoopBillingClient allows all provisioning without billing checks (for dev/testing).
 type NoopBillingClient struct{}
 
-func (n *NoopBillingClient) AuthorizeProvisioning(_ context.Context, _ string, platform Platform, _ string) (*BillingAuth, error) {
+func (n *NoopBillingClient) AuthorizeProvisioning(_ context.Context, _ string, platform Platform, _ string, _ float64) (*BillingAuth, error) {
 	rate := platformHourlyCents(platform)
 	return &BillingAuth{
 		Authorized:  true,
@@ -178,7 +261,7 @@ func (n *NoopBillingClient) GetTeamQuota(_ context.Context, _ string) (*CloudQuo
 	}, nil
 }
 
-// platformHourlyCents returns the default hourly rate in cents for a platform.
+// platformHourlyCents returns the default AWS/K8s hourly rate in cents for a platform.
 func platformHourlyCents(p Platform) int {
 	switch p {
 	case PlatformMacOS:
@@ -191,3 +274,38 @@ func platformHourlyCents(p Platform) int {
 		return 10
 	}
 }
+
+// spotDiscountFactor is the fraction of the on-demand rate charged for spot/
+// preemptible instances, roughly matching typical cloud spot market discounts.
+const spotDiscountFactor = 0.3
+
+// ProviderHourlyCents returns the default hourly rate in cents for a given
+// provider name (as stored on CloudInstance.Provider), falling back to the
+// AWS/K8s platform-based table for the original two providers. isSpot
+// applies the spot/preemptible discount for instances provisioned with
+// Metadata.spot=true.
+func ProviderHourlyCents(provider string, p Platform, isSpot bool) int {
+	rate := providerOnDemandHourlyCents(provider, p)
+	if isSpot {
+		rate = int(float64(rate) * spotDiscountFactor)
+		if rate < 1 {
+			rate = 1
+		}
+	}
+	return rate
+}
+
+func providerOnDemandHourlyCents(provider string, p Platform) int {
+	switch provider {
+	case "azure":
+		return 8 // $0.08/hr Standard_D2s_v3
+	case "gcp":
+		return 7 // $0.07/hr e2-standard-2
+	case "digitalocean":
+		return 4 // $0.04/hr s-2vcpu-4gb
+	case "linode":
+		return 4 // $0.04/hr g6-standard-2
+	default:
+		return platformHourlyCents(p)
+	}
+}
@@ -0,0 +1,127 @@
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// maxBootstrapTokenTTL caps how long a minted bootstrap token can remain
+// valid, regardless of BootstrapConfig.TokenTTL, so a misconfigured control
+// plane can't hand out long-lived tokens that defeat the point of making
+// them single-use.
+const maxBootstrapTokenTTL = 10 * time.Minute
+
+var (
+	// ErrBootstrapTokenUsed means the token's signature and expiry checked
+	// out, but it was already redeemed once before.
+	ErrBootstrapTokenUsed = errors.New("cloud: bootstrap token already used")
+	// ErrBootstrapTokenInvalid covers a bad signature, malformed token, or
+	// expired token - anything that fails verification outright.
+	ErrBootstrapTokenInvalid = errors.New("cloud: bootstrap token invalid or expired")
+)
+
+// bootstrapClaims is the payload of a bootstrap token: just enough to name
+// the instance it was minted for, riding on the standard registered claims
+// for expiry and its single-use jti.
+type bootstrapClaims struct {
+	InstanceID string `json:"instance_id"`
+	jwt.RegisteredClaims
+}
+
+// BootstrapIssuer mints and redeems the short-lived, single-use bootstrap
+// JWTs RenderUserData embeds in place of the real agent API key, so the key
+// itself never lands in EC2 userdata - which is readable by any process on
+// the instance - in plaintext. POST /api/v1/cloud/bootstrap/exchange
+// redeems a token for the real key exactly once; a second exchange attempt
+// with the same token is rejected even though it hasn't expired yet.
+type BootstrapIssuer struct {
+	secret []byte
+	ttl    time.Duration
+
+	usedMu sync.Mutex
+	used   map[string]time.Time // jti -> expiry, for eviction
+}
+
+// NewBootstrapIssuer creates a BootstrapIssuer signing tokens with secret.
+// ttl is clamped to maxBootstrapTokenTTL; zero or negative defaults to it.
+func NewBootstrapIssuer(secret string, ttl time.Duration) *BootstrapIssuer {
+	if ttl <= 0 || ttl > maxBootstrapTokenTTL {
+		ttl = maxBootstrapTokenTTL
+	}
+	return &BootstrapIssuer{
+		secret: []byte(secret),
+		ttl:    ttl,
+		used:   make(map[string]time.Time),
+	}
+}
+
+// Mint signs a bootstrap token scoped to instanceID, valid for the
+// issuer's configured TTL.
+func (b *BootstrapIssuer) Mint(instanceID string) (string, error) {
+	now := time.Now().UTC()
+	claims := bootstrapClaims{
+		InstanceID: instanceID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   instanceID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(b.ttl)),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(b.secret)
+	if err != nil {
+		return "", fmt.Errorf("cloud: failed to sign bootstrap token: %w", err)
+	}
+	return signed, nil
+}
+
+// Exchange validates tokenString's signature and expiry and ensures it
+// hasn't been redeemed before, returning the instance ID it was minted
+// for. The jti is remembered for the rest of the token's validity window,
+// so a captured-but-unexpired token still can't be redeemed twice.
+func (b *BootstrapIssuer) Exchange(tokenString string) (string, error) {
+	var claims bootstrapClaims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Method.Alg())
+		}
+		return b.secret, nil
+	})
+	if err != nil || !token.Valid {
+		return "", ErrBootstrapTokenInvalid
+	}
+
+	b.usedMu.Lock()
+	defer b.usedMu.Unlock()
+	b.evictExpiredLocked()
+
+	if _, ok := b.used[claims.ID]; ok {
+		return "", ErrBootstrapTokenUsed
+	}
+
+	var exp time.Time
+	if claims.ExpiresAt != nil {
+		exp = claims.ExpiresAt.Time
+	}
+	b.used[claims.ID] = exp
+
+	return claims.InstanceID, nil
+}
+
+// evictExpiredLocked drops redeemed jtis whose token would have expired on
+// its own by now, so the used map doesn't grow without bound. Callers must
+// hold usedMu.
+func (b *BootstrapIssuer) evictExpiredLocked() {
+	now := time.Now().UTC()
+	for jti, exp := range b.used {
+		if now.After(exp) {
+			delete(b.used, jti)
+		}
+	}
+}
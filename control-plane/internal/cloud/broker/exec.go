@@ -0,0 +1,124 @@
+package broker
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// ExecGateway upgrades a browser WebSocket connection into an interactive
+// exec session on an instance, via its provisioner's StreamingExecutor
+// capability (K8s SPDY exec, AWS SSM). Separate from Gateway since an exec
+// session speaks a small JSON-framed protocol of our own rather than
+// proxying the Guacamole protocol to guacd.
+type ExecGateway struct {
+	manager *cloud.CloudManager
+}
+
+// NewExecGateway creates a new exec gateway.
+func NewExecGateway(manager *cloud.CloudManager) *ExecGateway {
+	return &ExecGateway{manager: manager}
+}
+
+// execRequest is the JSON the client sends as its first WebSocket message to
+// start the session.
+type execRequest struct {
+	Command []string `json:"command"`
+}
+
+// ServeWS handles GET /api/ui/v1/cloud/instances/:id/exec/ws. The client's
+// first message is an execRequest; every message after that is written to
+// the process's stdin. The server writes back {"stream":"stdout"|"stderr",
+// "data":"..."} frames as output arrives, followed by a single
+// {"stream":"exit","code":N} frame once the process exits.
+func (g *ExecGateway) ServeWS(c *gin.Context) {
+	ctx := c.Request.Context()
+	instanceID := c.Param("id")
+
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Warn().Err(err).Str("instance_id", instanceID).Msg("exec: websocket upgrade failed")
+		return
+	}
+	defer ws.Close()
+
+	_, msg, err := ws.ReadMessage()
+	if err != nil {
+		return
+	}
+
+	var req execRequest
+	if err := json.Unmarshal(msg, &req); err != nil || len(req.Command) == 0 {
+		ws.WriteMessage(websocket.TextMessage, []byte(`{"error":"first message must be {\"command\":[...]}"}`))
+		return
+	}
+
+	stdinR, stdinW := io.Pipe()
+	defer stdinW.Close()
+
+	stdout, stderr, exitCh, err := g.manager.StreamCommand(ctx, instanceID, req.Command, stdinR)
+	if err != nil {
+		ws.WriteMessage(websocket.TextMessage, []byte(`{"error":`+strconv.Quote(err.Error())+`}`))
+		return
+	}
+	defer stdout.Close()
+	defer stderr.Close()
+
+	browserClosed := make(chan struct{})
+
+	// Browser -> stdin
+	go func() {
+		defer close(browserClosed)
+		defer stdinW.Close()
+		for {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			if _, err := stdinW.Write(data); err != nil {
+				return
+			}
+		}
+	}()
+
+	var relayWG sync.WaitGroup
+	relayWG.Add(2)
+	go func() { defer relayWG.Done(); g.relay(ws, stdout, "stdout") }()
+	go func() { defer relayWG.Done(); g.relay(ws, stderr, "stderr") }()
+
+	exitCode := <-exitCh
+	relayWG.Wait()
+
+	if frame, err := json.Marshal(map[string]interface{}{"stream": "exit", "code": exitCode}); err == nil {
+		ws.WriteMessage(websocket.TextMessage, frame)
+	}
+
+	<-browserClosed
+}
+
+// relay copies r to ws as stream-tagged JSON frames until r is exhausted or
+// the connection fails.
+func (g *ExecGateway) relay(ws *websocket.Conn, r io.Reader, stream string) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			frame, marshalErr := json.Marshal(map[string]string{"stream": stream, "data": string(buf[:n])})
+			if marshalErr == nil {
+				if writeErr := ws.WriteMessage(websocket.TextMessage, frame); writeErr != nil {
+					return
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
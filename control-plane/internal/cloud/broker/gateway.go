@@ -0,0 +1,190 @@
+package broker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+var upgrader = websocket.Upgrader{
+	// Sessions are authenticated by the surrounding gin route (same cookie/
+	// bearer auth as the rest of the UI API), so cross-origin checks are
+	// left to the caller's CORS middleware rather than duplicated here.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Gateway upgrades a browser WebSocket connection and multiplexes it to the
+// guacd backend appropriate for the target instance's platform (RDP for
+// Windows, VNC for macOS, SSH for Linux).
+type Gateway struct {
+	manager *cloud.CloudManager
+	cfg     cloud.BrokerConfig
+}
+
+// NewGateway creates a new broker gateway.
+func NewGateway(manager *cloud.CloudManager, cfg cloud.BrokerConfig) *Gateway {
+	return &Gateway{manager: manager, cfg: cfg}
+}
+
+// ServeWS handles GET /api/ui/v1/cloud/instances/:id/session, upgrading to a
+// WebSocket and proxying the Guacamole protocol between the browser and guacd.
+func (g *Gateway) ServeWS(c *gin.Context) {
+	ctx := c.Request.Context()
+	instanceID := c.Param("id")
+
+	inst, err := g.manager.GetInstance(ctx, instanceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "instance not found"})
+		return
+	}
+
+	connInfo, err := g.manager.GetConnectionInfo(ctx, instanceID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "connection info unavailable: " + err.Error()})
+		return
+	}
+
+	protocol, params := guacdParams(connInfo)
+	if protocol == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported connection protocol for broker sessions"})
+		return
+	}
+
+	guacdConn, err := DialGuacd(ctx, g.cfg.GuacdAddr, protocol, params)
+	if err != nil {
+		log.Error().Err(err).Str("instance_id", instanceID).Msg("broker: failed to connect to guacd")
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to reach broker backend"})
+		return
+	}
+	defer guacdConn.Close()
+
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Warn().Err(err).Str("instance_id", instanceID).Msg("broker: websocket upgrade failed")
+		return
+	}
+	defer ws.Close()
+
+	g.manager.EventBus().EmitInstanceEvent(cloud.EventSessionOpened, instanceID, map[string]string{
+		"protocol": protocol,
+	})
+
+	started := time.Now()
+	bytesIn, bytesOut := g.pump(ws, guacdConn)
+	duration := time.Since(started)
+
+	g.manager.EventBus().EmitInstanceEvent(cloud.EventSessionClosed, instanceID, map[string]interface{}{
+		"protocol":     protocol,
+		"bytes_in":     bytesIn,
+		"bytes_out":    bytesOut,
+		"duration_sec": duration.Seconds(),
+	})
+
+	if g.cfg.SessionHourlyCents > 0 {
+		hours := duration.Hours()
+		if err := g.manager.Billing().ReportUsage(context.Background(), instanceID, inst.Platform, hours, g.cfg.SessionHourlyCents); err != nil {
+			log.Warn().Err(err).Str("instance_id", instanceID).Msg("broker: failed to report session usage to billing")
+		}
+	}
+}
+
+// pump relays raw Guacamole protocol bytes between the browser WebSocket and
+// guacd until either side closes or the idle timeout elapses, returning the
+// number of bytes read from the browser and written to the browser.
+func (g *Gateway) pump(ws *websocket.Conn, guacdConn *GuacdConn) (bytesIn, bytesOut int64) {
+	done := make(chan struct{})
+
+	// Browser -> guacd
+	go func() {
+		defer close(done)
+		for {
+			if g.cfg.SessionIdleTimeout > 0 {
+				ws.SetReadDeadline(time.Now().Add(g.cfg.SessionIdleTimeout))
+				guacdConn.SetDeadline(time.Now().Add(g.cfg.SessionIdleTimeout))
+			}
+
+			_, msg, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			atomic.AddInt64(&bytesIn, int64(len(msg)))
+			if _, err := guacdConn.Write(msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Once the browser side exits, force the blocked guacd read below to
+	// unblock immediately rather than waiting out the idle deadline.
+	go func() {
+		<-done
+		guacdConn.Close()
+	}()
+
+	// guacd -> browser
+	buf := make([]byte, 8192)
+	for {
+		n, err := guacdConn.Reader().Read(buf)
+		if n > 0 {
+			atomic.AddInt64(&bytesOut, int64(n))
+			if writeErr := ws.WriteMessage(websocket.TextMessage, buf[:n]); writeErr != nil {
+				break
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				log.Debug().Err(err).Msg("broker: guacd read ended")
+			}
+			break
+		}
+	}
+
+	ws.Close()
+	<-done
+
+	return atomic.LoadInt64(&bytesIn), atomic.LoadInt64(&bytesOut)
+}
+
+// guacdParams maps a cloud.ConnectionInfo to the guacd protocol name and
+// connection parameters for the appropriate backend.
+func guacdParams(info *cloud.ConnectionInfo) (protocol string, params map[string]string) {
+	switch info.Protocol {
+	case cloud.ConnectionProtocolRDP:
+		params = map[string]string{
+			"hostname":    info.Host,
+			"port":        strconv.Itoa(info.Port),
+			"username":    info.Username,
+			"password":    info.Password,
+			"security":    "any",
+			"ignore-cert": "true",
+		}
+		return "rdp", params
+	case cloud.ConnectionProtocolVNC:
+		params = map[string]string{
+			"hostname": info.Host,
+			"port":     strconv.Itoa(info.Port),
+			"password": info.Password,
+		}
+		return "vnc", params
+	case cloud.ConnectionProtocolSSH:
+		params = map[string]string{
+			"hostname":    info.Host,
+			"port":        strconv.Itoa(info.Port),
+			"username":    info.Username,
+			"password":    info.Password,
+			"private-key": info.KeyData,
+		}
+		return "ssh", params
+	default:
+		return "", nil
+	}
+}
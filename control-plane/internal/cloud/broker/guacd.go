@@ -0,0 +1,120 @@
+package broker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// defaultScreenWidth/Height/DPI are used when the browser hasn't yet sent a
+// client "size" instruction at connect time.
+const (
+	defaultScreenWidth  = "1024"
+	defaultScreenHeight = "768"
+	defaultScreenDPI    = "96"
+)
+
+// GuacdConn is an established, handshaken connection to guacd for a single
+// remote desktop session.
+type GuacdConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// DialGuacd connects to guacd at addr and performs the protocol handshake
+// for the given backend protocol ("rdp", "vnc", or "ssh"), supplying
+// connection parameters (hostname, port, username, password, etc.) in the
+// order guacd requests them via its "args" instruction.
+func DialGuacd(ctx context.Context, addr, protocol string, params map[string]string) (*GuacdConn, error) {
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("guacd: failed to connect to %s: %w", addr, err)
+	}
+
+	gc := &GuacdConn{conn: conn, reader: bufio.NewReader(conn)}
+	if err := gc.handshake(protocol, params); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return gc, nil
+}
+
+func (gc *GuacdConn) handshake(protocol string, params map[string]string) error {
+	if err := gc.send(NewInstruction("select", protocol)); err != nil {
+		return fmt.Errorf("guacd: select failed: %w", err)
+	}
+
+	args, err := gc.receive()
+	if err != nil {
+		return fmt.Errorf("guacd: failed to read args: %w", err)
+	}
+	if args.Opcode != "args" {
+		return fmt.Errorf("guacd: expected args instruction, got %q", args.Opcode)
+	}
+
+	if err := gc.send(NewInstruction("size", defaultScreenWidth, defaultScreenHeight, defaultScreenDPI)); err != nil {
+		return fmt.Errorf("guacd: size failed: %w", err)
+	}
+	if err := gc.send(NewInstruction("audio")); err != nil {
+		return fmt.Errorf("guacd: audio failed: %w", err)
+	}
+	if err := gc.send(NewInstruction("video")); err != nil {
+		return fmt.Errorf("guacd: video failed: %w", err)
+	}
+	if err := gc.send(NewInstruction("image")); err != nil {
+		return fmt.Errorf("guacd: image failed: %w", err)
+	}
+
+	// Supply connection parameter values in the order guacd asked for them.
+	values := make([]string, len(args.Args))
+	for i, name := range args.Args {
+		values[i] = params[name]
+	}
+	if err := gc.send(NewInstruction("connect", values...)); err != nil {
+		return fmt.Errorf("guacd: connect failed: %w", err)
+	}
+
+	ready, err := gc.receive()
+	if err != nil {
+		return fmt.Errorf("guacd: failed to read ready: %w", err)
+	}
+	if ready.Opcode != "ready" {
+		return fmt.Errorf("guacd: expected ready instruction, got %q", ready.Opcode)
+	}
+
+	return nil
+}
+
+func (gc *GuacdConn) send(i Instruction) error {
+	_, err := gc.conn.Write([]byte(i.Encode()))
+	return err
+}
+
+func (gc *GuacdConn) receive() (*Instruction, error) {
+	return ReadInstruction(gc.reader)
+}
+
+// Reader returns the buffered reader positioned after the handshake, so the
+// gateway can keep draining raw guacd protocol bytes without re-parsing
+// instructions it doesn't need to inspect.
+func (gc *GuacdConn) Reader() *bufio.Reader {
+	return gc.reader
+}
+
+// Write sends raw bytes (already-encoded instructions) to guacd.
+func (gc *GuacdConn) Write(p []byte) (int, error) {
+	return gc.conn.Write(p)
+}
+
+// SetDeadline applies an idle read/write deadline to the underlying connection.
+func (gc *GuacdConn) SetDeadline(t time.Time) error {
+	return gc.conn.SetDeadline(t)
+}
+
+// Close closes the guacd connection.
+func (gc *GuacdConn) Close() error {
+	return gc.conn.Close()
+}
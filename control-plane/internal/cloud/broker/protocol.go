@@ -0,0 +1,93 @@
+// Package broker implements a Guacamole-compatible connection broker that
+// lets browsers reach provisioned RDP/VNC/SSH instances without exposing
+// them directly. It speaks the guacd wire protocol: each instruction is a
+// comma-separated list of length-prefixed elements terminated by a
+// semicolon, e.g. "4.size,3.800,3.600,2.96;".
+package broker
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Instruction is a single Guacamole protocol instruction.
+type Instruction struct {
+	Opcode string
+	Args   []string
+}
+
+// NewInstruction builds an instruction from an opcode and its arguments.
+func NewInstruction(opcode string, args ...string) Instruction {
+	return Instruction{Opcode: opcode, Args: args}
+}
+
+// Encode serializes the instruction into guacd wire format.
+func (i Instruction) Encode() string {
+	elements := make([]string, 0, len(i.Args)+1)
+	elements = append(elements, encodeElement(i.Opcode))
+	for _, arg := range i.Args {
+		elements = append(elements, encodeElement(arg))
+	}
+	return strings.Join(elements, ",") + ";"
+}
+
+func encodeElement(s string) string {
+	return strconv.Itoa(len(s)) + "." + s
+}
+
+// ReadInstruction reads and parses one instruction from r.
+func ReadInstruction(r *bufio.Reader) (*Instruction, error) {
+	var elements []string
+
+	for {
+		lengthStr, err := r.ReadString('.')
+		if err != nil {
+			return nil, fmt.Errorf("guacamole: failed to read element length: %w", err)
+		}
+		lengthStr = strings.TrimSuffix(lengthStr, ".")
+
+		length, err := strconv.Atoi(lengthStr)
+		if err != nil {
+			return nil, fmt.Errorf("guacamole: invalid element length %q: %w", lengthStr, err)
+		}
+
+		value := make([]byte, length)
+		if _, err := readFull(r, value); err != nil {
+			return nil, fmt.Errorf("guacamole: failed to read element value: %w", err)
+		}
+
+		terminator, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("guacamole: failed to read terminator: %w", err)
+		}
+
+		elements = append(elements, string(value))
+
+		if terminator == ';' {
+			break
+		}
+		if terminator != ',' {
+			return nil, fmt.Errorf("guacamole: unexpected terminator byte %q", terminator)
+		}
+	}
+
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("guacamole: instruction had no opcode")
+	}
+
+	return &Instruction{Opcode: elements[0], Args: elements[1:]}, nil
+}
+
+func readFull(r *bufio.Reader, buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		m, err := r.Read(buf[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
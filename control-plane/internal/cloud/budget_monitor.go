@@ -0,0 +1,235 @@
+package cloud
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+)
+
+// budgetSampleWindow bounds how many recent per-team cost samples
+// BudgetMonitor averages over when computing burn rate.
+const budgetSampleWindow = 12
+
+// budgetSample is one burn-rate observation for a team, recorded once per
+// monitor tick.
+type budgetSample struct {
+	at          time.Time
+	hourlyCents int
+}
+
+// BudgetMonitor watches per-team spend against CloudQuota.MonthlyBudgetCents,
+// emitting budget.warning/budget.exceeded CloudEvents and, depending on
+// BillingConfig.EnforcementMode, auto-stopping or auto-terminating a team's
+// oldest running instance when the hard cap is breached.
+type BudgetMonitor struct {
+	manager  *CloudManager
+	store    storage.StorageProvider
+	config   CloudConfig
+	stopOnce sync.Once
+	stopCh   chan struct{}
+
+	mu      sync.Mutex
+	samples map[string][]budgetSample // team ID -> ring buffer of recent hourly-rate samples
+	warned  map[string]int            // team ID -> highest threshold already emitted this budget period
+}
+
+// NewBudgetMonitor creates a new budget monitor.
+func NewBudgetMonitor(manager *CloudManager, store storage.StorageProvider, cfg CloudConfig) *BudgetMonitor {
+	return &BudgetMonitor{
+		manager: manager,
+		store:   store,
+		config:  cfg,
+		stopCh:  make(chan struct{}),
+		samples: make(map[string][]budgetSample),
+		warned:  make(map[string]int),
+	}
+}
+
+// Start begins the monitor loop.
+func (m *BudgetMonitor) Start() {
+	ticker := time.NewTicker(m.config.MonitorInterval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", m.config.MonitorInterval).Msg("cloud budget monitor started")
+
+	for {
+		select {
+		case <-m.stopCh:
+			log.Info().Msg("cloud budget monitor stopped")
+			return
+		case <-ticker.C:
+			m.tick()
+		}
+	}
+}
+
+// Stop terminates the monitor loop.
+func (m *BudgetMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+}
+
+func (m *BudgetMonitor) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	runningState := InstanceStateRunning
+	instances, err := m.store.ListCloudInstances(ctx, InstanceFilters{State: &runningState})
+	if err != nil {
+		log.Error().Err(err).Msg("budget monitor: failed to list running instances")
+		return
+	}
+
+	hourlyCentsByTeam := make(map[string]int)
+	for _, inst := range instances {
+		if inst.TeamID == "" || inst.HourlyRateCents <= 0 {
+			continue
+		}
+		hourlyCentsByTeam[inst.TeamID] += inst.HourlyRateCents
+	}
+
+	for teamID, hourlyCents := range hourlyCentsByTeam {
+		m.recordSample(teamID, hourlyCents)
+		m.evaluateBudget(ctx, teamID)
+	}
+}
+
+func (m *BudgetMonitor) recordSample(teamID string, hourlyCents int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	samples := append(m.samples[teamID], budgetSample{at: time.Now().UTC(), hourlyCents: hourlyCents})
+	if len(samples) > budgetSampleWindow {
+		samples = samples[len(samples)-budgetSampleWindow:]
+	}
+	m.samples[teamID] = samples
+}
+
+// burnRateCentsPerHour averages the team's recent hourly-rate samples.
+func (m *BudgetMonitor) burnRateCentsPerHour(teamID string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	samples := m.samples[teamID]
+	if len(samples) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, s := range samples {
+		total += s.hourlyCents
+	}
+	return total / len(samples)
+}
+
+func (m *BudgetMonitor) evaluateBudget(ctx context.Context, teamID string) {
+	quota, err := m.manager.billing.GetTeamQuota(ctx, teamID)
+	if err != nil {
+		log.Warn().Err(err).Str("team_id", teamID).Msg("budget monitor: failed to fetch team quota")
+		return
+	}
+	if quota.MonthlyBudgetCents <= 0 {
+		return // no budget cap configured for this team
+	}
+
+	burnRate := m.burnRateCentsPerHour(teamID)
+	usedPct := percentOf(quota.UsedBudgetCents, quota.MonthlyBudgetCents)
+	threshold := warningThreshold(usedPct)
+
+	m.mu.Lock()
+	lastWarned := m.warned[teamID]
+	if usedPct < 50 {
+		// Budget likely reset for a new billing period; allow warnings to re-fire.
+		delete(m.warned, teamID)
+	}
+	m.mu.Unlock()
+
+	if threshold == 0 || threshold <= lastWarned {
+		return
+	}
+
+	m.mu.Lock()
+	m.warned[teamID] = threshold
+	m.mu.Unlock()
+
+	data := map[string]interface{}{
+		"team_id":            teamID,
+		"used_budget_cents":  quota.UsedBudgetCents,
+		"budget_cents":       quota.MonthlyBudgetCents,
+		"burn_rate_cents_hr": burnRate,
+		"threshold_pct":      threshold,
+	}
+
+	if threshold >= 100 {
+		log.Warn().Str("team_id", teamID).Int("used_pct", usedPct).Msg("budget monitor: team hard cap exceeded")
+		m.manager.EventBus().EmitInstanceEvent(EventBudgetExceeded, "", data)
+		m.enforce(ctx, teamID)
+		return
+	}
+
+	log.Warn().Str("team_id", teamID).Int("threshold_pct", threshold).Msg("budget monitor: team crossed soft budget threshold")
+	m.manager.EventBus().EmitInstanceEvent(EventBudgetWarning, "", data)
+}
+
+// enforce acts on a team's hard budget cap breach according to
+// BillingConfig.EnforcementMode, stopping or terminating the team's oldest
+// running instance. EnforcementOff and EnforcementWarn take no action here
+// beyond the event already emitted by evaluateBudget.
+func (m *BudgetMonitor) enforce(ctx context.Context, teamID string) {
+	if m.config.Billing.EnforcementMode != EnforcementStop && m.config.Billing.EnforcementMode != EnforcementTerminate {
+		return
+	}
+
+	runningState := InstanceStateRunning
+	instances, err := m.store.ListCloudInstances(ctx, InstanceFilters{TeamID: &teamID, State: &runningState})
+	if err != nil || len(instances) == 0 {
+		return
+	}
+
+	oldest := instances[0]
+	for _, inst := range instances {
+		if inst.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = inst
+		}
+	}
+
+	switch m.config.Billing.EnforcementMode {
+	case EnforcementStop:
+		log.Warn().Str("team_id", teamID).Str("instance_id", oldest.ID).Msg("budget monitor: stopping oldest instance after hard cap breach")
+		if err := m.manager.StopInstance(ctx, oldest.ID); err != nil {
+			log.Error().Err(err).Str("instance_id", oldest.ID).Msg("budget monitor: failed to stop instance")
+		}
+	case EnforcementTerminate:
+		log.Warn().Str("team_id", teamID).Str("instance_id", oldest.ID).Msg("budget monitor: terminating oldest instance after hard cap breach")
+		if err := m.manager.TerminateInstance(ctx, oldest.ID); err != nil {
+			log.Error().Err(err).Str("instance_id", oldest.ID).Msg("budget monitor: failed to terminate instance")
+		}
+	}
+}
+
+func percentOf(used, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return used * 100 / total
+}
+
+func warningThreshold(pct int) int {
+	switch {
+	case pct >= 100:
+		return 100
+	case pct >= 95:
+		return 95
+	case pct >= 80:
+		return 80
+	case pct >= 50:
+		return 50
+	default:
+		return 0
+	}
+}
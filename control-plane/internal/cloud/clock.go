@@ -0,0 +1,14 @@
+package cloud
+
+import "time"
+
+// Clock abstracts wall-clock time so Scheduler phases can be driven by a
+// fake, tickable clock in tests instead of waiting on real timers.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the default Clock, backed by time.Now.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now().UTC() }
@@ -12,34 +12,228 @@ type CloudConfig struct {
 	Enabled bool      `yaml:"enabled" mapstructure:"enabled"`
 	AWS     AWSConfig `yaml:"aws" mapstructure:"aws"`
 	K8s     K8sConfig `yaml:"k8s" mapstructure:"k8s"`
+
+	// Additional provider backends, registered via RegisterProviderFactory.
+	Azure        AzureConfig        `yaml:"azure" mapstructure:"azure"`
+	GCP          GCPConfig          `yaml:"gcp" mapstructure:"gcp"`
+	DigitalOcean DigitalOceanConfig `yaml:"digitalocean" mapstructure:"digitalocean"`
+	Linode       LinodeConfig       `yaml:"linode" mapstructure:"linode"`
+	Hetzner      HetznerConfig      `yaml:"hetzner" mapstructure:"hetzner"`
+	EquinixMetal EquinixMetalConfig `yaml:"equinix_metal" mapstructure:"equinix_metal"`
+
+	// Drivers enables additional registered provider drivers generically,
+	// without CloudManager needing per-provider wiring code.
+	Drivers []DriverConfig `yaml:"drivers" mapstructure:"drivers"`
+
 	Billing BillingConfig `yaml:"billing" mapstructure:"billing"`
+	Broker  BrokerConfig  `yaml:"broker" mapstructure:"broker"`
 
 	// Safety limits
 	MaxInstancesPerTeam int           `yaml:"max_instances_per_team" mapstructure:"max_instances_per_team"`
 	ProvisioningTimeout time.Duration `yaml:"provisioning_timeout" mapstructure:"provisioning_timeout"`
 	MonitorInterval     time.Duration `yaml:"monitor_interval" mapstructure:"monitor_interval"`
+	// ReconcileInterval controls how often CloudManager.Reconcile runs to
+	// heal drift between storage and the provider's own view of instances
+	// (a full provider-side list, so it runs far less often than
+	// MonitorInterval's per-instance state sync).
+	ReconcileInterval time.Duration `yaml:"reconcile_interval" mapstructure:"reconcile_interval"`
+	// WorkerConcurrency bounds how many provisioner operations the
+	// Scheduler's Sync and RunQueue phases run at once per platform, so a
+	// burst of instances on one cloud can't starve the others.
+	WorkerConcurrency int `yaml:"worker_concurrency" mapstructure:"worker_concurrency"`
+	// RateLimits caps API calls per provider name (the string ProviderName()
+	// returns, e.g. "aws", "gcp"), so CloudManager's calls into CreateInstance/
+	// GetInstance/TerminateInstance can't exceed a provider's own throttling
+	// limits once there are hundreds of tracked instances. Providers without
+	// an entry here fall back to defaultRateLimiterConfig.
+	RateLimits map[string]RateLimiterConfig `yaml:"rate_limits" mapstructure:"rate_limits"`
+
+	// Jobs configures the independently scheduled background reconcilers run
+	// by internal/cloud/jobs.JobScheduler, replacing the single
+	// MonitorInterval cadence every monitor phase used to share.
+	Jobs JobsConfig `yaml:"jobs" mapstructure:"jobs"`
+
+	// Bootstrap configures the short-lived, single-use token newly
+	// provisioned instances exchange for their real agent API key, so the
+	// key itself is never baked into EC2 userdata in plaintext.
+	Bootstrap BootstrapConfig `yaml:"bootstrap" mapstructure:"bootstrap"`
+
+	// Freeze configures AccountFreezeService's grace periods and
+	// auto-freeze threshold.
+	Freeze FreezeConfig `yaml:"freeze" mapstructure:"freeze"`
+}
+
+// FreezeConfig configures AccountFreezeService.
+type FreezeConfig struct {
+	// BillingGracePeriod, ViolationGracePeriod and LegalGracePeriod bound
+	// how long a frozen account's already-running instances are left
+	// alone before FreezeReconciler suspends or terminates them. Zero
+	// falls back to Defaults().
+	BillingGracePeriod   time.Duration `yaml:"billing_grace_period" mapstructure:"billing_grace_period"`
+	ViolationGracePeriod time.Duration `yaml:"violation_grace_period" mapstructure:"violation_grace_period"`
+	LegalGracePeriod     time.Duration `yaml:"legal_grace_period" mapstructure:"legal_grace_period"`
+	// AutoFreezeThreshold is how many consecutive
+	// services.ErrInsufficientFunds results RecordInsufficientFunds sees
+	// for a team before it auto-applies a FreezeBilling.
+	AutoFreezeThreshold int `yaml:"auto_freeze_threshold" mapstructure:"auto_freeze_threshold"`
+	// ReconcileInterval controls how often FreezeReconciler checks for
+	// frozen accounts whose grace period has elapsed.
+	ReconcileInterval time.Duration `yaml:"reconcile_interval" mapstructure:"reconcile_interval"`
+}
+
+// BootstrapConfig configures BootstrapIssuer, which mints and redeems the
+// one-time bootstrap tokens embedded in instance userdata in place of the
+// real agent API key.
+type BootstrapConfig struct {
+	// SigningKey signs and verifies bootstrap JWTs. Required by any
+	// provisioner that embeds a bootstrap token in userdata.
+	SigningKey string `yaml:"signing_key" mapstructure:"signing_key"`
+	// AgentAPIKey is the real HANZO_AGENTS_API_KEY handed back on a
+	// successful token exchange.
+	AgentAPIKey string `yaml:"agent_api_key" mapstructure:"agent_api_key"`
+	// TokenTTL bounds how long a minted bootstrap token remains valid,
+	// capped at 10 minutes regardless of this setting. Defaults to 10
+	// minutes.
+	TokenTTL time.Duration `yaml:"token_ttl" mapstructure:"token_ttl"`
+}
+
+// JobsConfig holds the per-reconciler interval/timeout overrides for
+// CloudInstanceMonitor's JobScheduler. Any left zero fall back to the
+// defaults set in Defaults().
+type JobsConfig struct {
+	// SyncInterval controls FixStaleLocks/RunQueue/Sync, the frequent
+	// per-instance provisioner state poll.
+	SyncInterval time.Duration `yaml:"sync_interval" mapstructure:"sync_interval"`
+	// AccrueInterval controls usage cost accrual and billing reporting.
+	AccrueInterval time.Duration `yaml:"accrue_interval" mapstructure:"accrue_interval"`
+	// IdleHostReleaseInterval controls how often idle macOS Dedicated Hosts
+	// are checked for release eligibility.
+	IdleHostReleaseInterval time.Duration `yaml:"idle_host_release_interval" mapstructure:"idle_host_release_interval"`
+	// QuotaRecheckInterval controls how often each team's billed quota usage
+	// is reconciled against its actual running instance count.
+	QuotaRecheckInterval time.Duration `yaml:"quota_recheck_interval" mapstructure:"quota_recheck_interval"`
+	// PruneInterval controls how often terminated instances older than
+	// PruneAfter are checked for deletion from storage.
+	PruneInterval time.Duration `yaml:"prune_interval" mapstructure:"prune_interval"`
+	// PruneAfter is how long a terminated instance is kept in storage before
+	// it becomes eligible for pruning.
+	PruneAfter time.Duration `yaml:"prune_after" mapstructure:"prune_after"`
 }
 
 // AWSConfig holds AWS-specific provisioning configuration.
 type AWSConfig struct {
-	Enabled            bool   `yaml:"enabled" mapstructure:"enabled"`
-	Region             string `yaml:"region" mapstructure:"region"`
-	VPCID              string `yaml:"vpc_id" mapstructure:"vpc_id"`
+	Enabled            bool     `yaml:"enabled" mapstructure:"enabled"`
+	Region             string   `yaml:"region" mapstructure:"region"`
+	VPCID              string   `yaml:"vpc_id" mapstructure:"vpc_id"`
 	SubnetIDs          []string `yaml:"subnet_ids" mapstructure:"subnet_ids"`
-	SecurityGroupID    string `yaml:"security_group_id" mapstructure:"security_group_id"`
-	IAMInstanceProfile string `yaml:"iam_instance_profile" mapstructure:"iam_instance_profile"`
+	SecurityGroupID    string   `yaml:"security_group_id" mapstructure:"security_group_id"`
+	IAMInstanceProfile string   `yaml:"iam_instance_profile" mapstructure:"iam_instance_profile"`
+
+	// EnforceIMDSv2 requires token-only instance metadata access (HttpTokens
+	// "required") on every EC2 instance we launch.
+	EnforceIMDSv2 bool `yaml:"enforce_imdsv2" mapstructure:"enforce_imdsv2"`
+	// AgentAssumeRoleARN is the role agents assume to obtain the short-lived
+	// credentials vended by the AWS CredentialBroker. Required when
+	// EnforceIMDSv2 is set, since agents can no longer rely on the instance
+	// profile alone for anything beyond basic metadata access.
+	AgentAssumeRoleARN string `yaml:"agent_assume_role_arn" mapstructure:"agent_assume_role_arn"`
+
+	// AvailabilityZones lists the AZs dedicated hosts may be auto-allocated
+	// into, in preference order for the "spread" placement strategy.
+	AvailabilityZones []string `yaml:"availability_zones" mapstructure:"availability_zones"`
+
+	// Auth configures how the aws package itself authenticates to AWS
+	// (distinct from AgentAssumeRoleARN, which is the role provisioned
+	// agents assume). Left zero-valued, it falls back to the AWS SDK's
+	// ambient default credential chain.
+	Auth AWSAuthConfig `yaml:"auth" mapstructure:"auth"`
 
 	MacOS   AWSMacOSConfig   `yaml:"macos" mapstructure:"macos"`
 	Windows AWSWindowsConfig `yaml:"windows" mapstructure:"windows"`
 }
 
+// AWSAuthMode selects how CredentialResolver builds the control plane's own
+// aws.Config.
+type AWSAuthMode string
+
+const (
+	// AWSAuthModeDefault uses the AWS SDK's ambient default credential
+	// chain, which already covers EC2 IMDSv2, ECS/EKS container
+	// credentials (AWS_CONTAINER_CREDENTIALS_RELATIVE_URI / _FULL_URI),
+	// and AssumeRoleWithWebIdentity (IRSA / GitHub OIDC) via the standard
+	// AWS_WEB_IDENTITY_TOKEN_FILE / AWS_ROLE_ARN environment variables.
+	AWSAuthModeDefault AWSAuthMode = ""
+	// AWSAuthModeStatic uses AccessKeyID/SecretAccessKey/SessionToken.
+	AWSAuthModeStatic AWSAuthMode = "static"
+	// AWSAuthModeProfile uses a named shared config/credentials profile.
+	AWSAuthModeProfile AWSAuthMode = "profile"
+	// AWSAuthModeAssumeRole assumes AssumeRoleARN on top of the default
+	// credential chain (or Profile, if also set), supporting ExternalID,
+	// SessionName, DurationSeconds and MFA.
+	AWSAuthModeAssumeRole AWSAuthMode = "assume_role"
+	// AWSAuthModeWebIdentity explicitly assumes WebIdentityRoleARN via
+	// WebIdentityTokenFile, for deployments that want this spelled out in
+	// config rather than relying on the SDK's ambient env var detection.
+	AWSAuthModeWebIdentity AWSAuthMode = "web_identity"
+)
+
+// AWSAuthConfig declaratively configures the credentials CredentialResolver
+// builds an aws.Config from, covering every credential source the aws
+// package needs to run both in EKS pods and against cross-account SSM
+// fleets.
+type AWSAuthConfig struct {
+	Mode AWSAuthMode `yaml:"mode" mapstructure:"mode"`
+
+	// Static credentials, used when Mode is AWSAuthModeStatic.
+	AccessKeyID     string `yaml:"access_key_id" mapstructure:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key" mapstructure:"secret_access_key"`
+	SessionToken    string `yaml:"session_token" mapstructure:"session_token"`
+
+	// Profile names a shared config/credentials profile, used when Mode is
+	// AWSAuthModeProfile and as the base credentials AssumeRole is layered
+	// on top of when Mode is AWSAuthModeAssumeRole and Profile is set.
+	Profile string `yaml:"profile" mapstructure:"profile"`
+
+	// AssumeRoleARN, ExternalID, SessionName, DurationSeconds and
+	// MFASerial configure Mode AWSAuthModeAssumeRole. SessionName defaults
+	// to "hanzo-control-plane" and DurationSeconds to 3600 when unset.
+	// MFASerial, if set, requires a token supplied through
+	// CredentialResolver's MFATokenProvider at credential-refresh time.
+	AssumeRoleARN   string `yaml:"assume_role_arn" mapstructure:"assume_role_arn"`
+	ExternalID      string `yaml:"external_id" mapstructure:"external_id"`
+	SessionName     string `yaml:"session_name" mapstructure:"session_name"`
+	DurationSeconds int32  `yaml:"duration_seconds" mapstructure:"duration_seconds"`
+	MFASerial       string `yaml:"mfa_serial" mapstructure:"mfa_serial"`
+
+	// WebIdentityTokenFile and WebIdentityRoleARN configure Mode
+	// AWSAuthModeWebIdentity explicitly. Most deployments can leave these
+	// unset and rely on AWSAuthModeDefault picking up the same values from
+	// the standard AWS_WEB_IDENTITY_TOKEN_FILE / AWS_ROLE_ARN env vars
+	// that EKS's Pod Identity Webhook already injects.
+	WebIdentityTokenFile string `yaml:"web_identity_token_file" mapstructure:"web_identity_token_file"`
+	WebIdentityRoleARN   string `yaml:"web_identity_role_arn" mapstructure:"web_identity_role_arn"`
+}
+
 // AWSMacOSConfig holds macOS-specific AWS configuration.
 type AWSMacOSConfig struct {
-	DedicatedHostIDs []string `yaml:"dedicated_host_ids" mapstructure:"dedicated_host_ids"`
-	AMIID            string   `yaml:"ami_id" mapstructure:"ami_id"`
-	InstanceType     string   `yaml:"instance_type" mapstructure:"instance_type"`
+	DedicatedHostIDs  []string      `yaml:"dedicated_host_ids" mapstructure:"dedicated_host_ids"`
+	AMIID             string        `yaml:"ami_id" mapstructure:"ami_id"`
+	InstanceType      string        `yaml:"instance_type" mapstructure:"instance_type"`
 	MinHostAllocation time.Duration `yaml:"min_host_allocation" mapstructure:"min_host_allocation"`
 	IdleHostRelease   time.Duration `yaml:"idle_host_release" mapstructure:"idle_host_release"`
+
+	// MaxHosts caps the number of Dedicated Hosts auto-allocation will bring
+	// up in this region; 0 means auto-allocation is disabled and only the
+	// pre-seeded DedicatedHostIDs pool is used.
+	MaxHosts int `yaml:"max_hosts" mapstructure:"max_hosts"`
+	// AZStrategy picks how auto-allocated hosts are spread across
+	// AvailabilityZones: "round-robin" (default), "least-loaded", or
+	// "spread" (AvailabilityZones in listed order, always starting from the
+	// first).
+	AZStrategy string `yaml:"az_strategy" mapstructure:"az_strategy"`
+	// IdleGracePeriod is how long an auto-allocated host may sit idle past
+	// MinHostAllocation before the reaper releases it back to AWS.
+	IdleGracePeriod time.Duration `yaml:"idle_grace_period" mapstructure:"idle_grace_period"`
 }
 
 // AWSWindowsConfig holds Windows-specific AWS configuration.
@@ -48,6 +242,123 @@ type AWSWindowsConfig struct {
 	DefaultInstanceType string `yaml:"default_instance_type" mapstructure:"default_instance_type"`
 }
 
+// AzureConfig holds Azure-specific provisioning configuration (ARM VM APIs).
+type AzureConfig struct {
+	Enabled        bool   `yaml:"enabled" mapstructure:"enabled"`
+	TenantID       string `yaml:"tenant_id" mapstructure:"tenant_id"`
+	ClientID       string `yaml:"client_id" mapstructure:"client_id"`
+	ClientSecret   string `yaml:"client_secret" mapstructure:"client_secret"`
+	SubscriptionID string `yaml:"subscription_id" mapstructure:"subscription_id"`
+	ResourceGroup  string `yaml:"resource_group" mapstructure:"resource_group"`
+	Location       string `yaml:"location" mapstructure:"location"`
+	VNetName       string `yaml:"vnet_name" mapstructure:"vnet_name"`
+	SubnetName     string `yaml:"subnet_name" mapstructure:"subnet_name"`
+	NSGName        string `yaml:"nsg_name" mapstructure:"nsg_name"`
+	ImageReference string `yaml:"image_reference" mapstructure:"image_reference"`
+	VMSize         string `yaml:"vm_size" mapstructure:"vm_size"`
+}
+
+// GCPConfig holds Google Compute Engine provisioning configuration.
+type GCPConfig struct {
+	Enabled         bool   `yaml:"enabled" mapstructure:"enabled"`
+	ProjectID       string `yaml:"project_id" mapstructure:"project_id"`
+	Zone            string `yaml:"zone" mapstructure:"zone"`
+	Network         string `yaml:"network" mapstructure:"network"`
+	Subnetwork      string `yaml:"subnetwork" mapstructure:"subnetwork"`
+	CredentialsFile string `yaml:"credentials_file" mapstructure:"credentials_file"`
+	ImageFamily     string `yaml:"image_family" mapstructure:"image_family"`
+	MachineType     string `yaml:"machine_type" mapstructure:"machine_type"`
+}
+
+// DigitalOceanConfig holds DigitalOcean Droplet provisioning configuration.
+type DigitalOceanConfig struct {
+	Enabled bool   `yaml:"enabled" mapstructure:"enabled"`
+	Token   string `yaml:"token" mapstructure:"token"`
+	Region  string `yaml:"region" mapstructure:"region"`
+	Size    string `yaml:"size" mapstructure:"size"`
+	Image   string `yaml:"image" mapstructure:"image"`
+}
+
+// LinodeConfig holds Linode instance provisioning configuration.
+type LinodeConfig struct {
+	Enabled bool   `yaml:"enabled" mapstructure:"enabled"`
+	Token   string `yaml:"token" mapstructure:"token"`
+	Region  string `yaml:"region" mapstructure:"region"`
+	Type    string `yaml:"type" mapstructure:"type"`
+	Image   string `yaml:"image" mapstructure:"image"`
+}
+
+// HetznerConfig holds Hetzner Cloud server provisioning configuration.
+type HetznerConfig struct {
+	Enabled    bool   `yaml:"enabled" mapstructure:"enabled"`
+	Token      string `yaml:"token" mapstructure:"token"`
+	Location   string `yaml:"location" mapstructure:"location"`
+	ServerType string `yaml:"server_type" mapstructure:"server_type"`
+	Image      string `yaml:"image" mapstructure:"image"`
+}
+
+// EquinixMetalConfig holds Equinix Metal bare-metal provisioning
+// configuration, for teams that need non-AWS macOS or Linux bare metal
+// (e.g. Apple silicon bare metal plans).
+type EquinixMetalConfig struct {
+	Enabled   bool   `yaml:"enabled" mapstructure:"enabled"`
+	Token     string `yaml:"token" mapstructure:"token"`
+	ProjectID string `yaml:"project_id" mapstructure:"project_id"`
+	Metro     string `yaml:"metro" mapstructure:"metro"`
+	Plan      string `yaml:"plan" mapstructure:"plan"`
+	OS        string `yaml:"os" mapstructure:"os"`
+}
+
+// DriverConfig enables a provider driver registered via RegisterProviderFactory
+// (see cloud/provisioner.go) for one or more platforms. New provider packages
+// only need their own init()-registered factory and a CloudConfig section;
+// CloudManager.RegisterConfiguredDrivers wires up every enabled entry here
+// without any provider-specific code of its own.
+type DriverConfig struct {
+	Name      string     `yaml:"name" mapstructure:"name"`
+	Platforms []Platform `yaml:"platforms" mapstructure:"platforms"`
+	Enabled   bool       `yaml:"enabled" mapstructure:"enabled"`
+}
+
+// driverConfig returns the provider-specific config section for a named
+// driver, so RegisterConfiguredDrivers can build provisioners generically
+// through NewProviderProvisioner.
+func driverConfig(c CloudConfig, name string) (any, bool) {
+	switch name {
+	case "azure":
+		return c.Azure, true
+	case "gcp":
+		return c.GCP, true
+	case "digitalocean":
+		return c.DigitalOcean, true
+	case "linode":
+		return c.Linode, true
+	case "hetzner":
+		return c.Hetzner, true
+	case "equinixmetal":
+		return c.EquinixMetal, true
+	default:
+		return nil, false
+	}
+}
+
+// BrokerConfig holds configuration for the Guacamole-compatible browser
+// connection broker (see cloud/broker).
+type BrokerConfig struct {
+	Enabled     bool   `yaml:"enabled" mapstructure:"enabled"`
+	ListenAddr  string `yaml:"listen_addr" mapstructure:"listen_addr"`
+	GuacdAddr   string `yaml:"guacd_addr" mapstructure:"guacd_addr"`
+	TLSCertFile string `yaml:"tls_cert_file" mapstructure:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file" mapstructure:"tls_key_file"`
+	// SessionIdleTimeout disconnects a browser session after this long
+	// without a message from the browser; output from guacd alone does not
+	// extend it.
+	SessionIdleTimeout time.Duration `yaml:"session_idle_timeout" mapstructure:"session_idle_timeout"`
+	// SessionHourlyCents is the surcharge billed for active broker session
+	// time, on top of the instance's own compute hourly rate.
+	SessionHourlyCents int `yaml:"session_hourly_cents" mapstructure:"session_hourly_cents"`
+}
+
 // K8sConfig holds Kubernetes-specific provisioning configuration.
 type K8sConfig struct {
 	Enabled      bool   `yaml:"enabled" mapstructure:"enabled"`
@@ -55,6 +366,91 @@ type K8sConfig struct {
 	DefaultImage string `yaml:"default_image" mapstructure:"default_image"`
 	// ServiceAccount to use for pods. Defaults to "default".
 	ServiceAccount string `yaml:"service_account" mapstructure:"service_account"`
+
+	// NodeSelector constrains bot pods to nodes matching these labels.
+	NodeSelector map[string]string `yaml:"node_selector" mapstructure:"node_selector"`
+	// Tolerations lets bot pods schedule onto nodes with matching taints
+	// (e.g. a dedicated bot node pool).
+	Tolerations []K8sToleration `yaml:"tolerations" mapstructure:"tolerations"`
+	// Affinity steers bot pod scheduling beyond NodeSelector.
+	Affinity *K8sAffinity `yaml:"affinity" mapstructure:"affinity"`
+	// InitContainers run to completion before the agent container starts,
+	// e.g. to wait for a dependency to become reachable.
+	InitContainers []K8sContainer `yaml:"init_containers" mapstructure:"init_containers"`
+	// ReadinessProbe gates when kubelet marks the agent container Ready, so
+	// waitForPodReady's Running determination reflects the bot actually
+	// being able to serve work rather than just having started.
+	ReadinessProbe *K8sProbe `yaml:"readiness_probe" mapstructure:"readiness_probe"`
+
+	// WorkloadKind selects what object CreateInstance provisions a bot as.
+	// Defaults to WorkloadKindPod. A ProvisionRequest can override this
+	// per-instance.
+	WorkloadKind WorkloadKind `yaml:"workload_kind" mapstructure:"workload_kind"`
+	// JobBackoffLimit/JobActiveDeadlineSeconds configure WorkloadKindJob's
+	// batchv1.Job; zero leaves the Kubernetes API server default.
+	JobBackoffLimit          int32 `yaml:"job_backoff_limit" mapstructure:"job_backoff_limit"`
+	JobActiveDeadlineSeconds int64 `yaml:"job_active_deadline_seconds" mapstructure:"job_active_deadline_seconds"`
+	// PVCStorageClass selects the StorageClass for WorkloadKindStatefulSet's
+	// volume claim template. Empty uses the cluster's default class.
+	PVCStorageClass string `yaml:"pvc_storage_class" mapstructure:"pvc_storage_class"`
+	// PVCSizeGiB sizes that volume claim. Defaults to 10 GiB.
+	PVCSizeGiB int `yaml:"pvc_size_gib" mapstructure:"pvc_size_gib"`
+	// RetainPVCOnTerminate skips deleting a StatefulSet instance's PVC on
+	// TerminateInstance, so a replacement instance with the same identity
+	// can reattach the same scratch volume.
+	RetainPVCOnTerminate bool `yaml:"retain_pvc_on_terminate" mapstructure:"retain_pvc_on_terminate"`
+}
+
+// WorkloadKind selects what Kubernetes object type a bot instance is
+// provisioned as.
+type WorkloadKind string
+
+const (
+	// WorkloadKindPod provisions a bare Pod with RestartPolicy=Never: a
+	// crashed bot is not restarted, and it has no persistent storage.
+	WorkloadKindPod WorkloadKind = "pod"
+	// WorkloadKindJob provisions a batchv1.Job, so a crashed bot is
+	// retried up to JobBackoffLimit times before being given up on.
+	WorkloadKindJob WorkloadKind = "job"
+	// WorkloadKindStatefulSet provisions a single-replica StatefulSet with
+	// a PVC-backed volume mounted at /var/lib/hanzo-agent, so bot state
+	// survives pod restarts and the pod keeps a stable identity.
+	WorkloadKindStatefulSet WorkloadKind = "statefulset"
+	// WorkloadKindDeployment provisions a single-replica Deployment, so a
+	// crashed or evicted bot is rescheduled indefinitely for long-lived HA
+	// use cases.
+	WorkloadKindDeployment WorkloadKind = "deployment"
+)
+
+// K8sToleration configures a pod toleration. Kept as our own type rather
+// than corev1.Toleration so config.go doesn't need to import k8s.io/api.
+type K8sToleration struct {
+	Key      string `yaml:"key" mapstructure:"key"`
+	Operator string `yaml:"operator" mapstructure:"operator"`
+	Value    string `yaml:"value" mapstructure:"value"`
+	Effect   string `yaml:"effect" mapstructure:"effect"`
+}
+
+// K8sAffinity configures node affinity for bot pods via a required
+// node-label match; anything more elaborate belongs in a custom provider
+// driver instead.
+type K8sAffinity struct {
+	RequiredNodeLabels map[string]string `yaml:"required_node_labels" mapstructure:"required_node_labels"`
+}
+
+// K8sContainer configures a minimal init container: a name, image, and
+// command, matching the common "wait for a dependency" use case.
+type K8sContainer struct {
+	Name    string   `yaml:"name" mapstructure:"name"`
+	Image   string   `yaml:"image" mapstructure:"image"`
+	Command []string `yaml:"command" mapstructure:"command"`
+}
+
+// K8sProbe configures a basic exec readiness probe for the agent container.
+type K8sProbe struct {
+	Command             []string `yaml:"command" mapstructure:"command"`
+	InitialDelaySeconds int32    `yaml:"initial_delay_seconds" mapstructure:"initial_delay_seconds"`
+	PeriodSeconds       int32    `yaml:"period_seconds" mapstructure:"period_seconds"`
 }
 
 // Defaults fills in zero values with sensible defaults.
@@ -68,6 +464,57 @@ func (c *CloudConfig) Defaults() {
 	if c.MonitorInterval == 0 {
 		c.MonitorInterval = 30 * time.Second
 	}
+	if c.ReconcileInterval == 0 {
+		c.ReconcileInterval = 10 * time.Minute
+	}
+	if c.WorkerConcurrency == 0 {
+		c.WorkerConcurrency = defaultWorkerConcurrency
+	}
+	if c.RateLimits == nil {
+		c.RateLimits = map[string]RateLimiterConfig{}
+	}
+	if _, ok := c.RateLimits["aws"]; !ok {
+		c.RateLimits["aws"] = RateLimiterConfig{MaxCalls: 20, Window: time.Second}
+	}
+	if _, ok := c.RateLimits["gcp"]; !ok {
+		c.RateLimits["gcp"] = RateLimiterConfig{MaxCalls: 10, Window: time.Second}
+	}
+	if c.Jobs.SyncInterval == 0 {
+		c.Jobs.SyncInterval = 30 * time.Second
+	}
+	if c.Jobs.AccrueInterval == 0 {
+		c.Jobs.AccrueInterval = 5 * time.Minute
+	}
+	if c.Jobs.IdleHostReleaseInterval == 0 {
+		c.Jobs.IdleHostReleaseInterval = 10 * time.Minute
+	}
+	if c.Jobs.QuotaRecheckInterval == 0 {
+		c.Jobs.QuotaRecheckInterval = time.Hour
+	}
+	if c.Jobs.PruneInterval == 0 {
+		c.Jobs.PruneInterval = time.Hour
+	}
+	if c.Jobs.PruneAfter == 0 {
+		c.Jobs.PruneAfter = 30 * 24 * time.Hour
+	}
+	if c.Bootstrap.TokenTTL == 0 {
+		c.Bootstrap.TokenTTL = maxBootstrapTokenTTL
+	}
+	if c.Freeze.BillingGracePeriod == 0 {
+		c.Freeze.BillingGracePeriod = 72 * time.Hour
+	}
+	if c.Freeze.ViolationGracePeriod == 0 {
+		c.Freeze.ViolationGracePeriod = time.Hour
+	}
+	// LegalGracePeriod defaults to zero: FreezeReconciler terminates a legal
+	// hold's running instances on its next pass rather than waiting out a
+	// grace period.
+	if c.Freeze.AutoFreezeThreshold == 0 {
+		c.Freeze.AutoFreezeThreshold = 3
+	}
+	if c.Freeze.ReconcileInterval == 0 {
+		c.Freeze.ReconcileInterval = 5 * time.Minute
+	}
 	if c.AWS.Region == "" {
 		c.AWS.Region = "us-east-1"
 	}
@@ -80,6 +527,12 @@ func (c *CloudConfig) Defaults() {
 	if c.AWS.MacOS.IdleHostRelease == 0 {
 		c.AWS.MacOS.IdleHostRelease = 25 * time.Hour // 1 hour after 24h min
 	}
+	if c.AWS.MacOS.AZStrategy == "" {
+		c.AWS.MacOS.AZStrategy = "round-robin"
+	}
+	if c.AWS.MacOS.IdleGracePeriod == 0 {
+		c.AWS.MacOS.IdleGracePeriod = 1 * time.Hour
+	}
 	if c.AWS.Windows.DefaultInstanceType == "" {
 		c.AWS.Windows.DefaultInstanceType = "t3.large"
 	}
@@ -92,6 +545,45 @@ func (c *CloudConfig) Defaults() {
 	if c.K8s.ServiceAccount == "" {
 		c.K8s.ServiceAccount = "default"
 	}
+	if c.K8s.WorkloadKind == "" {
+		c.K8s.WorkloadKind = WorkloadKindPod
+	}
+	if c.K8s.PVCSizeGiB == 0 {
+		c.K8s.PVCSizeGiB = 10
+	}
+	if c.Azure.VMSize == "" {
+		c.Azure.VMSize = "Standard_D2s_v3"
+	}
+	if c.GCP.MachineType == "" {
+		c.GCP.MachineType = "e2-standard-2"
+	}
+	if c.DigitalOcean.Size == "" {
+		c.DigitalOcean.Size = "s-2vcpu-4gb"
+	}
+	if c.Linode.Type == "" {
+		c.Linode.Type = "g6-standard-2"
+	}
+	if c.Hetzner.ServerType == "" {
+		c.Hetzner.ServerType = "cx22"
+	}
+	if c.Hetzner.Location == "" {
+		c.Hetzner.Location = "nbg1"
+	}
+	if c.EquinixMetal.Plan == "" {
+		c.EquinixMetal.Plan = "c3.small.x86"
+	}
+	if c.Broker.ListenAddr == "" {
+		c.Broker.ListenAddr = ":4823"
+	}
+	if c.Broker.GuacdAddr == "" {
+		c.Broker.GuacdAddr = "127.0.0.1:4822"
+	}
+	if c.Broker.SessionIdleTimeout == 0 {
+		c.Broker.SessionIdleTimeout = 15 * time.Minute
+	}
+	if c.Billing.EnforcementMode == "" {
+		c.Billing.EnforcementMode = EnforcementWarn
+	}
 }
 
 // ApplyEnvOverrides applies environment variable overrides to the cloud config.
@@ -125,12 +617,29 @@ func (c *CloudConfig) ApplyEnvOverrides() {
 	if v := os.Getenv("HANZO_AGENTS_CLOUD_AWS_MACOS_HOST_IDS"); v != "" {
 		c.AWS.MacOS.DedicatedHostIDs = strings.Split(v, ",")
 	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_AWS_AVAILABILITY_ZONES"); v != "" {
+		c.AWS.AvailabilityZones = strings.Split(v, ",")
+	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_AWS_MACOS_MAX_HOSTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.AWS.MacOS.MaxHosts = n
+		}
+	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_AWS_MACOS_AZ_STRATEGY"); v != "" {
+		c.AWS.MacOS.AZStrategy = v
+	}
 	if v := os.Getenv("HANZO_AGENTS_CLOUD_AWS_WINDOWS_AMI"); v != "" {
 		c.AWS.Windows.AMIID = v
 	}
 	if v := os.Getenv("HANZO_AGENTS_CLOUD_AWS_WINDOWS_INSTANCE_TYPE"); v != "" {
 		c.AWS.Windows.DefaultInstanceType = v
 	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_AWS_IMDSV2_REQUIRED"); v != "" {
+		c.AWS.EnforceIMDSv2 = v == "true" || v == "1"
+	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_AWS_ASSUME_ROLE_ARN"); v != "" {
+		c.AWS.AgentAssumeRoleARN = v
+	}
 
 	// K8s overrides
 	if v := os.Getenv("HANZO_AGENTS_CLOUD_K8S_ENABLED"); v != "" {
@@ -150,6 +659,86 @@ func (c *CloudConfig) ApplyEnvOverrides() {
 		}
 	}
 
+	// Azure overrides
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_AZURE_ENABLED"); v != "" {
+		c.Azure.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("AZURE_TENANT_ID"); v != "" {
+		c.Azure.TenantID = v
+	}
+	if v := os.Getenv("AZURE_CLIENT_ID"); v != "" {
+		c.Azure.ClientID = v
+	}
+	if v := os.Getenv("AZURE_CLIENT_SECRET"); v != "" {
+		c.Azure.ClientSecret = v
+	}
+	if v := os.Getenv("AZURE_SUBSCRIPTION_ID"); v != "" {
+		c.Azure.SubscriptionID = v
+	}
+
+	// GCP overrides
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_GCP_ENABLED"); v != "" {
+		c.GCP.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("GOOGLE_CLOUD_PROJECT"); v != "" {
+		c.GCP.ProjectID = v
+	}
+	if v := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); v != "" {
+		c.GCP.CredentialsFile = v
+	}
+
+	// DigitalOcean overrides
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_DIGITALOCEAN_ENABLED"); v != "" {
+		c.DigitalOcean.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("DIGITALOCEAN_TOKEN"); v != "" {
+		c.DigitalOcean.Token = v
+	}
+
+	// Linode overrides
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_LINODE_ENABLED"); v != "" {
+		c.Linode.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("LINODE_TOKEN"); v != "" {
+		c.Linode.Token = v
+	}
+
+	// Hetzner overrides
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_HETZNER_ENABLED"); v != "" {
+		c.Hetzner.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("HETZNER_TOKEN"); v != "" {
+		c.Hetzner.Token = v
+	}
+
+	// Equinix Metal overrides
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_EQUINIX_METAL_ENABLED"); v != "" {
+		c.EquinixMetal.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("EQUINIX_METAL_TOKEN"); v != "" {
+		c.EquinixMetal.Token = v
+	}
+	if v := os.Getenv("EQUINIX_METAL_PROJECT_ID"); v != "" {
+		c.EquinixMetal.ProjectID = v
+	}
+
+	// Broker
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_BROKER_ENABLED"); v != "" {
+		c.Broker.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_BROKER_LISTEN_ADDR"); v != "" {
+		c.Broker.ListenAddr = v
+	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_BROKER_GUACD_ADDR"); v != "" {
+		c.Broker.GuacdAddr = v
+	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_BROKER_TLS_CERT_FILE"); v != "" {
+		c.Broker.TLSCertFile = v
+	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_BROKER_TLS_KEY_FILE"); v != "" {
+		c.Broker.TLSKeyFile = v
+	}
+
 	// Billing
 	if v := os.Getenv("HANZO_AGENTS_CLOUD_BILLING_ENABLED"); v != "" {
 		c.Billing.Enabled = v == "true" || v == "1"
@@ -160,4 +749,7 @@ func (c *CloudConfig) ApplyEnvOverrides() {
 	if v := os.Getenv("HANZO_AGENTS_CLOUD_BILLING_API_KEY"); v != "" {
 		c.Billing.APIKey = v
 	}
+	if v := os.Getenv("HANZO_AGENTS_CLOUD_BILLING_ENFORCEMENT_MODE"); v != "" {
+		c.Billing.EnforcementMode = EnforcementMode(v)
+	}
 }
@@ -0,0 +1,106 @@
+package cloud
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// credentialRefreshSkew is how far ahead of expiry credentials are rotated.
+const credentialRefreshSkew = 2 * time.Minute
+
+// CredentialBroker vends short-lived credentials for a provisioned instance,
+// e.g. by calling a cloud provider's STS-equivalent AssumeRole API. It
+// mirrors BillingAuthorizer so it can be mocked the same way in tests.
+type CredentialBroker interface {
+	// IssueCredentials returns a fresh set of short-lived credentials for instanceID.
+	IssueCredentials(ctx context.Context, instanceID string) (*Credentials, error)
+}
+
+// NoopCredentialBroker never issues credentials; used when credential
+// rotation is disabled for a provider.
+type NoopCredentialBroker struct{}
+
+// IssueCredentials always returns ErrCredentialBrokerDisabled.
+func (NoopCredentialBroker) IssueCredentials(ctx context.Context, instanceID string) (*Credentials, error) {
+	return nil, ErrCredentialBrokerDisabled
+}
+
+// CredentialRefresher rotates CredentialBroker-issued credentials for
+// provisioned instances ahead of expiry, publishing each rotation on the
+// event bus so connected agents can pick up new credentials without
+// reconnecting.
+type CredentialRefresher struct {
+	broker   CredentialBroker
+	eventBus *EventBus
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewCredentialRefresher creates a refresher backed by the given broker.
+func NewCredentialRefresher(broker CredentialBroker, eventBus *EventBus) *CredentialRefresher {
+	return &CredentialRefresher{
+		broker:   broker,
+		eventBus: eventBus,
+		cancels:  make(map[string]context.CancelFunc),
+	}
+}
+
+// Watch starts a background goroutine that keeps instanceID's credentials
+// rotated until StopWatching is called. Calling Watch again for an instance
+// that is already being watched is a no-op.
+func (r *CredentialRefresher) Watch(instanceID string) {
+	r.mu.Lock()
+	if _, ok := r.cancels[instanceID]; ok {
+		r.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancels[instanceID] = cancel
+	r.mu.Unlock()
+
+	go r.run(ctx, instanceID)
+}
+
+// StopWatching cancels credential rotation for instanceID, e.g. once it is
+// terminated.
+func (r *CredentialRefresher) StopWatching(instanceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cancel, ok := r.cancels[instanceID]; ok {
+		cancel()
+		delete(r.cancels, instanceID)
+	}
+}
+
+func (r *CredentialRefresher) run(ctx context.Context, instanceID string) {
+	for {
+		creds, err := r.broker.IssueCredentials(ctx, instanceID)
+		if err != nil {
+			log.Warn().Err(err).Str("instance_id", instanceID).Msg("credential refresher: failed to issue credentials")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(30 * time.Second):
+				continue
+			}
+		}
+
+		r.eventBus.EmitInstanceEvent(EventInstanceCredentialsRotated, instanceID, creds)
+
+		wait := time.Until(creds.Expiry) - credentialRefreshSkew
+		if wait <= 0 {
+			wait = credentialRefreshSkew
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,312 @@
+// Package digitalocean implements the cloud.CloudProvisioner interface for
+// DigitalOcean Droplets.
+package digitalocean
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+const apiBaseURL = "https://api.digitalocean.com/v2"
+
+func init() {
+	cloud.RegisterProviderFactory("digitalocean", func(cfg any) (cloud.CloudProvisioner, error) {
+		doCfg, ok := cfg.(cloud.DigitalOceanConfig)
+		if !ok {
+			return nil, fmt.Errorf("digitalocean: unexpected config type %T", cfg)
+		}
+		return NewProvisioner(doCfg), nil
+	})
+}
+
+// Provisioner implements cloud.CloudProvisioner for DigitalOcean Droplets.
+type Provisioner struct {
+	cfg    cloud.DigitalOceanConfig
+	client *http.Client
+}
+
+// NewProvisioner creates a new DigitalOcean provisioner.
+func NewProvisioner(cfg cloud.DigitalOceanConfig) *Provisioner {
+	return &Provisioner{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *Provisioner) ProviderName() string { return "digitalocean" }
+
+// CreateInstance creates a new Droplet tagged with the cloud instance ID.
+func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRequest) (*cloud.CloudInstance, error) {
+	instanceID := uuid.New().String()
+
+	body := map[string]interface{}{
+		"name":   fmt.Sprintf("hanzo-bot-%s", instanceID[:8]),
+		"region": p.cfg.Region,
+		"size":   p.cfg.Size,
+		"image":  p.cfg.Image,
+		"tags": []string{
+			"hanzo-agent-bot",
+			"hanzo-instance-" + instanceID,
+			"hanzo-team-" + req.TeamID,
+		},
+	}
+
+	var result struct {
+		Droplet struct {
+			ID int `json:"id"`
+		} `json:"droplet"`
+	}
+	if err := p.doRequest(ctx, http.MethodPost, "/droplets", body, &result); err != nil {
+		return nil, &cloud.ProvisionError{
+			InstanceID: instanceID,
+			Platform:   cloud.PlatformLinux,
+			Provider:   "digitalocean",
+			Err:        err,
+		}
+	}
+
+	log.Info().Int("droplet_id", result.Droplet.ID).Str("instance_id", instanceID).Msg("DigitalOcean droplet created")
+
+	now := time.Now().UTC()
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        cloud.InstanceStateProvisioning,
+		Provider:     "digitalocean",
+		InstanceID:   strconv.Itoa(result.Droplet.ID),
+		InstanceType: p.cfg.Size,
+		ImageID:      p.cfg.Image,
+		Region:       p.cfg.Region,
+		BotPackage:   req.BotPackage,
+		BotVersion:   req.BotVersion,
+		TeamID:       req.TeamID,
+		Tags:         req.Tags,
+		RequestedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// GetInstance returns the current state of a Droplet by its cloud instance tag.
+func (p *Provisioner) GetInstance(ctx context.Context, instanceID string) (*cloud.CloudInstance, error) {
+	droplet, err := p.findDropletByTag(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return dropletToInstance(instanceID, droplet), nil
+}
+
+// ListInstances returns Droplets matching filters.
+func (p *Provisioner) ListInstances(ctx context.Context, filters cloud.InstanceFilters) ([]*cloud.CloudInstance, error) {
+	var result struct {
+		Droplets []dropletResource `json:"droplets"`
+	}
+	if err := p.doRequest(ctx, http.MethodGet, "/droplets?tag_name=hanzo-agent-bot", nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list droplets: %w", err)
+	}
+
+	var instances []*cloud.CloudInstance
+	for _, d := range result.Droplets {
+		instanceID := tagValue(d.Tags, "hanzo-instance-")
+		teamID := tagValue(d.Tags, "hanzo-team-")
+		if filters.TeamID != nil && teamID != *filters.TeamID {
+			continue
+		}
+		inst := dropletToInstance(instanceID, &d)
+		if filters.State != nil && inst.State != *filters.State {
+			continue
+		}
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
+
+func (p *Provisioner) StartInstance(ctx context.Context, instanceID string) error {
+	return p.dropletAction(ctx, instanceID, "power_on")
+}
+
+func (p *Provisioner) StopInstance(ctx context.Context, instanceID string) error {
+	return p.dropletAction(ctx, instanceID, "power_off")
+}
+
+// TerminateInstance destroys the Droplet.
+func (p *Provisioner) TerminateInstance(ctx context.Context, instanceID string) error {
+	droplet, err := p.findDropletByTag(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	if err := p.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/droplets/%d", droplet.ID), nil, nil); err != nil {
+		return fmt.Errorf("failed to destroy droplet %d: %w", droplet.ID, err)
+	}
+
+	log.Info().Int("droplet_id", droplet.ID).Str("instance_id", instanceID).Msg("DigitalOcean droplet destroyed")
+	return nil
+}
+
+// GetConnectionInfo returns SSH connection details for the Droplet.
+func (p *Provisioner) GetConnectionInfo(ctx context.Context, instanceID string) (*cloud.ConnectionInfo, error) {
+	droplet, err := p.findDropletByTag(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloud.ConnectionInfo{
+		Protocol: cloud.ConnectionProtocolSSH,
+		Host:     dropletPublicIP(droplet),
+		Port:     22,
+		Username: "root",
+	}, nil
+}
+
+// ExecuteCommand is not supported directly over the DigitalOcean API; callers
+// should connect over the SSH info returned by GetConnectionInfo.
+func (p *Provisioner) ExecuteCommand(ctx context.Context, instanceID, command string) (*cloud.CommandResult, error) {
+	return nil, fmt.Errorf("digitalocean: ExecuteCommand requires an SSH connection, see GetConnectionInfo")
+}
+
+// GetLogs is not supported directly over the DigitalOcean API.
+func (p *Provisioner) GetLogs(ctx context.Context, instanceID string, lines int) (string, error) {
+	return "", fmt.Errorf("digitalocean: GetLogs requires an SSH connection, see GetConnectionInfo")
+}
+
+func (p *Provisioner) dropletAction(ctx context.Context, instanceID, action string) error {
+	droplet, err := p.findDropletByTag(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]string{"type": action}
+	path := fmt.Sprintf("/droplets/%d/actions", droplet.ID)
+	if err := p.doRequest(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("failed to %s droplet %d: %w", action, droplet.ID, err)
+	}
+	return nil
+}
+
+func (p *Provisioner) findDropletByTag(ctx context.Context, instanceID string) (*dropletResource, error) {
+	var result struct {
+		Droplets []dropletResource `json:"droplets"`
+	}
+	path := "/droplets?tag_name=" + "hanzo-instance-" + instanceID
+	if err := p.doRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to look up droplet: %w", err)
+	}
+	if len(result.Droplets) == 0 {
+		return nil, cloud.ErrInstanceNotFound
+	}
+	return &result.Droplets[0], nil
+}
+
+func (p *Provisioner) doRequest(ctx context.Context, method, path string, payload interface{}, out interface{}) error {
+	var bodyReader io.Reader
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		bodyReader = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiBaseURL+path, bodyReader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.cfg.Token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("digitalocean API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("digitalocean API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// dropletResource is the subset of the DigitalOcean droplet API response we care about.
+type dropletResource struct {
+	ID       int      `json:"id"`
+	Status   string   `json:"status"`
+	SizeSlug string   `json:"size_slug"`
+	Image    struct {
+		Slug string `json:"slug"`
+	} `json:"image"`
+	Region struct {
+		Slug string `json:"slug"`
+	} `json:"region"`
+	Tags    []string `json:"tags"`
+	Networks struct {
+		V4 []struct {
+			IPAddress string `json:"ip_address"`
+			Type      string `json:"type"`
+		} `json:"v4"`
+	} `json:"networks"`
+}
+
+func dropletToInstance(instanceID string, d *dropletResource) *cloud.CloudInstance {
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        dropletStatusToState(d.Status),
+		Provider:     "digitalocean",
+		InstanceID:   strconv.Itoa(d.ID),
+		InstanceType: d.SizeSlug,
+		ImageID:      d.Image.Slug,
+		Region:       d.Region.Slug,
+		PublicIP:     dropletPublicIP(d),
+		TeamID:       tagValue(d.Tags, "hanzo-team-"),
+	}
+}
+
+func dropletPublicIP(d *dropletResource) string {
+	for _, n := range d.Networks.V4 {
+		if n.Type == "public" {
+			return n.IPAddress
+		}
+	}
+	return ""
+}
+
+func tagValue(tags []string, prefix string) string {
+	for _, t := range tags {
+		if len(t) > len(prefix) && t[:len(prefix)] == prefix {
+			return t[len(prefix):]
+		}
+	}
+	return ""
+}
+
+func dropletStatusToState(status string) cloud.InstanceState {
+	switch status {
+	case "new":
+		return cloud.InstanceStateProvisioning
+	case "active":
+		return cloud.InstanceStateRunning
+	case "off":
+		return cloud.InstanceStateStopped
+	case "archive":
+		return cloud.InstanceStateTerminated
+	default:
+		return cloud.InstanceStateFailed
+	}
+}
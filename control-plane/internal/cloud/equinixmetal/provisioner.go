@@ -0,0 +1,328 @@
+// Package equinixmetal implements the cloud.CloudProvisioner interface for
+// Equinix Metal bare-metal devices, for teams that need non-AWS bare metal
+// (e.g. Apple silicon bare metal plans for macOS bot workloads).
+package equinixmetal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+const apiBaseURL = "https://api.equinix.com/metal/v1"
+
+func init() {
+	cloud.RegisterProviderFactory("equinixmetal", func(cfg any) (cloud.CloudProvisioner, error) {
+		emCfg, ok := cfg.(cloud.EquinixMetalConfig)
+		if !ok {
+			return nil, fmt.Errorf("equinixmetal: unexpected config type %T", cfg)
+		}
+		return NewProvisioner(emCfg), nil
+	})
+}
+
+// Provisioner implements cloud.CloudProvisioner for Equinix Metal devices.
+type Provisioner struct {
+	cfg    cloud.EquinixMetalConfig
+	client *http.Client
+}
+
+// NewProvisioner creates a new Equinix Metal provisioner.
+func NewProvisioner(cfg cloud.EquinixMetalConfig) *Provisioner {
+	return &Provisioner{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *Provisioner) ProviderName() string { return "equinixmetal" }
+
+// CreateInstance creates a new Equinix Metal device tagged with the cloud
+// instance ID. req.Platform is recorded but otherwise ignored: the device
+// plan and operating system come from config, since a single Equinix Metal
+// account typically reserves distinct plans per platform up front.
+func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRequest) (*cloud.CloudInstance, error) {
+	instanceID := uuid.New().String()
+
+	body := map[string]interface{}{
+		"hostname":         fmt.Sprintf("hanzo-bot-%s", instanceID[:8]),
+		"plan":             p.cfg.Plan,
+		"metro":            p.cfg.Metro,
+		"operating_system": p.cfg.OS,
+		"tags": []string{
+			"hanzo-agent-bot",
+			"hanzo-instance-" + instanceID,
+			"hanzo-team-" + req.TeamID,
+		},
+	}
+
+	var device deviceResource
+	path := fmt.Sprintf("/projects/%s/devices", p.cfg.ProjectID)
+	if err := p.doRequest(ctx, http.MethodPost, path, body, &device); err != nil {
+		return nil, &cloud.ProvisionError{
+			InstanceID: instanceID,
+			Platform:   req.Platform,
+			Provider:   "equinixmetal",
+			Err:        err,
+		}
+	}
+
+	log.Info().Str("device_id", device.ID).Str("instance_id", instanceID).Msg("Equinix Metal device created")
+
+	now := time.Now().UTC()
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     req.Platform,
+		State:        deviceStateToState(device.State),
+		Provider:     "equinixmetal",
+		InstanceID:   device.ID,
+		InstanceType: p.cfg.Plan,
+		ImageID:      p.cfg.OS,
+		Region:       p.cfg.Metro,
+		BotPackage:   req.BotPackage,
+		BotVersion:   req.BotVersion,
+		TeamID:       req.TeamID,
+		Tags:         req.Tags,
+		RequestedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// GetInstance returns the current state of a device by its instance tag.
+func (p *Provisioner) GetInstance(ctx context.Context, instanceID string) (*cloud.CloudInstance, error) {
+	device, err := p.findDeviceByTag(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return deviceToInstance(instanceID, device), nil
+}
+
+// ListInstances returns Equinix Metal devices matching filters.
+func (p *Provisioner) ListInstances(ctx context.Context, filters cloud.InstanceFilters) ([]*cloud.CloudInstance, error) {
+	var result struct {
+		Devices []deviceResource `json:"devices"`
+	}
+	path := fmt.Sprintf("/projects/%s/devices", p.cfg.ProjectID)
+	if err := p.doRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	var instances []*cloud.CloudInstance
+	for _, d := range result.Devices {
+		if !hasTag(d.Tags, "hanzo-agent-bot") {
+			continue
+		}
+		instanceID := tagValue(d.Tags, "hanzo-instance-")
+		teamID := tagValue(d.Tags, "hanzo-team-")
+		if filters.TeamID != nil && teamID != *filters.TeamID {
+			continue
+		}
+		inst := deviceToInstance(instanceID, &d)
+		if filters.State != nil && inst.State != *filters.State {
+			continue
+		}
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
+
+func (p *Provisioner) StartInstance(ctx context.Context, instanceID string) error {
+	return p.deviceAction(ctx, instanceID, "power_on")
+}
+
+func (p *Provisioner) StopInstance(ctx context.Context, instanceID string) error {
+	return p.deviceAction(ctx, instanceID, "power_off")
+}
+
+// TerminateInstance deletes the device.
+func (p *Provisioner) TerminateInstance(ctx context.Context, instanceID string) error {
+	device, err := p.findDeviceByTag(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	if err := p.doRequest(ctx, http.MethodDelete, "/devices/"+device.ID, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete device %s: %w", device.ID, err)
+	}
+
+	log.Info().Str("device_id", device.ID).Str("instance_id", instanceID).Msg("Equinix Metal device deleted")
+	return nil
+}
+
+// GetConnectionInfo returns SSH connection details for the device.
+func (p *Provisioner) GetConnectionInfo(ctx context.Context, instanceID string) (*cloud.ConnectionInfo, error) {
+	device, err := p.findDeviceByTag(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloud.ConnectionInfo{
+		Protocol: cloud.ConnectionProtocolSSH,
+		Host:     devicePublicIP(device),
+		Port:     22,
+		Username: "root",
+	}, nil
+}
+
+// ExecuteCommand is not supported directly over the Equinix Metal API;
+// callers should connect over the SSH info returned by GetConnectionInfo.
+func (p *Provisioner) ExecuteCommand(ctx context.Context, instanceID, command string) (*cloud.CommandResult, error) {
+	return nil, fmt.Errorf("equinixmetal: ExecuteCommand requires an SSH connection, see GetConnectionInfo")
+}
+
+// GetLogs is not supported directly over the Equinix Metal API.
+func (p *Provisioner) GetLogs(ctx context.Context, instanceID string, lines int) (string, error) {
+	return "", fmt.Errorf("equinixmetal: GetLogs requires an SSH connection, see GetConnectionInfo")
+}
+
+func (p *Provisioner) deviceAction(ctx context.Context, instanceID, action string) error {
+	device, err := p.findDeviceByTag(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]string{"type": action}
+	path := fmt.Sprintf("/devices/%s/actions", device.ID)
+	if err := p.doRequest(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("failed to %s device %s: %w", action, device.ID, err)
+	}
+	return nil
+}
+
+func (p *Provisioner) findDeviceByTag(ctx context.Context, instanceID string) (*deviceResource, error) {
+	var result struct {
+		Devices []deviceResource `json:"devices"`
+	}
+	path := fmt.Sprintf("/projects/%s/devices", p.cfg.ProjectID)
+	if err := p.doRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to look up device: %w", err)
+	}
+	for _, d := range result.Devices {
+		if tagValue(d.Tags, "hanzo-instance-") == instanceID {
+			return &d, nil
+		}
+	}
+	return nil, cloud.ErrInstanceNotFound
+}
+
+func (p *Provisioner) doRequest(ctx context.Context, method, path string, payload interface{}, out interface{}) error {
+	var bodyReader io.Reader
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		bodyReader = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiBaseURL+path, bodyReader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Auth-Token", p.cfg.Token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("equinix metal API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("equinix metal API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// deviceResource is the subset of the Equinix Metal device API response we care about.
+type deviceResource struct {
+	ID    string   `json:"id"`
+	State string   `json:"state"`
+	Plan  struct {
+		Slug string `json:"slug"`
+	} `json:"plan"`
+	Metro struct {
+		Code string `json:"code"`
+	} `json:"metro"`
+	OperatingSystem struct {
+		Slug string `json:"slug"`
+	} `json:"operating_system"`
+	Tags        []string `json:"tags"`
+	IPAddresses []struct {
+		Address       string `json:"address"`
+		Public        bool   `json:"public"`
+		AddressFamily int    `json:"address_family"`
+	} `json:"ip_addresses"`
+}
+
+func deviceToInstance(instanceID string, d *deviceResource) *cloud.CloudInstance {
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        deviceStateToState(d.State),
+		Provider:     "equinixmetal",
+		InstanceID:   d.ID,
+		InstanceType: d.Plan.Slug,
+		ImageID:      d.OperatingSystem.Slug,
+		Region:       d.Metro.Code,
+		PublicIP:     devicePublicIP(d),
+		TeamID:       tagValue(d.Tags, "hanzo-team-"),
+	}
+}
+
+func devicePublicIP(d *deviceResource) string {
+	for _, ip := range d.IPAddresses {
+		if ip.Public && ip.AddressFamily == 4 {
+			return ip.Address
+		}
+	}
+	return ""
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func tagValue(tags []string, prefix string) string {
+	for _, t := range tags {
+		if len(t) > len(prefix) && t[:len(prefix)] == prefix {
+			return t[len(prefix):]
+		}
+	}
+	return ""
+}
+
+func deviceStateToState(state string) cloud.InstanceState {
+	switch state {
+	case "queued", "provisioning":
+		return cloud.InstanceStateProvisioning
+	case "active":
+		return cloud.InstanceStateRunning
+	case "inactive":
+		return cloud.InstanceStateStopped
+	case "deprovisioning":
+		return cloud.InstanceStateTerminated
+	default:
+		return cloud.InstanceStateFailed
+	}
+}
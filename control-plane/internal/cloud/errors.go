@@ -36,6 +36,10 @@ var (
 	// ErrHostMinAllocation is returned when trying to release a host before minimum allocation.
 	ErrHostMinAllocation = errors.New("dedicated host minimum allocation period not met")
 
+	// ErrHostCeilingReached is returned when auto-allocating a new Dedicated
+	// Host would exceed AWSMacOSConfig.MaxHosts for the region.
+	ErrHostCeilingReached = errors.New("dedicated host ceiling reached for region")
+
 	// ErrBillingNotAuthorized is returned when billing check denies provisioning.
 	ErrBillingNotAuthorized = errors.New("billing authorization denied")
 
@@ -44,6 +48,22 @@ var (
 
 	// ErrBillingServiceUnavailable is returned when the billing service is unreachable.
 	ErrBillingServiceUnavailable = errors.New("billing service unavailable")
+
+	// ErrCredentialBrokerDisabled is returned when credential issuance is requested
+	// but no CredentialBroker is configured for the instance's provider.
+	ErrCredentialBrokerDisabled = errors.New("credential broker disabled")
+
+	// ErrTaskNotFound is returned when a task ID has no matching Task, either
+	// because it never existed or because it aged out of the TaskManager.
+	ErrTaskNotFound = errors.New("task not found")
+
+	// ErrAccountFrozen is returned when provisioning is requested for a
+	// team with an active AccountFreeze.
+	ErrAccountFrozen = errors.New("account is frozen")
+
+	// ErrHoldDenied is returned when a HoldService declines to reserve an
+	// instance's estimated cost (e.g. insufficient funds).
+	ErrHoldDenied = errors.New("billing hold denied")
 )
 
 // ProvisionError wraps an error with provisioning context.
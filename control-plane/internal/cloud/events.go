@@ -8,33 +8,66 @@ import (
 	"github.com/google/uuid"
 )
 
-// EventBus provides publish/subscribe for cloud events.
+// cloudEventsSpecVersion is the CloudEvents spec version stamped on every
+// event this bus publishes. See https://cloudevents.io.
+const cloudEventsSpecVersion = "1.0"
+
+// EventBus provides publish/subscribe for cloud events, fanning each
+// Publish out to in-process subscribers and to every registered Sink.
+// Events conform to the CNCF CloudEvents 1.0 spec so a Sink can hand them
+// to any standard CloudEvents receiver without custom bridging.
 type EventBus struct {
+	// source is stamped on every event's CloudEvents "source" attribute
+	// unless the event already sets one. EmitPlatformEvent overrides it
+	// per-event with "/cloud/<platform>".
+	source string
+
 	mu          sync.RWMutex
 	subscribers map[string]chan CloudEvent
 	buffer      []CloudEvent
 	bufferSize  int
+
+	sinksMu sync.RWMutex
+	sinks   map[string]*sinkWorker
 }
 
-// NewEventBus creates a new event bus with the given buffer size.
-func NewEventBus(bufferSize int) *EventBus {
+// NewEventBus creates a new event bus with the given buffer size and
+// default CloudEvents source, e.g. "/cloud/hanzo-agents". An empty source
+// defaults to "/cloud".
+func NewEventBus(bufferSize int, source string) *EventBus {
 	if bufferSize <= 0 {
 		bufferSize = 100
 	}
+	if source == "" {
+		source = "/cloud"
+	}
 	return &EventBus{
+		source:      source,
 		subscribers: make(map[string]chan CloudEvent),
 		buffer:      make([]CloudEvent, 0, bufferSize),
 		bufferSize:  bufferSize,
+		sinks:       make(map[string]*sinkWorker),
 	}
 }
 
-// Publish sends an event to all subscribers.
+// Publish sends an event to all subscribers and to every registered Sink.
+// Any CloudEvents attribute the caller left zero (SpecVersion, ID, Source,
+// Time, DataContentType) is stamped with its default.
 func (eb *EventBus) Publish(event CloudEvent) {
+	if event.SpecVersion == "" {
+		event.SpecVersion = cloudEventsSpecVersion
+	}
 	if event.ID == "" {
 		event.ID = uuid.New().String()
 	}
-	if event.Timestamp.IsZero() {
-		event.Timestamp = time.Now().UTC()
+	if event.Source == "" {
+		event.Source = eb.source
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if event.DataContentType == "" && len(event.Data) > 0 {
+		event.DataContentType = "application/json"
 	}
 
 	eb.mu.Lock()
@@ -58,6 +91,46 @@ func (eb *EventBus) Publish(event CloudEvent) {
 			// Drop if subscriber is slow.
 		}
 	}
+
+	eb.sinksMu.RLock()
+	sinks := make([]*sinkWorker, 0, len(eb.sinks))
+	for _, w := range eb.sinks {
+		sinks = append(sinks, w)
+	}
+	eb.sinksMu.RUnlock()
+
+	for _, w := range sinks {
+		w.enqueue(event)
+	}
+}
+
+// RegisterSink attaches a Sink under name, delivering every event published
+// from this point on through its own bounded queue and retry loop.
+// Registering a second Sink under a name already in use stops and replaces
+// the first.
+func (eb *EventBus) RegisterSink(name string, s Sink) {
+	w := newSinkWorker(name, s, defaultSinkConfig)
+
+	eb.sinksMu.Lock()
+	old := eb.sinks[name]
+	eb.sinks[name] = w
+	eb.sinksMu.Unlock()
+
+	if old != nil {
+		old.stop()
+	}
+}
+
+// UnregisterSink stops and removes the named Sink.
+func (eb *EventBus) UnregisterSink(name string) {
+	eb.sinksMu.Lock()
+	w, ok := eb.sinks[name]
+	delete(eb.sinks, name)
+	eb.sinksMu.Unlock()
+
+	if ok {
+		w.stop()
+	}
 }
 
 // Subscribe returns a channel for receiving events.
@@ -97,8 +170,23 @@ func (eb *EventBus) Recent(limit int) []CloudEvent {
 	return result
 }
 
-// EmitInstanceEvent is a convenience method for publishing instance lifecycle events.
+// EmitInstanceEvent is a convenience method for publishing instance
+// lifecycle events. It stamps subject=instanceID and leaves source at the
+// bus's default; use EmitPlatformEvent instead when the event concerns one
+// specific cloud platform.
 func (eb *EventBus) EmitInstanceEvent(eventType, instanceID string, data interface{}) {
+	eb.emitEvent(eventType, "", instanceID, data)
+}
+
+// EmitPlatformEvent is like EmitInstanceEvent but stamps source as
+// "/cloud/<platform>" instead of the bus's default, for events scoped to
+// one specific cloud platform (instance state transitions, terminations,
+// spot interruptions, and the like).
+func (eb *EventBus) EmitPlatformEvent(eventType string, platform Platform, instanceID string, data interface{}) {
+	eb.emitEvent(eventType, "/cloud/"+string(platform), instanceID, data)
+}
+
+func (eb *EventBus) emitEvent(eventType, source, instanceID string, data interface{}) {
 	var rawData json.RawMessage
 	if data != nil {
 		if b, err := json.Marshal(data); err == nil {
@@ -107,9 +195,10 @@ func (eb *EventBus) EmitInstanceEvent(eventType, instanceID string, data interfa
 	}
 
 	eb.Publish(CloudEvent{
+		Source:     source,
 		Type:       eventType,
+		Subject:    instanceID,
 		InstanceID: instanceID,
-		Timestamp:  time.Now().UTC(),
 		Data:       rawData,
 	})
 }
@@ -125,4 +214,39 @@ const (
 	EventInstanceConnected    = "instance.connected" // agent registered
 	EventHostAllocated        = "host.allocated"
 	EventHostReleased         = "host.released"
+
+	EventInstanceCredentialsRotated = "instance.credentials_rotated"
+
+	EventSessionOpened = "session.opened"
+	EventSessionClosed = "session.closed"
+
+	EventBudgetWarning  = "budget.warning"
+	EventBudgetExceeded = "budget.exceeded"
+
+	EventInstanceInterruptionWarning = "instance.interruption_warning"
+
+	// EventInstanceReconciled fires when the reconcile loop re-imports an
+	// instance that exists in the provider but was missing from storage.
+	EventInstanceReconciled = "instance.reconciled"
+	// EventInstanceOrphaned fires when the reconcile loop finds an instance
+	// storage still considers live but the provider no longer has, and marks
+	// it terminated.
+	EventInstanceOrphaned = "instance.orphaned"
+
+	// EventAccountFrozen fires when AccountFreezeService places a new
+	// freeze on an account, whether manually or via auto-freeze.
+	EventAccountFrozen = "account.frozen"
+	// EventAccountUnfrozen fires when a freeze is lifted, whether manually
+	// or via auto-unfreeze on a successful top-up.
+	EventAccountUnfrozen = "account.unfrozen"
+	// EventAccountFreezeEscalated fires when a freeze's grace period
+	// elapses without resolution and the reconciler suspends or
+	// terminates the account's running instances immediately.
+	EventAccountFreezeEscalated = "account.freeze_escalated"
+
+	// EventWindowsPasswordReady fires once a Windows instance's admin
+	// password has finished generating and GetPasswordData stops returning
+	// empty, so the UI can enable its "Connect via RDP" button without
+	// polling GetInstanceDetailsHandler.
+	EventWindowsPasswordReady = "windows.password_ready"
 )
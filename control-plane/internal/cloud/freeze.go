@@ -0,0 +1,275 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/services/ledger"
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+	"github.com/hanzoai/agents/control-plane/pkg/types"
+)
+
+// AccountFreezeService places and lifts freezes on an account's cloud
+// access: a frozen account can't provision new instances
+// (CloudManager.CreateInstance), and FreezeReconciler suspends or
+// terminates its already-running instances once the freeze's grace period
+// elapses.
+type AccountFreezeService struct {
+	store    storage.StorageProvider
+	eventBus *EventBus
+	config   FreezeConfig
+	ledger   *ledger.Ledger
+
+	mu                  sync.Mutex
+	consecutiveFailures map[string]int // userID -> consecutive ErrInsufficientFunds count
+}
+
+// NewAccountFreezeService creates a new AccountFreezeService.
+func NewAccountFreezeService(store storage.StorageProvider, eventBus *EventBus, cfg FreezeConfig) *AccountFreezeService {
+	return &AccountFreezeService{
+		store:               store,
+		eventBus:            eventBus,
+		config:              cfg,
+		consecutiveFailures: make(map[string]int),
+	}
+}
+
+// SetLedger installs a Ledger so Freeze/Unfreeze append a FreezeAdjustment
+// audit entry alongside the freeze state change. Defaults to unset, in
+// which case freezes aren't reflected in the billing ledger at all
+// (Ledger.Record is nil-safe, so this works either way).
+func (s *AccountFreezeService) SetLedger(l *ledger.Ledger) {
+	s.ledger = l
+}
+
+func (s *AccountFreezeService) gracePeriod(freezeType types.FreezeType) time.Duration {
+	switch freezeType {
+	case types.FreezeBilling:
+		return s.config.BillingGracePeriod
+	case types.FreezeViolation:
+		return s.config.ViolationGracePeriod
+	case types.FreezeLegal:
+		return s.config.LegalGracePeriod
+	default:
+		return 0
+	}
+}
+
+// Freeze places a new freeze of freezeType on userID, replacing any
+// existing active freeze. metadata is marshaled as-is into
+// AccountFreeze.Metadata.
+func (s *AccountFreezeService) Freeze(ctx context.Context, userID string, freezeType types.FreezeType, reason string, metadata interface{}) (*types.AccountFreeze, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("cloud: userID is required to freeze an account")
+	}
+
+	var metaBytes json.RawMessage
+	if metadata != nil {
+		b, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, fmt.Errorf("cloud: failed to marshal freeze metadata: %w", err)
+		}
+		metaBytes = b
+	}
+
+	now := time.Now().UTC()
+	freeze := &types.AccountFreeze{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Type:        freezeType,
+		Reason:      reason,
+		Metadata:    metaBytes,
+		GracePeriod: s.gracePeriod(freezeType),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.store.CreateAccountFreeze(ctx, freeze); err != nil {
+		return nil, fmt.Errorf("cloud: failed to persist account freeze: %w", err)
+	}
+
+	log.Warn().Str("user_id", userID).Str("type", string(freezeType)).Str("reason", reason).Msg("account frozen")
+	s.eventBus.EmitInstanceEvent(EventAccountFrozen, "", map[string]interface{}{
+		"freeze_id": freeze.ID,
+		"user_id":   userID,
+		"team_id":   userID,
+		"type":      string(freezeType),
+		"reason":    reason,
+	})
+
+	// A freeze doesn't move money, so this entry carries no AmountCents —
+	// it's an audit marker recording when and why the account's ledger
+	// account stopped being able to accrue new holds/debits.
+	if _, err := s.ledger.Record(ctx, ledger.RecordParams{
+		User:         userID,
+		Action:       types.LedgerActionFreezeAdjustment,
+		DebitAccount: ledger.UserAccount(userID),
+		Metadata:     map[string]interface{}{"freeze_id": freeze.ID, "type": string(freezeType), "reason": reason},
+	}); err != nil {
+		log.Error().Err(err).Str("user_id", userID).Msg("failed to record freeze in billing ledger")
+	}
+
+	return freeze, nil
+}
+
+// Unfreeze lifts userID's active freeze, if any. It is not an error to
+// unfreeze an account that isn't frozen.
+func (s *AccountFreezeService) Unfreeze(ctx context.Context, userID string) error {
+	freeze, err := s.store.GetActiveAccountFreeze(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("cloud: failed to look up active freeze: %w", err)
+	}
+	if freeze == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	freeze.UnfrozenAt = &now
+	freeze.UpdatedAt = now
+	if err := s.store.UpdateAccountFreeze(ctx, freeze); err != nil {
+		return fmt.Errorf("cloud: failed to persist account unfreeze: %w", err)
+	}
+
+	s.mu.Lock()
+	delete(s.consecutiveFailures, userID)
+	s.mu.Unlock()
+
+	log.Info().Str("user_id", userID).Str("freeze_id", freeze.ID).Msg("account unfrozen")
+	s.eventBus.EmitInstanceEvent(EventAccountUnfrozen, "", map[string]interface{}{
+		"freeze_id": freeze.ID,
+		"user_id":   userID,
+		"team_id":   userID,
+		"type":      string(freeze.Type),
+	})
+
+	if _, err := s.ledger.Record(ctx, ledger.RecordParams{
+		User:          userID,
+		Action:        types.LedgerActionFreezeAdjustment,
+		CreditAccount: ledger.UserAccount(userID),
+		Metadata:      map[string]interface{}{"freeze_id": freeze.ID, "type": string(freeze.Type), "unfrozen": true},
+	}); err != nil {
+		log.Error().Err(err).Str("user_id", userID).Msg("failed to record unfreeze in billing ledger")
+	}
+
+	return nil
+}
+
+// Escalate marks userID's active freeze as escalated, so FreezeReconciler
+// suspends or terminates its running instances immediately instead of
+// waiting out the remainder of the grace period. Used when a grace period
+// expires without resolution (see FreezeReconciler) or an admin wants to
+// act immediately on a violation/legal freeze.
+func (s *AccountFreezeService) Escalate(ctx context.Context, userID string) error {
+	freeze, err := s.store.GetActiveAccountFreeze(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("cloud: failed to look up active freeze: %w", err)
+	}
+	if freeze == nil {
+		return fmt.Errorf("cloud: no active freeze for user %s", userID)
+	}
+	if freeze.EscalatedAt != nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	freeze.EscalatedAt = &now
+	freeze.UpdatedAt = now
+	if err := s.store.UpdateAccountFreeze(ctx, freeze); err != nil {
+		return fmt.Errorf("cloud: failed to persist freeze escalation: %w", err)
+	}
+
+	log.Warn().Str("user_id", userID).Str("freeze_id", freeze.ID).Msg("account freeze escalated")
+	s.eventBus.EmitInstanceEvent(EventAccountFreezeEscalated, "", map[string]interface{}{
+		"freeze_id": freeze.ID,
+		"user_id":   userID,
+		"team_id":   userID,
+		"type":      string(freeze.Type),
+	})
+
+	return nil
+}
+
+// List returns freezes for userID, or every account's freezes if userID is
+// empty. If activeOnly is set, only freezes that haven't been lifted are
+// returned.
+func (s *AccountFreezeService) List(ctx context.Context, userID string, activeOnly bool) ([]*types.AccountFreeze, error) {
+	freezes, err := s.store.ListAccountFreezes(ctx, userID, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("cloud: failed to list account freezes: %w", err)
+	}
+	return freezes, nil
+}
+
+// IsFrozen reports whether userID has an active freeze.
+func (s *AccountFreezeService) IsFrozen(ctx context.Context, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	freeze, err := s.store.GetActiveAccountFreeze(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("cloud: failed to check freeze state: %w", err)
+	}
+	return freeze.IsActive(), nil
+}
+
+// RecordInsufficientFunds tracks a consecutive services.ErrInsufficientFunds
+// result for userID, auto-applying a FreezeBilling once
+// FreezeConfig.AutoFreezeThreshold consecutive failures are seen. The
+// counter resets on RecordSuccessfulTopUp.
+func (s *AccountFreezeService) RecordInsufficientFunds(ctx context.Context, userID string) error {
+	if userID == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.consecutiveFailures[userID]++
+	count := s.consecutiveFailures[userID]
+	s.mu.Unlock()
+
+	threshold := s.config.AutoFreezeThreshold
+	if threshold <= 0 || count < threshold {
+		return nil
+	}
+
+	already, err := s.IsFrozen(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if already {
+		return nil
+	}
+
+	reason := fmt.Sprintf("auto-frozen after %d consecutive insufficient-funds results", count)
+	_, err = s.Freeze(ctx, userID, types.FreezeBilling, reason, nil)
+	return err
+}
+
+// RecordSuccessfulTopUp resets userID's consecutive-failure counter and, if
+// userID is frozen for billing reasons, auto-unfreezes it. A
+// ViolationFreeze or LegalFreeze on the same account is left in place -
+// paying a balance down doesn't resolve a ToS violation or legal hold.
+func (s *AccountFreezeService) RecordSuccessfulTopUp(ctx context.Context, userID string) error {
+	if userID == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	delete(s.consecutiveFailures, userID)
+	s.mu.Unlock()
+
+	freeze, err := s.store.GetActiveAccountFreeze(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("cloud: failed to look up active freeze: %w", err)
+	}
+	if freeze == nil || freeze.Type != types.FreezeBilling {
+		return nil
+	}
+
+	return s.Unfreeze(ctx, userID)
+}
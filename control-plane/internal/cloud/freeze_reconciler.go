@@ -0,0 +1,119 @@
+package cloud
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/pkg/types"
+)
+
+// FreezeReconciler periodically checks active account freezes and, once a
+// freeze's grace period has elapsed, suspends or terminates the frozen
+// account's running instances. AccountFreeze.UserID is matched against
+// CloudInstance.TeamID, since ProvisionRequest (and therefore
+// CloudInstance) has no separate concept of user - provisioning is already
+// scoped by team everywhere else in this package.
+type FreezeReconciler struct {
+	manager *CloudManager
+	freezes *AccountFreezeService
+	config  CloudConfig
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewFreezeReconciler creates a new freeze reconciler.
+func NewFreezeReconciler(manager *CloudManager, freezes *AccountFreezeService, cfg CloudConfig) *FreezeReconciler {
+	return &FreezeReconciler{
+		manager: manager,
+		freezes: freezes,
+		config:  cfg,
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start begins the reconciler loop.
+func (r *FreezeReconciler) Start() {
+	interval := r.config.Freeze.ReconcileInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", interval).Msg("account freeze reconciler started")
+
+	for {
+		select {
+		case <-r.stopCh:
+			log.Info().Msg("account freeze reconciler stopped")
+			return
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+// Stop terminates the reconciler loop.
+func (r *FreezeReconciler) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+func (r *FreezeReconciler) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	active, err := r.freezes.List(ctx, "", true)
+	if err != nil {
+		log.Error().Err(err).Msg("freeze reconciler: failed to list active freezes")
+		return
+	}
+
+	for _, freeze := range active {
+		r.reconcileOne(ctx, freeze)
+	}
+}
+
+func (r *FreezeReconciler) reconcileOne(ctx context.Context, freeze *types.AccountFreeze) {
+	due := freeze.EscalatedAt != nil || time.Since(freeze.CreatedAt) >= freeze.GracePeriod
+	if !due {
+		return
+	}
+
+	runningState := InstanceStateRunning
+	instances, err := r.manager.store.ListCloudInstances(ctx, InstanceFilters{TeamID: &freeze.UserID, State: &runningState})
+	if err != nil {
+		log.Error().Err(err).Str("user_id", freeze.UserID).Msg("freeze reconciler: failed to list running instances")
+		return
+	}
+	if len(instances) == 0 {
+		return
+	}
+
+	// A legal hold terminates outright once due; billing and violation
+	// freezes suspend first, leaving the instance recoverable on unfreeze.
+	terminate := freeze.Type == types.FreezeLegal
+
+	for _, inst := range instances {
+		if terminate {
+			log.Warn().Str("user_id", freeze.UserID).Str("instance_id", inst.ID).Str("freeze_type", string(freeze.Type)).
+				Msg("freeze reconciler: terminating instance for frozen account")
+			if err := r.manager.TerminateInstance(ctx, inst.ID); err != nil {
+				log.Error().Err(err).Str("instance_id", inst.ID).Msg("freeze reconciler: failed to terminate instance")
+			}
+			continue
+		}
+
+		log.Warn().Str("user_id", freeze.UserID).Str("instance_id", inst.ID).Str("freeze_type", string(freeze.Type)).
+			Msg("freeze reconciler: suspending instance for frozen account")
+		if err := r.manager.StopInstance(ctx, inst.ID); err != nil {
+			log.Error().Err(err).Str("instance_id", inst.ID).Msg("freeze reconciler: failed to suspend instance")
+		}
+	}
+}
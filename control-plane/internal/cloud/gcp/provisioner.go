@@ -0,0 +1,301 @@
+// Package gcp implements the cloud.CloudProvisioner interface for Google
+// Compute Engine VMs.
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/compute/v1"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+func init() {
+	cloud.RegisterProviderFactory("gcp", func(cfg any) (cloud.CloudProvisioner, error) {
+		gcpCfg, ok := cfg.(cloud.GCPConfig)
+		if !ok {
+			return nil, fmt.Errorf("gcp: unexpected config type %T", cfg)
+		}
+		return NewProvisioner(context.Background(), gcpCfg)
+	})
+}
+
+// Provisioner implements cloud.CloudProvisioner for GCE instances.
+type Provisioner struct {
+	cfg    cloud.GCPConfig
+	client *compute.Service
+}
+
+// NewProvisioner creates a new GCP provisioner using Application Default
+// Credentials (GOOGLE_APPLICATION_CREDENTIALS or the metadata server).
+func NewProvisioner(ctx context.Context, cfg cloud.GCPConfig) (*Provisioner, error) {
+	client, err := compute.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCE client: %w", err)
+	}
+
+	return &Provisioner{cfg: cfg, client: client}, nil
+}
+
+func (p *Provisioner) ProviderName() string { return "gcp" }
+
+// CreateInstance creates a new GCE instance labeled with the cloud instance ID.
+func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRequest) (*cloud.CloudInstance, error) {
+	instanceID := uuid.New().String()
+	name := fmt.Sprintf("hanzo-bot-%s", instanceID[:8])
+
+	machineType := p.cfg.MachineType
+	if req.InstanceType != "" {
+		machineType = req.InstanceType
+	}
+
+	labels := map[string]string{
+		"hanzo-cloud-instance": instanceID,
+		"hanzo-team":           sanitizeLabel(req.TeamID),
+		"hanzo-bot-package":    sanitizeLabel(req.BotPackage),
+	}
+
+	inst := &compute.Instance{
+		Name:        name,
+		MachineType: fmt.Sprintf("zones/%s/machineTypes/%s", p.cfg.Zone, machineType),
+		Labels:      labels,
+		Disks: []*compute.AttachedDisk{
+			{
+				Boot:       true,
+				AutoDelete: true,
+				InitializeParams: &compute.AttachedDiskInitializeParams{
+					SourceImage: fmt.Sprintf("projects/%s/global/images/family/%s", p.cfg.ProjectID, p.cfg.ImageFamily),
+				},
+			},
+		},
+		NetworkInterfaces: []*compute.NetworkInterface{
+			{
+				Network:    p.cfg.Network,
+				Subnetwork: p.cfg.Subnetwork,
+				AccessConfigs: []*compute.AccessConfig{
+					{Type: "ONE_TO_ONE_NAT", Name: "External NAT"},
+				},
+			},
+		},
+	}
+
+	op, err := p.client.Instances.Insert(p.cfg.ProjectID, p.cfg.Zone, inst).Context(ctx).Do()
+	if err != nil {
+		return nil, &cloud.ProvisionError{
+			InstanceID: instanceID,
+			Platform:   cloud.PlatformLinux,
+			Provider:   "gcp",
+			Err:        err,
+		}
+	}
+
+	log.Info().Str("operation", op.Name).Str("instance_id", instanceID).Msg("GCE instance creation started")
+
+	now := time.Now().UTC()
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        cloud.InstanceStateProvisioning,
+		Provider:     "gcp",
+		InstanceID:   name,
+		InstanceType: machineType,
+		ImageID:      p.cfg.ImageFamily,
+		Region:       p.cfg.Zone,
+		BotPackage:   req.BotPackage,
+		BotVersion:   req.BotVersion,
+		TeamID:       req.TeamID,
+		Tags:         req.Tags,
+		RequestedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// GetInstance returns the current state of a GCE instance by its label.
+func (p *Provisioner) GetInstance(ctx context.Context, instanceID string) (*cloud.CloudInstance, error) {
+	gceInstance, err := p.findInstanceByLabel(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return instanceToCloudInstance(instanceID, gceInstance), nil
+}
+
+// ListInstances returns GCE instances matching filters.
+func (p *Provisioner) ListInstances(ctx context.Context, filters cloud.InstanceFilters) ([]*cloud.CloudInstance, error) {
+	call := p.client.Instances.List(p.cfg.ProjectID, p.cfg.Zone).Filter("labels.hanzo-cloud-instance:*")
+
+	var instances []*cloud.CloudInstance
+	err := call.Pages(ctx, func(page *compute.InstanceList) error {
+		for _, inst := range page.Items {
+			instanceID := inst.Labels["hanzo-cloud-instance"]
+			teamID := inst.Labels["hanzo-team"]
+			if filters.TeamID != nil && teamID != sanitizeLabel(*filters.TeamID) {
+				continue
+			}
+			ci := instanceToCloudInstance(instanceID, inst)
+			if filters.State != nil && ci.State != *filters.State {
+				continue
+			}
+			instances = append(instances, ci)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GCE instances: %w", err)
+	}
+	return instances, nil
+}
+
+func (p *Provisioner) StartInstance(ctx context.Context, instanceID string) error {
+	gceInstance, err := p.findInstanceByLabel(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	_, err = p.client.Instances.Start(p.cfg.ProjectID, p.cfg.Zone, gceInstance.Name).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to start GCE instance %s: %w", gceInstance.Name, err)
+	}
+	return nil
+}
+
+func (p *Provisioner) StopInstance(ctx context.Context, instanceID string) error {
+	gceInstance, err := p.findInstanceByLabel(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	_, err = p.client.Instances.Stop(p.cfg.ProjectID, p.cfg.Zone, gceInstance.Name).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to stop GCE instance %s: %w", gceInstance.Name, err)
+	}
+	return nil
+}
+
+// TerminateInstance deletes the GCE instance.
+func (p *Provisioner) TerminateInstance(ctx context.Context, instanceID string) error {
+	gceInstance, err := p.findInstanceByLabel(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.client.Instances.Delete(p.cfg.ProjectID, p.cfg.Zone, gceInstance.Name).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to delete GCE instance %s: %w", gceInstance.Name, err)
+	}
+
+	log.Info().Str("instance", gceInstance.Name).Str("instance_id", instanceID).Msg("GCE instance deleted")
+	return nil
+}
+
+// GetConnectionInfo returns SSH connection details for the instance.
+func (p *Provisioner) GetConnectionInfo(ctx context.Context, instanceID string) (*cloud.ConnectionInfo, error) {
+	gceInstance, err := p.findInstanceByLabel(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloud.ConnectionInfo{
+		Protocol: cloud.ConnectionProtocolSSH,
+		Host:     externalIP(gceInstance),
+		Port:     22,
+		Username: "hanzo",
+	}, nil
+}
+
+// ExecuteCommand is not supported directly over the Compute API; callers
+// should connect over the SSH info returned by GetConnectionInfo.
+func (p *Provisioner) ExecuteCommand(ctx context.Context, instanceID, command string) (*cloud.CommandResult, error) {
+	return nil, fmt.Errorf("gcp: ExecuteCommand requires an SSH connection, see GetConnectionInfo")
+}
+
+// GetLogs is not supported directly over the Compute API.
+func (p *Provisioner) GetLogs(ctx context.Context, instanceID string, lines int) (string, error) {
+	return "", fmt.Errorf("gcp: GetLogs requires an SSH connection, see GetConnectionInfo")
+}
+
+func (p *Provisioner) findInstanceByLabel(ctx context.Context, instanceID string) (*compute.Instance, error) {
+	filter := fmt.Sprintf("labels.hanzo-cloud-instance=%s", instanceID)
+
+	var found *compute.Instance
+	err := p.client.Instances.List(p.cfg.ProjectID, p.cfg.Zone).Filter(filter).Pages(ctx, func(page *compute.InstanceList) error {
+		if len(page.Items) > 0 {
+			found = page.Items[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up GCE instance: %w", err)
+	}
+	if found == nil {
+		return nil, cloud.ErrInstanceNotFound
+	}
+	return found, nil
+}
+
+func instanceToCloudInstance(instanceID string, inst *compute.Instance) *cloud.CloudInstance {
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        gceStatusToState(inst.Status),
+		Provider:     "gcp",
+		InstanceID:   inst.Name,
+		InstanceType: lastPathSegment(inst.MachineType),
+		Region:       lastPathSegment(inst.Zone),
+		PublicIP:     externalIP(inst),
+		TeamID:       inst.Labels["hanzo-team"],
+		BotPackage:   inst.Labels["hanzo-bot-package"],
+	}
+}
+
+func externalIP(inst *compute.Instance) string {
+	for _, iface := range inst.NetworkInterfaces {
+		for _, ac := range iface.AccessConfigs {
+			if ac.NatIP != "" {
+				return ac.NatIP
+			}
+		}
+	}
+	return ""
+}
+
+func lastPathSegment(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			return path[i+1:]
+		}
+	}
+	return path
+}
+
+func sanitizeLabel(v string) string {
+	// GCE labels must be lowercase; callers already constrain team/bot
+	// package identifiers to safe characters.
+	out := make([]byte, 0, len(v))
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		out = append(out, c)
+	}
+	return string(out)
+}
+
+func gceStatusToState(status string) cloud.InstanceState {
+	switch status {
+	case "PROVISIONING", "STAGING":
+		return cloud.InstanceStateProvisioning
+	case "RUNNING":
+		return cloud.InstanceStateRunning
+	case "STOPPING", "STOPPED", "SUSPENDED", "SUSPENDING":
+		return cloud.InstanceStateStopped
+	case "TERMINATED":
+		return cloud.InstanceStateTerminated
+	default:
+		return cloud.InstanceStateFailed
+	}
+}
@@ -0,0 +1,298 @@
+// Package hetzner implements the cloud.CloudProvisioner interface for
+// Hetzner Cloud servers.
+package hetzner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+const apiBaseURL = "https://api.hetzner.cloud/v1"
+
+func init() {
+	cloud.RegisterProviderFactory("hetzner", func(cfg any) (cloud.CloudProvisioner, error) {
+		hzCfg, ok := cfg.(cloud.HetznerConfig)
+		if !ok {
+			return nil, fmt.Errorf("hetzner: unexpected config type %T", cfg)
+		}
+		return NewProvisioner(hzCfg), nil
+	})
+}
+
+// Provisioner implements cloud.CloudProvisioner for Hetzner Cloud servers.
+type Provisioner struct {
+	cfg    cloud.HetznerConfig
+	client *http.Client
+}
+
+// NewProvisioner creates a new Hetzner Cloud provisioner.
+func NewProvisioner(cfg cloud.HetznerConfig) *Provisioner {
+	return &Provisioner{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *Provisioner) ProviderName() string { return "hetzner" }
+
+// CreateInstance creates a new Hetzner Cloud server labeled with the cloud
+// instance ID.
+func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRequest) (*cloud.CloudInstance, error) {
+	instanceID := uuid.New().String()
+
+	body := map[string]interface{}{
+		"name":        fmt.Sprintf("hanzo-bot-%s", instanceID[:8]),
+		"server_type": p.cfg.ServerType,
+		"image":       p.cfg.Image,
+		"location":    p.cfg.Location,
+		"labels": map[string]string{
+			"hanzo-agent-bot":  "true",
+			"hanzo-instance":   instanceID,
+			"hanzo-team":       req.TeamID,
+			"hanzo-bot-package": req.BotPackage,
+		},
+	}
+
+	var result struct {
+		Server serverResource `json:"server"`
+	}
+	if err := p.doRequest(ctx, http.MethodPost, "/servers", body, &result); err != nil {
+		return nil, &cloud.ProvisionError{
+			InstanceID: instanceID,
+			Platform:   cloud.PlatformLinux,
+			Provider:   "hetzner",
+			Err:        err,
+		}
+	}
+
+	log.Info().Int("server_id", result.Server.ID).Str("instance_id", instanceID).Msg("Hetzner Cloud server created")
+
+	now := time.Now().UTC()
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        serverStatusToState(result.Server.Status),
+		Provider:     "hetzner",
+		InstanceID:   strconv.Itoa(result.Server.ID),
+		InstanceType: p.cfg.ServerType,
+		ImageID:      p.cfg.Image,
+		Region:       p.cfg.Location,
+		BotPackage:   req.BotPackage,
+		BotVersion:   req.BotVersion,
+		TeamID:       req.TeamID,
+		Tags:         req.Tags,
+		RequestedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// GetInstance returns the current state of a server by its instance label.
+func (p *Provisioner) GetInstance(ctx context.Context, instanceID string) (*cloud.CloudInstance, error) {
+	server, err := p.findServerByLabel(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return serverToInstance(instanceID, server), nil
+}
+
+// ListInstances returns Hetzner servers matching filters.
+func (p *Provisioner) ListInstances(ctx context.Context, filters cloud.InstanceFilters) ([]*cloud.CloudInstance, error) {
+	var result struct {
+		Servers []serverResource `json:"servers"`
+	}
+	if err := p.doRequest(ctx, http.MethodGet, "/servers?label_selector=hanzo-agent-bot%3Dtrue", nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	var instances []*cloud.CloudInstance
+	for _, s := range result.Servers {
+		instanceID := s.Labels["hanzo-instance"]
+		if filters.TeamID != nil && s.Labels["hanzo-team"] != *filters.TeamID {
+			continue
+		}
+		inst := serverToInstance(instanceID, &s)
+		if filters.State != nil && inst.State != *filters.State {
+			continue
+		}
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
+
+func (p *Provisioner) StartInstance(ctx context.Context, instanceID string) error {
+	return p.serverAction(ctx, instanceID, "poweron")
+}
+
+func (p *Provisioner) StopInstance(ctx context.Context, instanceID string) error {
+	return p.serverAction(ctx, instanceID, "poweroff")
+}
+
+// TerminateInstance deletes the server.
+func (p *Provisioner) TerminateInstance(ctx context.Context, instanceID string) error {
+	server, err := p.findServerByLabel(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	if err := p.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/servers/%d", server.ID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete server %d: %w", server.ID, err)
+	}
+
+	log.Info().Int("server_id", server.ID).Str("instance_id", instanceID).Msg("Hetzner Cloud server deleted")
+	return nil
+}
+
+// GetConnectionInfo returns SSH connection details for the server.
+func (p *Provisioner) GetConnectionInfo(ctx context.Context, instanceID string) (*cloud.ConnectionInfo, error) {
+	server, err := p.findServerByLabel(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloud.ConnectionInfo{
+		Protocol: cloud.ConnectionProtocolSSH,
+		Host:     server.PublicNet.IPv4.IP,
+		Port:     22,
+		Username: "root",
+	}, nil
+}
+
+// ExecuteCommand is not supported directly over the Hetzner Cloud API;
+// callers should connect over the SSH info returned by GetConnectionInfo.
+func (p *Provisioner) ExecuteCommand(ctx context.Context, instanceID, command string) (*cloud.CommandResult, error) {
+	return nil, fmt.Errorf("hetzner: ExecuteCommand requires an SSH connection, see GetConnectionInfo")
+}
+
+// GetLogs is not supported directly over the Hetzner Cloud API.
+func (p *Provisioner) GetLogs(ctx context.Context, instanceID string, lines int) (string, error) {
+	return "", fmt.Errorf("hetzner: GetLogs requires an SSH connection, see GetConnectionInfo")
+}
+
+func (p *Provisioner) serverAction(ctx context.Context, instanceID, action string) error {
+	server, err := p.findServerByLabel(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/servers/%d/actions/%s", server.ID, action)
+	if err := p.doRequest(ctx, http.MethodPost, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to %s server %d: %w", action, server.ID, err)
+	}
+	return nil
+}
+
+func (p *Provisioner) findServerByLabel(ctx context.Context, instanceID string) (*serverResource, error) {
+	var result struct {
+		Servers []serverResource `json:"servers"`
+	}
+	selector := url.QueryEscape(fmt.Sprintf("hanzo-instance==%s", instanceID))
+	path := "/servers?label_selector=" + selector
+	if err := p.doRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to look up server: %w", err)
+	}
+	if len(result.Servers) == 0 {
+		return nil, cloud.ErrInstanceNotFound
+	}
+	return &result.Servers[0], nil
+}
+
+func (p *Provisioner) doRequest(ctx context.Context, method, path string, payload interface{}, out interface{}) error {
+	var bodyReader io.Reader
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		bodyReader = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiBaseURL+path, bodyReader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.cfg.Token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("hetzner API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("hetzner API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// serverResource is the subset of the Hetzner Cloud server API response we care about.
+type serverResource struct {
+	ID        int               `json:"id"`
+	Status    string            `json:"status"`
+	ServerType struct {
+		Name string `json:"name"`
+	} `json:"server_type"`
+	Image struct {
+		Name string `json:"name"`
+	} `json:"image"`
+	Datacenter struct {
+		Location struct {
+			Name string `json:"name"`
+		} `json:"location"`
+	} `json:"datacenter"`
+	Labels    map[string]string `json:"labels"`
+	PublicNet struct {
+		IPv4 struct {
+			IP string `json:"ip"`
+		} `json:"ipv4"`
+	} `json:"public_net"`
+}
+
+func serverToInstance(instanceID string, s *serverResource) *cloud.CloudInstance {
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        serverStatusToState(s.Status),
+		Provider:     "hetzner",
+		InstanceID:   strconv.Itoa(s.ID),
+		InstanceType: s.ServerType.Name,
+		ImageID:      s.Image.Name,
+		Region:       s.Datacenter.Location.Name,
+		PublicIP:     s.PublicNet.IPv4.IP,
+		TeamID:       s.Labels["hanzo-team"],
+		BotPackage:   s.Labels["hanzo-bot-package"],
+	}
+}
+
+func serverStatusToState(status string) cloud.InstanceState {
+	switch status {
+	case "initializing", "starting":
+		return cloud.InstanceStateProvisioning
+	case "running":
+		return cloud.InstanceStateRunning
+	case "stopping", "off":
+		return cloud.InstanceStateStopped
+	case "deleting":
+		return cloud.InstanceStateTerminated
+	default:
+		return cloud.InstanceStateFailed
+	}
+}
@@ -0,0 +1,15 @@
+package cloud
+
+import "context"
+
+// ImageResolver resolves a symbolic "bot_package@version" reference to a
+// concrete provider image ID (e.g. an AMI ID), so CloudConfig and
+// ProvisionRequest callers never need to hand-manage image IDs directly.
+// Backed by the image builder's ImageStore; defaults to unset, in which case
+// CreateInstance falls back to the platform's configured default image.
+type ImageResolver interface {
+	// ResolveImage looks up the most recent image built for botPackage at
+	// botVersion on the given platform. ok is false when no matching image
+	// has been built.
+	ResolveImage(ctx context.Context, platform Platform, botPackage, botVersion string) (imageID string, ok bool)
+}
@@ -0,0 +1,202 @@
+package imagebuilder
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+const (
+	defaultBuildTimeout = 20 * time.Minute
+	buildPollInterval   = 10 * time.Second
+	// buildTeamID attributes build instances to a fixed pseudo-team so they
+	// show up under their own bucket in per-team instance limits/billing
+	// rather than an empty TeamID.
+	buildTeamID = "hanzo-image-builder"
+)
+
+// Builder runs ImageSpec builds against a CloudManager: it provisions a
+// temporary instance from BaseImage, runs the spec's provisioning steps
+// against it over the platform's existing connection protocol, snapshots
+// the result, and persists the record to an ImageStore.
+type Builder struct {
+	manager *cloud.CloudManager
+	store   ImageStore
+}
+
+// NewBuilder creates a Builder backed by the given CloudManager and store.
+func NewBuilder(manager *cloud.CloudManager, store ImageStore) *Builder {
+	return &Builder{manager: manager, store: store}
+}
+
+// BuildImage provisions a temporary instance from spec.BaseImage, runs its
+// provisioning steps, snapshots the result into a new provider image, tags
+// it with the bot package, version, and git SHA, and persists and returns
+// the resulting Image record. The temporary build instance is always
+// terminated before returning, whether or not the build succeeded.
+func (b *Builder) BuildImage(ctx context.Context, spec ImageSpec) (*Image, error) {
+	inst, err := b.manager.CreateInstance(ctx, &cloud.ProvisionRequest{
+		Platform:      spec.Platform,
+		BotPackage:    fmt.Sprintf("image-builder/%s", spec.BotPackage),
+		BotVersion:    spec.BotVersion,
+		TeamID:        buildTeamID,
+		Tags:          spec.Tags,
+		ImageOverride: spec.BaseImage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("imagebuilder: failed to launch build instance: %w", err)
+	}
+	defer func() {
+		if termErr := b.manager.TerminateInstance(context.Background(), inst.ID); termErr != nil {
+			log.Warn().Err(termErr).Str("instance_id", inst.ID).Msg("imagebuilder: failed to terminate build instance")
+		}
+	}()
+
+	if err := b.waitUntilRunning(ctx, inst.ID); err != nil {
+		return nil, err
+	}
+
+	for i, step := range spec.Provisioners {
+		if err := b.runStep(ctx, inst.ID, spec.Platform, step); err != nil {
+			return nil, fmt.Errorf("imagebuilder: step %d (%s) failed: %w", i, step.Kind, err)
+		}
+	}
+
+	tags := map[string]string{
+		"bot_package": spec.BotPackage,
+		"bot_version": spec.BotVersion,
+	}
+	if spec.GitSHA != "" {
+		tags["git_sha"] = spec.GitSHA
+	}
+	for k, v := range spec.Tags {
+		tags[k] = v
+	}
+
+	imageID, err := b.manager.SnapshotInstance(ctx, inst.ID, tags)
+	if err != nil {
+		if errors.Is(err, cloud.ErrInvalidPlatform) {
+			return nil, fmt.Errorf("%w: %v", ErrSnapshotUnsupported, err)
+		}
+		return nil, fmt.Errorf("imagebuilder: failed to snapshot build instance: %w", err)
+	}
+
+	img := &Image{
+		ID:         uuid.New().String(),
+		Provider:   inst.Provider,
+		ImageID:    imageID,
+		Platform:   spec.Platform,
+		BotPackage: spec.BotPackage,
+		BotVersion: spec.BotVersion,
+		GitSHA:     spec.GitSHA,
+		Tags:       tags,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := b.store.CreateImage(ctx, img); err != nil {
+		return nil, fmt.Errorf("imagebuilder: failed to persist image record: %w", err)
+	}
+
+	log.Info().
+		Str("image_id", imageID).
+		Str("bot_package", spec.BotPackage).
+		Str("bot_version", spec.BotVersion).
+		Msg("imagebuilder: build complete")
+
+	return img, nil
+}
+
+// waitUntilRunning polls the build instance until it reaches the running
+// state or defaultBuildTimeout elapses.
+func (b *Builder) waitUntilRunning(ctx context.Context, instanceID string) error {
+	deadline := time.Now().Add(defaultBuildTimeout)
+	for time.Now().Before(deadline) {
+		inst, err := b.manager.GetInstance(ctx, instanceID)
+		if err == nil && inst.State == cloud.InstanceStateRunning {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(buildPollInterval):
+		}
+	}
+	return ErrBuildTimeout
+}
+
+// runStep executes a single provisioning step against the build instance.
+func (b *Builder) runStep(ctx context.Context, instanceID string, platform cloud.Platform, step Step) error {
+	switch step.Kind {
+	case StepShell:
+		return b.runCommand(ctx, instanceID, strings.Join(step.Inline, " && "))
+	case StepPowerShell:
+		return b.runCommand(ctx, instanceID, strings.Join(step.Inline, "; "))
+	case StepFile:
+		return b.uploadFile(ctx, instanceID, platform, step.Source, step.Destination)
+	case StepReboot:
+		return b.rebootInstance(ctx, instanceID)
+	default:
+		// StepAnsible isn't implemented: running a playbook needs a
+		// connection plugin (SSH/WinRM) and inventory CloudProvisioner
+		// doesn't expose - ExecuteCommand is a one-shot remote shell, not a
+		// transport ansible-playbook can run over. Fail the build loudly
+		// rather than silently skip the step.
+		return fmt.Errorf("%w: %s", ErrUnsupportedStep, step.Kind)
+	}
+}
+
+// uploadFile writes a local file's contents to destination on the build
+// instance, base64-encoded inline over ExecuteCommand since CloudProvisioner
+// has no dedicated file transfer primitive. Only practical for the small
+// config/script files golden-image builds stage - SSM caps command size
+// well under what a base64-encoded binary would need.
+func (b *Builder) uploadFile(ctx context.Context, instanceID string, platform cloud.Platform, source, destination string) error {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", source, err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var command string
+	if platform == cloud.PlatformWindows {
+		command = fmt.Sprintf(
+			"[IO.File]::WriteAllBytes('%s', [Convert]::FromBase64String('%s'))",
+			destination, encoded,
+		)
+	} else {
+		command = fmt.Sprintf("echo %s | base64 -d > %s", encoded, destination)
+	}
+	return b.runCommand(ctx, instanceID, command)
+}
+
+func (b *Builder) runCommand(ctx context.Context, instanceID, command string) error {
+	result, err := b.manager.ExecuteCommand(ctx, instanceID, command)
+	if err != nil {
+		return err
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("command exited %d: %s", result.ExitCode, result.Stderr)
+	}
+	return nil
+}
+
+// rebootInstance stops then starts the instance, since CloudProvisioner has
+// no native reboot primitive, and waits for it to come back up.
+func (b *Builder) rebootInstance(ctx context.Context, instanceID string) error {
+	if err := b.manager.StopInstance(ctx, instanceID); err != nil {
+		return err
+	}
+	if err := b.manager.StartInstance(ctx, instanceID); err != nil {
+		return err
+	}
+	return b.waitUntilRunning(ctx, instanceID)
+}
@@ -0,0 +1,17 @@
+package imagebuilder
+
+import "errors"
+
+var (
+	// ErrUnsupportedStep is returned when an ImageSpec contains a StepKind
+	// the build instance's platform cannot execute.
+	ErrUnsupportedStep = errors.New("imagebuilder: unsupported provisioning step for platform")
+
+	// ErrSnapshotUnsupported is returned when the platform's registered
+	// provisioner does not implement cloud.Snapshotter.
+	ErrSnapshotUnsupported = errors.New("imagebuilder: provisioner does not support image snapshots")
+
+	// ErrBuildTimeout is returned when the build instance never reaches the
+	// running state within defaultBuildTimeout.
+	ErrBuildTimeout = errors.New("imagebuilder: timed out waiting for build instance to be ready")
+)
@@ -0,0 +1,28 @@
+package imagebuilder
+
+import (
+	"context"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// Resolver adapts an ImageStore to cloud.ImageResolver, letting
+// ProvisionRequests reference images by the symbolic "bot_package@version"
+// key they were built under instead of a hand-managed AMI ID.
+type Resolver struct {
+	store ImageStore
+}
+
+// NewResolver wraps store as a cloud.ImageResolver.
+func NewResolver(store ImageStore) *Resolver {
+	return &Resolver{store: store}
+}
+
+// ResolveImage implements cloud.ImageResolver.
+func (r *Resolver) ResolveImage(ctx context.Context, platform cloud.Platform, botPackage, botVersion string) (string, bool) {
+	img, err := r.store.ResolveSymbolicRef(ctx, platform, botPackage, botVersion)
+	if err != nil || img == nil {
+		return "", false
+	}
+	return img.ImageID, true
+}
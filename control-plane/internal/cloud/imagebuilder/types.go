@@ -0,0 +1,89 @@
+// Package imagebuilder builds reproducible "golden" bot images (AMIs today;
+// GCE images and Azure managed images once those provisioner backends
+// support snapshotting) from a declarative spec, so teams can ship signed
+// images instead of hand-managing AMI IDs in CloudConfig.
+package imagebuilder
+
+import (
+	"context"
+	"time"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// StepKind identifies the kind of provisioning step in an ImageSpec.
+//
+// Builder implements StepShell, StepPowerShell, StepFile, and StepReboot.
+// StepAnsible is declared for forward compatibility but not runnable yet:
+// CloudProvisioner's ExecuteCommand is a one-shot remote shell with no
+// connection plugin or inventory for ansible-playbook to run over, so
+// Builder always rejects it with ErrUnsupportedStep.
+type StepKind string
+
+const (
+	StepShell      StepKind = "shell"
+	StepPowerShell StepKind = "powershell"
+	StepFile       StepKind = "file"
+	StepAnsible    StepKind = "ansible"
+	StepReboot     StepKind = "reboot"
+)
+
+// Step is a single provisioning action run against the build instance while
+// constructing an image.
+type Step struct {
+	Kind StepKind `json:"kind"`
+
+	// Inline holds the commands run for StepShell/StepPowerShell, executed
+	// in order as one ExecuteCommand call: joined by " && " for StepShell
+	// and by "; " for StepPowerShell.
+	Inline []string `json:"inline,omitempty"`
+
+	// Source/Destination describe a StepFile upload: a local path staged by
+	// the caller, base64-encoded inline over ExecuteCommand, and the remote
+	// path it should land at. Only practical for small files - there is no
+	// chunking, so this is bounded by the provider's command size limit.
+	Source      string `json:"source,omitempty"`
+	Destination string `json:"destination,omitempty"`
+
+	// Playbook is the Ansible playbook path for StepAnsible. Unused today;
+	// see StepKind's doc comment.
+	Playbook string `json:"playbook,omitempty"`
+}
+
+// ImageSpec declaratively describes a golden image build.
+type ImageSpec struct {
+	Platform     cloud.Platform    `json:"platform"`
+	BaseImage    string            `json:"base_image"` // provider image ID the build instance boots from
+	Provisioners []Step            `json:"provisioners"`
+	Tags         map[string]string `json:"tags,omitempty"`
+
+	// BotPackage/BotVersion identify the image for symbolic resolution via
+	// ImageStore.ResolveSymbolicRef (as "bot_package@version").
+	BotPackage string `json:"bot_package"`
+	BotVersion string `json:"bot_version"`
+
+	// GitSHA records the commit the build was run from, for provenance.
+	GitSHA string `json:"git_sha,omitempty"`
+}
+
+// Image is the result of a successful build.
+type Image struct {
+	ID         string            `json:"id"`
+	Provider   string            `json:"provider"`
+	ImageID    string            `json:"image_id"` // concrete AMI / GCE image / Azure managed image ID
+	Platform   cloud.Platform    `json:"platform"`
+	BotPackage string            `json:"bot_package"`
+	BotVersion string            `json:"bot_version"`
+	GitSHA     string            `json:"git_sha,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// ImageStore persists built Image records and resolves symbolic references.
+type ImageStore interface {
+	CreateImage(ctx context.Context, img *Image) error
+	GetImage(ctx context.Context, id string) (*Image, error)
+	// ResolveSymbolicRef returns the most recently built Image matching
+	// platform/botPackage/botVersion, or nil if none has been built yet.
+	ResolveSymbolicRef(ctx context.Context, platform cloud.Platform, botPackage, botVersion string) (*Image, error)
+}
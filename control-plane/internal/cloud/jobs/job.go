@@ -0,0 +1,27 @@
+// Package jobs provides a small pluggable scheduler for the cloud control
+// plane's background reconcilers (state sync, cost accrual, idle host
+// release, quota recheck, pruning, ...), so each one runs on its own
+// interval instead of all sharing CloudInstanceMonitor's single tick.
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// Job is one independently scheduled reconciler. Name must be unique within
+// a JobScheduler; it's used as the metrics label and in reload diffing.
+type Job struct {
+	Name string
+	// Interval is how often Run is invoked.
+	Interval time.Duration
+	// Timeout bounds a single Run call. Zero falls back to Interval.
+	Timeout time.Duration
+	// Enabled controls whether the job is scheduled at all; a registered but
+	// disabled Job is simply never started.
+	Enabled bool
+	// Run performs one pass of the reconciler. Its returned error is
+	// counted in the job's error metric, marks the last run as failed, and
+	// is logged, but never stops future runs.
+	Run func(ctx context.Context) error
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/hanzoai/agents/control-plane/internal/metrics"
+)
+
+// Metrics holds the Prometheus collectors reporting each Job's last-run,
+// next-run, duration, and error count, labeled by job name.
+type Metrics struct {
+	lastRun   *prometheus.GaugeVec
+	nextRun   *prometheus.GaugeVec
+	duration  *prometheus.HistogramVec
+	errors    *prometheus.CounterVec
+	lastError *prometheus.GaugeVec
+}
+
+// NewMetrics builds the job metrics and, if reg is non-nil, registers them.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	r := metrics.New(reg)
+	return &Metrics{
+		lastRun: r.GaugeVec(prometheus.GaugeOpts{
+			Name: "hanzo_cloud_job_last_run_timestamp_seconds",
+			Help: "Unix timestamp of the last time a job ran, by job name.",
+		}, []string{"job"}),
+		nextRun: r.GaugeVec(prometheus.GaugeOpts{
+			Name: "hanzo_cloud_job_next_run_timestamp_seconds",
+			Help: "Unix timestamp of the next scheduled run of a job, by job name.",
+		}, []string{"job"}),
+		duration: r.HistogramVec(prometheus.HistogramOpts{
+			Name: "hanzo_cloud_job_duration_seconds",
+			Help: "Duration of a job's Run call, by job name.",
+		}, []string{"job"}),
+		errors: r.CounterVec(prometheus.CounterOpts{
+			Name: "hanzo_cloud_job_errors_total",
+			Help: "Errors returned by a job's Run call, by job name.",
+		}, []string{"job"}),
+		lastError: r.GaugeVec(prometheus.GaugeOpts{
+			Name: "hanzo_cloud_job_last_run_success",
+			Help: "1 if a job's most recent run succeeded, 0 otherwise, by job name.",
+		}, []string{"job"}),
+	}
+}
+
+func (m *Metrics) setNextRun(job string, at time.Time) {
+	m.nextRun.WithLabelValues(job).Set(float64(at.Unix()))
+}
+
+func (m *Metrics) record(job string, dur time.Duration, err error) {
+	m.lastRun.WithLabelValues(job).Set(float64(time.Now().Unix()))
+	m.duration.WithLabelValues(job).Observe(dur.Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(job).Inc()
+		m.lastError.WithLabelValues(job).Set(0)
+	} else {
+		m.lastError.WithLabelValues(job).Set(1)
+	}
+}
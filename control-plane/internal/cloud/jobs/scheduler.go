@@ -0,0 +1,149 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// JobScheduler independently starts, stops, and ticks a set of Jobs, each on
+// its own interval. Unlike a single shared ticker, one job running long
+// never delays another's schedule.
+type JobScheduler struct {
+	mu      sync.Mutex
+	running map[string]*runner
+	metrics *Metrics
+}
+
+// NewJobScheduler creates a JobScheduler. If reg is non-nil, per-job metrics
+// are registered against it.
+func NewJobScheduler(reg prometheus.Registerer) *JobScheduler {
+	return &JobScheduler{
+		running: make(map[string]*runner),
+		metrics: NewMetrics(reg),
+	}
+}
+
+// Start begins running the given jobs. It's equivalent to calling Reload
+// against an empty scheduler.
+func (s *JobScheduler) Start(jobList []Job) {
+	s.Reload(jobList)
+}
+
+// Reload reconciles the running set of jobs against jobList: jobs no longer
+// present or now disabled are stopped, jobs whose Interval or Timeout
+// changed are restarted, and unchanged jobs are left running untouched. This
+// lets config hot-reload pick up new intervals without a process restart.
+func (s *JobScheduler) Reload(jobList []Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[string]Job, len(jobList))
+	for _, job := range jobList {
+		wanted[job.Name] = job
+	}
+
+	for name, r := range s.running {
+		job, ok := wanted[name]
+		if !ok || !job.Enabled {
+			r.stop()
+			delete(s.running, name)
+			continue
+		}
+		if job.Interval != r.job.Interval || job.Timeout != r.job.Timeout {
+			r.stop()
+			delete(s.running, name)
+		}
+	}
+
+	for name, job := range wanted {
+		if !job.Enabled {
+			continue
+		}
+		if _, ok := s.running[name]; ok {
+			continue
+		}
+		r := newRunner(job, s.metrics)
+		s.running[name] = r
+		r.start()
+	}
+}
+
+// Stop stops every running job and waits for each to finish its current run.
+func (s *JobScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, r := range s.running {
+		r.stop()
+		delete(s.running, name)
+	}
+}
+
+// runner owns the goroutine ticking a single Job.
+type runner struct {
+	job      Job
+	metrics  *Metrics
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newRunner(job Job, metrics *Metrics) *runner {
+	return &runner{
+		job:     job,
+		metrics: metrics,
+		stopCh:  make(chan struct{}),
+	}
+}
+
+func (r *runner) start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+func (r *runner) stop() {
+	r.stopOnce.Do(func() { close(r.stopCh) })
+	r.wg.Wait()
+}
+
+func (r *runner) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.job.Interval)
+	defer ticker.Stop()
+
+	r.metrics.setNextRun(r.job.Name, time.Now().Add(r.job.Interval))
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			r.runOnce()
+			r.metrics.setNextRun(r.job.Name, time.Now().Add(r.job.Interval))
+		}
+	}
+}
+
+func (r *runner) runOnce() {
+	timeout := r.job.Timeout
+	if timeout == 0 {
+		timeout = r.job.Interval
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := r.job.Run(ctx)
+	dur := time.Since(start)
+
+	r.metrics.record(r.job.Name, dur, err)
+	if err != nil {
+		log.Error().Err(err).Str("job", r.job.Name).Dur("duration", dur).Msg("background job failed")
+	}
+}
@@ -0,0 +1,168 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rs/zerolog/log"
+)
+
+// interruptionTaintKeys are the NoSchedule/NoExecute taint keys used by common
+// spot-interruption signalers (AWS's node-termination-handler, Karpenter's
+// consolidation/disruption controller, GKE's preemptible-node handler) to
+// mark a node for imminent removal. A taint match is treated the same as a
+// cloud provider's interruption notice.
+var interruptionTaintKeys = map[string]struct{}{
+	"aws-node-termination-handler/spot-itn":              {},
+	"aws-node-termination-handler/scheduled-maintenance": {},
+	"karpenter.sh/disruption":                            {},
+	"cloud.google.com/impending-node-termination":        {},
+}
+
+// InterruptionWatcher polls Hanzo-managed pods' nodes for an
+// interruption-style NoSchedule or NoExecute taint and reports each affected instance
+// once. There is no portable, vendor-neutral admission webhook for pod
+// eviction notices, so this polls node taints rather than watching
+// Eviction API calls directly.
+type InterruptionWatcher struct {
+	provisioner *Provisioner
+	interval    time.Duration
+	onWarning   func(ctx context.Context, instanceID string)
+
+	mu        sync.Mutex
+	announced map[string]struct{}
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewInterruptionWatcher creates a watcher that invokes onWarning once per
+// instance the first time its node is observed tainted for interruption.
+func NewInterruptionWatcher(p *Provisioner, interval time.Duration, onWarning func(ctx context.Context, instanceID string)) *InterruptionWatcher {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &InterruptionWatcher{
+		provisioner: p,
+		interval:    interval,
+		onWarning:   onWarning,
+		announced:   make(map[string]struct{}),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start runs the poll loop until Stop is called.
+func (w *InterruptionWatcher) Start() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", w.interval).Msg("k8s interruption watcher started")
+
+	for {
+		select {
+		case <-w.stopCh:
+			log.Info().Msg("k8s interruption watcher stopped")
+			return
+		case <-ticker.C:
+			w.tick()
+		}
+	}
+}
+
+// Stop terminates the poll loop.
+func (w *InterruptionWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+	})
+}
+
+func (w *InterruptionWatcher) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	p := w.provisioner
+	pods, err := p.client.CoreV1().Pods(p.config.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "hanzo.ai/cloud-instance",
+	})
+	if err != nil {
+		log.Debug().Err(err).Msg("interruption watcher: failed to list pods")
+		return
+	}
+
+	nodeInterrupted := make(map[string]bool)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		instanceID := pod.Labels["hanzo.ai/cloud-instance"]
+		if instanceID == "" || pod.Spec.NodeName == "" {
+			continue
+		}
+
+		interrupted, ok := nodeInterrupted[pod.Spec.NodeName]
+		if !ok {
+			interrupted = w.nodeIsInterrupted(ctx, pod.Spec.NodeName)
+			nodeInterrupted[pod.Spec.NodeName] = interrupted
+		}
+		if !interrupted {
+			continue
+		}
+
+		w.mu.Lock()
+		_, already := w.announced[instanceID]
+		w.announced[instanceID] = struct{}{}
+		w.mu.Unlock()
+		if already {
+			continue
+		}
+
+		log.Warn().Str("instance_id", instanceID).Str("node", pod.Spec.NodeName).Msg("node interruption taint detected")
+		if w.onWarning != nil {
+			w.onWarning(context.Background(), instanceID)
+		}
+	}
+}
+
+func (w *InterruptionWatcher) nodeIsInterrupted(ctx context.Context, nodeName string) bool {
+	node, err := w.provisioner.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		if _, ok := interruptionTaintKeys[taint.Key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Drain implements cloud.Drainer by cordoning the pod's node so the
+// scheduler stops placing new work there while the eviction plays out;
+// the pod itself is left for TerminateInstance/the eviction to remove.
+func (p *Provisioner) Drain(ctx context.Context, instanceID string) error {
+	pod, err := p.findPodByInstanceID(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	if pod.Spec.NodeName == "" {
+		return nil
+	}
+
+	node, err := p.client.CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable {
+		return nil
+	}
+
+	node.Spec.Unschedulable = true
+	_, err = p.client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	return err
+}
@@ -1,10 +1,12 @@
 package k8s
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,6 +32,16 @@ type Provisioner struct {
 	restCfg   *restclient.Config
 	serverURL string // control plane URL for agent registration
 	apiKey    string // API key for agent auth
+
+	interruptionWatcher *InterruptionWatcher
+}
+
+// SetInterruptionWatcher installs an InterruptionWatcher used to detect
+// node-level interruption taints for instances this provisioner launches.
+// Call this during server wiring; instances run fine without it, they
+// just won't get a proactive interruption warning surfaced.
+func (p *Provisioner) SetInterruptionWatcher(w *InterruptionWatcher) {
+	p.interruptionWatcher = w
 }
 
 // NewProvisioner creates a new K8s provisioner.
@@ -59,10 +71,20 @@ func NewProvisioner(cfg cloud.K8sConfig, serverURL, apiKey string) (*Provisioner
 
 func (p *Provisioner) ProviderName() string { return "k8s" }
 
-// CreateInstance creates a pod in the configured namespace.
+// CreateInstance creates a bot workload in the configured namespace, as a
+// Pod, Job, StatefulSet, or Deployment depending on the resolved
+// WorkloadKind (see buildPodSpec and the per-kind create* helpers).
 func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRequest) (*cloud.CloudInstance, error) {
 	instanceID := uuid.New().String()
-	podName := fmt.Sprintf("bot-%s", instanceID[:8])
+	workloadName := fmt.Sprintf("bot-%s", instanceID[:8])
+
+	kind := cloud.WorkloadKind(req.WorkloadKind)
+	if kind == "" {
+		kind = p.config.WorkloadKind
+	}
+	if kind == "" {
+		kind = cloud.WorkloadKindPod
+	}
 
 	image := p.config.DefaultImage
 	if req.InstanceType != "" {
@@ -70,65 +92,40 @@ func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRe
 	}
 
 	labels := map[string]string{
-		"app":                       "hanzo-agent-bot",
-		"hanzo.ai/cloud-instance":   instanceID,
-		"hanzo.ai/team":             req.TeamID,
-		"hanzo.ai/bot-package":      req.BotPackage,
+		"app":                     "hanzo-agent-bot",
+		"hanzo.ai/cloud-instance": instanceID,
+		"hanzo.ai/team":           req.TeamID,
+		"hanzo.ai/bot-package":    req.BotPackage,
+		"hanzo.ai/workload-kind":  string(kind),
+		"hanzo.ai/workload-name":  workloadName,
 	}
 	for k, v := range req.Tags {
 		labels["hanzo.ai/tag-"+k] = v
 	}
 
-	env := []corev1.EnvVar{
-		{Name: "HANZO_AGENTS_SERVER_URL", Value: p.serverURL},
-		{Name: "HANZO_AGENTS_API_KEY", Value: p.apiKey},
-		{Name: "HANZO_AGENTS_INSTANCE_ID", Value: instanceID},
-		{Name: "HANZO_AGENTS_BOT_PACKAGE", Value: req.BotPackage},
-	}
-	if req.BotVersion != "" {
-		env = append(env, corev1.EnvVar{Name: "HANZO_AGENTS_BOT_VERSION", Value: req.BotVersion})
-	}
+	podSpec := p.buildPodSpec(req, instanceID, image, kind)
 
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podName,
-			Namespace: p.config.Namespace,
-			Labels:    labels,
-		},
-		Spec: corev1.PodSpec{
-			ServiceAccountName: p.config.ServiceAccount,
-			RestartPolicy:      corev1.RestartPolicyNever,
-			Containers: []corev1.Container{
-				{
-					Name:  "agent",
-					Image: image,
-					Env:   env,
-					Resources: corev1.ResourceRequirements{
-						Requests: corev1.ResourceList{
-							corev1.ResourceCPU:    resource.MustParse("250m"),
-							corev1.ResourceMemory: resource.MustParse("256Mi"),
-						},
-						Limits: corev1.ResourceList{
-							corev1.ResourceCPU:    resource.MustParse("1"),
-							corev1.ResourceMemory: resource.MustParse("1Gi"),
-						},
-					},
-				},
-			},
-		},
+	var createErr error
+	switch kind {
+	case cloud.WorkloadKindJob:
+		createErr = p.createJob(ctx, workloadName, labels, podSpec)
+	case cloud.WorkloadKindStatefulSet:
+		createErr = p.createStatefulSet(ctx, workloadName, labels, podSpec)
+	case cloud.WorkloadKindDeployment:
+		createErr = p.createDeployment(ctx, workloadName, labels, podSpec)
+	default:
+		createErr = p.createPod(ctx, workloadName, labels, podSpec)
 	}
-
-	created, err := p.client.CoreV1().Pods(p.config.Namespace).Create(ctx, pod, metav1.CreateOptions{})
-	if err != nil {
+	if createErr != nil {
 		return nil, &cloud.ProvisionError{
 			InstanceID: instanceID,
 			Platform:   cloud.PlatformLinux,
 			Provider:   "k8s",
-			Err:        err,
+			Err:        createErr,
 		}
 	}
 
-	log.Info().Str("pod", created.Name).Str("instance_id", instanceID).Msg("K8s pod created")
+	log.Info().Str("workload", workloadName).Str("kind", string(kind)).Str("instance_id", instanceID).Msg("K8s workload created")
 
 	now := time.Now().UTC()
 	return &cloud.CloudInstance{
@@ -136,7 +133,7 @@ func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRe
 		Platform:     cloud.PlatformLinux,
 		State:        cloud.InstanceStateProvisioning,
 		Provider:     "k8s",
-		InstanceID:   created.Name,
+		InstanceID:   workloadName,
 		InstanceType: image,
 		BotPackage:   req.BotPackage,
 		BotVersion:   req.BotVersion,
@@ -147,6 +144,68 @@ func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRe
 	}, nil
 }
 
+// buildPodSpec assembles the PodSpec shared by every WorkloadKind. restart
+// policy differs by kind: Jobs must use OnFailure/Never, everything this
+// provisioner creates otherwise (bare Pod, StatefulSet, Deployment pods)
+// uses Never/Always per their controller's own requirements.
+func (p *Provisioner) buildPodSpec(req *cloud.ProvisionRequest, instanceID, image string, kind cloud.WorkloadKind) corev1.PodSpec {
+	env := []corev1.EnvVar{
+		{Name: "HANZO_AGENTS_SERVER_URL", Value: p.serverURL},
+		{Name: "HANZO_AGENTS_API_KEY", Value: p.apiKey},
+		{Name: "HANZO_AGENTS_INSTANCE_ID", Value: instanceID},
+		{Name: "HANZO_AGENTS_BOT_PACKAGE", Value: req.BotPackage},
+	}
+	if req.BotVersion != "" {
+		env = append(env, corev1.EnvVar{Name: "HANZO_AGENTS_BOT_VERSION", Value: req.BotVersion})
+	}
+
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("250m"),
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	if req.Requirements != nil {
+		resources = resourcesFromRequirements(*req.Requirements, resources)
+	}
+
+	agentContainer := corev1.Container{
+		Name:           "agent",
+		Image:          image,
+		Env:            env,
+		Resources:      resources,
+		ReadinessProbe: buildReadinessProbe(p.config.ReadinessProbe),
+	}
+
+	restartPolicy := corev1.RestartPolicyNever
+	switch kind {
+	case cloud.WorkloadKindJob:
+		restartPolicy = corev1.RestartPolicyOnFailure
+	case cloud.WorkloadKindStatefulSet, cloud.WorkloadKindDeployment:
+		restartPolicy = corev1.RestartPolicyAlways
+	}
+
+	if kind == cloud.WorkloadKindStatefulSet {
+		agentContainer.VolumeMounts = []corev1.VolumeMount{
+			{Name: "data", MountPath: "/var/lib/hanzo-agent"},
+		}
+	}
+
+	return corev1.PodSpec{
+		ServiceAccountName: p.config.ServiceAccount,
+		RestartPolicy:      restartPolicy,
+		NodeSelector:       nodeSelectorFromRequirements(p.config.NodeSelector, req.Requirements),
+		Tolerations:        buildTolerations(p.config.Tolerations),
+		Affinity:           buildAffinity(p.config.Affinity),
+		InitContainers:     buildInitContainers(p.config.InitContainers),
+		Containers:         []corev1.Container{agentContainer},
+	}
+}
+
 // GetInstance returns the instance state by looking up the K8s pod.
 func (p *Provisioner) GetInstance(ctx context.Context, instanceID string) (*cloud.CloudInstance, error) {
 	pod, err := p.findPodByInstanceID(ctx, instanceID)
@@ -154,18 +213,19 @@ func (p *Provisioner) GetInstance(ctx context.Context, instanceID string) (*clou
 		return nil, err
 	}
 
-	state := podPhaseToState(pod.Status.Phase)
-	podIP := pod.Status.PodIP
+	state, reason, message := waitForPodReady(pod)
 
 	return &cloud.CloudInstance{
-		ID:         instanceID,
-		Platform:   cloud.PlatformLinux,
-		State:      state,
-		Provider:   "k8s",
-		InstanceID: pod.Name,
-		PrivateIP:  podIP,
-		TeamID:     pod.Labels["hanzo.ai/team"],
-		BotPackage: pod.Labels["hanzo.ai/bot-package"],
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        state,
+		StateReason:  reason,
+		StateMessage: message,
+		Provider:     "k8s",
+		InstanceID:   pod.Name,
+		PrivateIP:    pod.Status.PodIP,
+		TeamID:       pod.Labels["hanzo.ai/team"],
+		BotPackage:   pod.Labels["hanzo.ai/bot-package"],
 	}, nil
 }
 
@@ -184,23 +244,26 @@ func (p *Provisioner) ListInstances(ctx context.Context, filters cloud.InstanceF
 	}
 
 	var instances []*cloud.CloudInstance
-	for _, pod := range pods.Items {
+	for i := range pods.Items {
+		pod := &pods.Items[i]
 		instanceID := pod.Labels["hanzo.ai/cloud-instance"]
-		state := podPhaseToState(pod.Status.Phase)
+		state, reason, message := waitForPodReady(pod)
 
 		if filters.State != nil && state != *filters.State {
 			continue
 		}
 
 		instances = append(instances, &cloud.CloudInstance{
-			ID:         instanceID,
-			Platform:   cloud.PlatformLinux,
-			State:      state,
-			Provider:   "k8s",
-			InstanceID: pod.Name,
-			PrivateIP:  pod.Status.PodIP,
-			TeamID:     pod.Labels["hanzo.ai/team"],
-			BotPackage: pod.Labels["hanzo.ai/bot-package"],
+			ID:           instanceID,
+			Platform:     cloud.PlatformLinux,
+			State:        state,
+			StateReason:  reason,
+			StateMessage: message,
+			Provider:     "k8s",
+			InstanceID:   pod.Name,
+			PrivateIP:    pod.Status.PodIP,
+			TeamID:       pod.Labels["hanzo.ai/team"],
+			BotPackage:   pod.Labels["hanzo.ai/bot-package"],
 		})
 	}
 
@@ -222,12 +285,27 @@ func (p *Provisioner) TerminateInstance(ctx context.Context, instanceID string)
 		return err
 	}
 
-	err = p.client.CoreV1().Pods(p.config.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+	kind := cloud.WorkloadKind(pod.Labels["hanzo.ai/workload-kind"])
+	workloadName := pod.Labels["hanzo.ai/workload-name"]
+	if workloadName == "" {
+		workloadName = pod.Name
+	}
+
+	switch kind {
+	case cloud.WorkloadKindJob:
+		err = p.deleteJob(ctx, workloadName)
+	case cloud.WorkloadKindStatefulSet:
+		err = p.deleteStatefulSet(ctx, workloadName)
+	case cloud.WorkloadKindDeployment:
+		err = p.deleteDeployment(ctx, workloadName)
+	default:
+		err = p.client.CoreV1().Pods(p.config.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+	}
 	if err != nil {
-		return fmt.Errorf("failed to delete pod %s: %w", pod.Name, err)
+		return fmt.Errorf("failed to delete %s %s: %w", kind, workloadName, err)
 	}
 
-	log.Info().Str("pod", pod.Name).Str("instance_id", instanceID).Msg("K8s pod terminated")
+	log.Info().Str("workload", workloadName).Str("kind", string(kind)).Str("instance_id", instanceID).Msg("K8s workload terminated")
 	return nil
 }
 
@@ -315,6 +393,103 @@ func (p *Provisioner) GetLogs(ctx context.Context, instanceID string, lines int)
 	return buf.String(), nil
 }
 
+// StreamCommand uses SPDY exec to run cmd inside the pod, keeping the
+// session open instead of buffering into a CommandResult: stdin is wired
+// through for as long as the caller keeps writing to it, and stdout/stderr
+// are readable as they arrive, for the WebSocket-backed terminal handler.
+func (p *Provisioner) StreamCommand(ctx context.Context, instanceID string, cmd []string, stdin io.Reader) (io.ReadCloser, io.ReadCloser, <-chan int, error) {
+	pod, err := p.findPodByInstanceID(ctx, instanceID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	req := p.client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(p.config.Namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Command: cmd,
+			Stdin:   stdin != nil,
+			Stdout:  true,
+			Stderr:  true,
+		}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(p.restCfg, "POST", req.URL())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	stdoutR, stdoutW := io.Pipe()
+	stderrR, stderrW := io.Pipe()
+	exitCh := make(chan int, 1)
+
+	go func() {
+		streamErr := exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+			Stdin:  stdin,
+			Stdout: stdoutW,
+			Stderr: stderrW,
+		})
+
+		exitCode := 0
+		if streamErr != nil {
+			exitCode = 1
+		}
+		exitCh <- exitCode
+		close(exitCh)
+
+		stdoutW.CloseWithError(streamErr)
+		stderrW.CloseWithError(streamErr)
+	}()
+
+	return stdoutR, stderrR, exitCh, nil
+}
+
+// TailLogs streams the pod's logs as cloud.LogLine values, following new
+// lines as they're written when opts.Follow is set rather than buffering
+// the whole backlog into a string.
+func (p *Provisioner) TailLogs(ctx context.Context, instanceID string, opts cloud.LogTailOptions) (<-chan cloud.LogLine, error) {
+	pod, err := p.findPodByInstanceID(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	podLogOpts := &corev1.PodLogOptions{
+		Follow:   opts.Follow,
+		Previous: opts.Previous,
+	}
+	if opts.Lines > 0 {
+		tailLines := int64(opts.Lines)
+		podLogOpts.TailLines = &tailLines
+	}
+	if !opts.SinceTime.IsZero() {
+		sinceTime := metav1.NewTime(opts.SinceTime)
+		podLogOpts.SinceTime = &sinceTime
+	}
+
+	stream, err := p.client.CoreV1().Pods(p.config.Namespace).GetLogs(pod.Name, podLogOpts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod logs: %w", err)
+	}
+
+	lines := make(chan cloud.LogLine)
+	go func() {
+		defer close(lines)
+		defer stream.Close()
+
+		scanner := bufio.NewScanner(stream)
+		for scanner.Scan() {
+			select {
+			case lines <- cloud.LogLine{Timestamp: time.Now().UTC(), Text: scanner.Text()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return lines, nil
+}
+
 // findPodByInstanceID finds a pod by the cloud instance ID label.
 func (p *Provisioner) findPodByInstanceID(ctx context.Context, instanceID string) (*corev1.Pod, error) {
 	pods, err := p.client.CoreV1().Pods(p.config.Namespace).List(ctx, metav1.ListOptions{
@@ -344,3 +519,188 @@ func podPhaseToState(phase corev1.PodPhase) cloud.InstanceState {
 		return cloud.InstanceStateFailed
 	}
 }
+
+// waitForPodReady refines podPhaseToState's coarse signal: PodRunning only
+// means every container has started, not that the agent is actually ready
+// to serve work (its image may still be pulling, an init container may
+// still be running, or it may be crash-looping). It only reports
+// InstanceStateRunning once every container reports Ready=true, whatever
+// its restart count; otherwise it returns a structured
+// reason/message describing what's still pending. A container that has
+// restarted at all while not Ready is treated as crash-looping, since this
+// provisioner doesn't track a prior restart count to detect whether it's
+// still climbing.
+func waitForPodReady(pod *corev1.Pod) (state cloud.InstanceState, reason, message string) {
+	for _, ic := range pod.Status.InitContainerStatuses {
+		if ic.State.Waiting != nil {
+			return cloud.InstanceStateProvisioning, ic.State.Waiting.Reason, ic.State.Waiting.Message
+		}
+		if ic.State.Terminated != nil && ic.State.Terminated.ExitCode != 0 {
+			return cloud.InstanceStateFailed, "InitContainerFailed", ic.State.Terminated.Message
+		}
+	}
+
+	state = podPhaseToState(pod.Status.Phase)
+	if state != cloud.InstanceStateRunning {
+		return state, "", ""
+	}
+
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return cloud.InstanceStateProvisioning, "ContainersNotReady", "waiting for container statuses to be reported"
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil {
+			return cloud.InstanceStateProvisioning, cs.State.Waiting.Reason, cs.State.Waiting.Message
+		}
+		if cs.State.Terminated != nil {
+			return cloud.InstanceStateFailed, cs.State.Terminated.Reason, cs.State.Terminated.Message
+		}
+		if !cs.Ready {
+			if cs.RestartCount > 0 {
+				return cloud.InstanceStateFailed, "CrashLoopBackOff",
+					fmt.Sprintf("container %s has restarted %d time(s) and is not ready", cs.Name, cs.RestartCount)
+			}
+			return cloud.InstanceStateProvisioning, "ContainersNotReady",
+				fmt.Sprintf("container %s is not yet ready", cs.Name)
+		}
+	}
+
+	return cloud.InstanceStateRunning, "", ""
+}
+
+// buildTolerations converts configured K8sTolerations to their corev1 form.
+func buildTolerations(tolerations []cloud.K8sToleration) []corev1.Toleration {
+	if len(tolerations) == 0 {
+		return nil
+	}
+
+	out := make([]corev1.Toleration, 0, len(tolerations))
+	for _, t := range tolerations {
+		out = append(out, corev1.Toleration{
+			Key:      t.Key,
+			Operator: corev1.TolerationOperator(t.Operator),
+			Value:    t.Value,
+			Effect:   corev1.TaintEffect(t.Effect),
+		})
+	}
+	return out
+}
+
+// buildAffinity converts a configured K8sAffinity's required node-label
+// match into a corev1.Affinity, or nil if none is configured.
+func buildAffinity(affinity *cloud.K8sAffinity) *corev1.Affinity {
+	if affinity == nil || len(affinity.RequiredNodeLabels) == 0 {
+		return nil
+	}
+
+	exprs := make([]corev1.NodeSelectorRequirement, 0, len(affinity.RequiredNodeLabels))
+	for k, v := range affinity.RequiredNodeLabels {
+		exprs = append(exprs, corev1.NodeSelectorRequirement{
+			Key:      k,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{v},
+		})
+	}
+
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: exprs},
+				},
+			},
+		},
+	}
+}
+
+// buildInitContainers converts configured K8sContainers to their corev1
+// form.
+func buildInitContainers(containers []cloud.K8sContainer) []corev1.Container {
+	if len(containers) == 0 {
+		return nil
+	}
+
+	out := make([]corev1.Container, 0, len(containers))
+	for _, c := range containers {
+		out = append(out, corev1.Container{
+			Name:    c.Name,
+			Image:   c.Image,
+			Command: c.Command,
+		})
+	}
+	return out
+}
+
+// resourcesFromRequirements translates InstanceRequirements into pod
+// resource requests/limits, the Kubernetes equivalent of AWS's
+// SelectInstanceType right-sizing: instead of picking a named instance
+// type, the scheduler picks a node with room for exactly the requested
+// shape. Any bound left unset in requirements keeps defaults' value.
+func resourcesFromRequirements(requirements cloud.InstanceRequirements, defaults corev1.ResourceRequirements) corev1.ResourceRequirements {
+	out := corev1.ResourceRequirements{
+		Requests: cloneResourceList(defaults.Requests),
+		Limits:   cloneResourceList(defaults.Limits),
+	}
+
+	if requirements.MinVCPU > 0 {
+		out.Requests[corev1.ResourceCPU] = resource.MustParse(strconv.Itoa(requirements.MinVCPU))
+	}
+	if requirements.MaxVCPU > 0 {
+		out.Limits[corev1.ResourceCPU] = resource.MustParse(strconv.Itoa(requirements.MaxVCPU))
+	}
+	if requirements.MinMemoryGiB > 0 {
+		out.Requests[corev1.ResourceMemory] = resource.MustParse(fmt.Sprintf("%.2fGi", requirements.MinMemoryGiB))
+	}
+	if requirements.MaxMemoryGiB > 0 {
+		out.Limits[corev1.ResourceMemory] = resource.MustParse(fmt.Sprintf("%.2fGi", requirements.MaxMemoryGiB))
+	}
+	if requirements.GPUCount > 0 {
+		out.Limits[corev1.ResourceName("nvidia.com/gpu")] = resource.MustParse(strconv.Itoa(requirements.GPUCount))
+	}
+
+	return out
+}
+
+// cloneResourceList returns a shallow copy of list so callers can add
+// entries without mutating a shared default.
+func cloneResourceList(list corev1.ResourceList) corev1.ResourceList {
+	out := make(corev1.ResourceList, len(list))
+	for k, v := range list {
+		out[k] = v
+	}
+	return out
+}
+
+// nodeSelectorFromRequirements merges base with an architecture constraint
+// derived from requirements, the Kubernetes equivalent of AWS's
+// Architecture filter: the scheduler only considers nodes in the matching
+// arm64/x86_64 node pool.
+func nodeSelectorFromRequirements(base map[string]string, requirements *cloud.InstanceRequirements) map[string]string {
+	if requirements == nil || requirements.Architecture == "" {
+		return base
+	}
+
+	out := make(map[string]string, len(base)+1)
+	for k, v := range base {
+		out[k] = v
+	}
+	out["kubernetes.io/arch"] = requirements.Architecture
+	return out
+}
+
+// buildReadinessProbe converts a configured K8sProbe to a corev1.Probe
+// running its command inside the container, or nil if none is configured.
+func buildReadinessProbe(probe *cloud.K8sProbe) *corev1.Probe {
+	if probe == nil || len(probe.Command) == 0 {
+		return nil
+	}
+
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{Command: probe.Command},
+		},
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+	}
+}
@@ -0,0 +1,167 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createPod provisions a bare Pod: WorkloadKindPod's default, unrecoverable
+// if it crashes.
+func (p *Provisioner) createPod(ctx context.Context, name string, labels map[string]string, podSpec corev1.PodSpec) error {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: p.config.Namespace,
+			Labels:    labels,
+		},
+		Spec: podSpec,
+	}
+
+	_, err := p.client.CoreV1().Pods(p.config.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	return err
+}
+
+// createJob provisions a batchv1.Job: a crashed bot is retried up to
+// JobBackoffLimit times before the Job gives up, reporting PodFailed.
+func (p *Provisioner) createJob(ctx context.Context, name string, labels map[string]string, podSpec corev1.PodSpec) error {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: p.config.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec,
+			},
+		},
+	}
+	if p.config.JobBackoffLimit > 0 {
+		job.Spec.BackoffLimit = &p.config.JobBackoffLimit
+	}
+	if p.config.JobActiveDeadlineSeconds > 0 {
+		job.Spec.ActiveDeadlineSeconds = &p.config.JobActiveDeadlineSeconds
+	}
+
+	_, err := p.client.BatchV1().Jobs(p.config.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	return err
+}
+
+// createStatefulSet provisions a single-replica StatefulSet with a
+// PVC-backed "data" volume mounted at /var/lib/hanzo-agent, so bot state
+// survives pod restarts and the pod keeps a stable name (<name>-0).
+func (p *Provisioner) createStatefulSet(ctx context.Context, name string, labels map[string]string, podSpec corev1.PodSpec) error {
+	replicas := int32(1)
+
+	pvcSize := p.config.PVCSizeGiB
+	if pvcSize <= 0 {
+		pvcSize = 10
+	}
+
+	volumeClaimTemplate := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(fmt.Sprintf("%dGi", pvcSize)),
+				},
+			},
+		},
+	}
+	if p.config.PVCStorageClass != "" {
+		volumeClaimTemplate.Spec.StorageClassName = &p.config.PVCStorageClass
+	}
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: p.config.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			ServiceName: name,
+			Replicas:    &replicas,
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"hanzo.ai/workload-name": name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec,
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{volumeClaimTemplate},
+		},
+	}
+
+	_, err := p.client.AppsV1().StatefulSets(p.config.Namespace).Create(ctx, sts, metav1.CreateOptions{})
+	return err
+}
+
+// createDeployment provisions a single-replica Deployment: a crashed or
+// evicted bot is rescheduled indefinitely, for long-lived HA use cases.
+func (p *Provisioner) createDeployment(ctx context.Context, name string, labels map[string]string, podSpec corev1.PodSpec) error {
+	replicas := int32(1)
+
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: p.config.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"hanzo.ai/workload-name": name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec,
+			},
+		},
+	}
+
+	_, err := p.client.AppsV1().Deployments(p.config.Namespace).Create(ctx, dep, metav1.CreateOptions{})
+	return err
+}
+
+// deleteJob removes a Job and, via DeletePropagationBackground, its pods.
+func (p *Provisioner) deleteJob(ctx context.Context, name string) error {
+	policy := metav1.DeletePropagationBackground
+	return p.client.BatchV1().Jobs(p.config.Namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &policy,
+	})
+}
+
+// deleteStatefulSet removes a StatefulSet and its pod, then its PVC unless
+// RetainPVCOnTerminate is set.
+func (p *Provisioner) deleteStatefulSet(ctx context.Context, name string) error {
+	policy := metav1.DeletePropagationForeground
+	if err := p.client.AppsV1().StatefulSets(p.config.Namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &policy,
+	}); err != nil {
+		return err
+	}
+
+	if p.config.RetainPVCOnTerminate {
+		return nil
+	}
+
+	pvcName := fmt.Sprintf("data-%s-0", name)
+	err := p.client.CoreV1().PersistentVolumeClaims(p.config.Namespace).Delete(ctx, pvcName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete PVC %s: %w", pvcName, err)
+	}
+	return nil
+}
+
+// deleteDeployment removes a Deployment and its pod.
+func (p *Provisioner) deleteDeployment(ctx context.Context, name string) error {
+	policy := metav1.DeletePropagationForeground
+	return p.client.AppsV1().Deployments(p.config.Namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &policy,
+	})
+}
@@ -0,0 +1,299 @@
+// Package linode implements the cloud.CloudProvisioner interface for Linode
+// compute instances.
+package linode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+const apiBaseURL = "https://api.linode.com/v4"
+
+func init() {
+	cloud.RegisterProviderFactory("linode", func(cfg any) (cloud.CloudProvisioner, error) {
+		liCfg, ok := cfg.(cloud.LinodeConfig)
+		if !ok {
+			return nil, fmt.Errorf("linode: unexpected config type %T", cfg)
+		}
+		return NewProvisioner(liCfg), nil
+	})
+}
+
+// Provisioner implements cloud.CloudProvisioner for Linode instances.
+type Provisioner struct {
+	cfg    cloud.LinodeConfig
+	client *http.Client
+}
+
+// NewProvisioner creates a new Linode provisioner.
+func NewProvisioner(cfg cloud.LinodeConfig) *Provisioner {
+	return &Provisioner{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *Provisioner) ProviderName() string { return "linode" }
+
+// CreateInstance creates a new Linode instance tagged with the cloud instance ID.
+func (p *Provisioner) CreateInstance(ctx context.Context, req *cloud.ProvisionRequest) (*cloud.CloudInstance, error) {
+	instanceID := uuid.New().String()
+
+	body := map[string]interface{}{
+		"label":  fmt.Sprintf("hanzo-bot-%s", instanceID[:8]),
+		"region": p.cfg.Region,
+		"type":   p.cfg.Type,
+		"image":  p.cfg.Image,
+		"tags": []string{
+			"hanzo-agent-bot",
+			"hanzo-instance-" + instanceID,
+			"hanzo-team-" + req.TeamID,
+		},
+	}
+
+	var linode linodeResource
+	if err := p.doRequest(ctx, http.MethodPost, "/linode/instances", body, &linode); err != nil {
+		return nil, &cloud.ProvisionError{
+			InstanceID: instanceID,
+			Platform:   cloud.PlatformLinux,
+			Provider:   "linode",
+			Err:        err,
+		}
+	}
+
+	log.Info().Int("linode_id", linode.ID).Str("instance_id", instanceID).Msg("Linode instance created")
+
+	now := time.Now().UTC()
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        cloud.InstanceStateProvisioning,
+		Provider:     "linode",
+		InstanceID:   strconv.Itoa(linode.ID),
+		InstanceType: p.cfg.Type,
+		ImageID:      p.cfg.Image,
+		Region:       p.cfg.Region,
+		BotPackage:   req.BotPackage,
+		BotVersion:   req.BotVersion,
+		TeamID:       req.TeamID,
+		Tags:         req.Tags,
+		RequestedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// GetInstance returns the current state of a Linode by its cloud instance tag.
+func (p *Provisioner) GetInstance(ctx context.Context, instanceID string) (*cloud.CloudInstance, error) {
+	linode, err := p.findLinodeByTag(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return linodeToInstance(instanceID, linode), nil
+}
+
+// ListInstances returns Linodes matching filters.
+func (p *Provisioner) ListInstances(ctx context.Context, filters cloud.InstanceFilters) ([]*cloud.CloudInstance, error) {
+	var result struct {
+		Data []linodeResource `json:"data"`
+	}
+	if err := p.doRequest(ctx, http.MethodGet, "/linode/instances?tag=hanzo-agent-bot", nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list linodes: %w", err)
+	}
+
+	var instances []*cloud.CloudInstance
+	for _, l := range result.Data {
+		instanceID := tagValue(l.Tags, "hanzo-instance-")
+		teamID := tagValue(l.Tags, "hanzo-team-")
+		if filters.TeamID != nil && teamID != *filters.TeamID {
+			continue
+		}
+		inst := linodeToInstance(instanceID, &l)
+		if filters.State != nil && inst.State != *filters.State {
+			continue
+		}
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
+
+func (p *Provisioner) StartInstance(ctx context.Context, instanceID string) error {
+	return p.linodeAction(ctx, instanceID, "boot")
+}
+
+func (p *Provisioner) StopInstance(ctx context.Context, instanceID string) error {
+	return p.linodeAction(ctx, instanceID, "shutdown")
+}
+
+// TerminateInstance deletes the Linode.
+func (p *Provisioner) TerminateInstance(ctx context.Context, instanceID string) error {
+	linode, err := p.findLinodeByTag(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	if err := p.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/linode/instances/%d", linode.ID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete linode %d: %w", linode.ID, err)
+	}
+
+	log.Info().Int("linode_id", linode.ID).Str("instance_id", instanceID).Msg("Linode instance deleted")
+	return nil
+}
+
+// GetConnectionInfo returns SSH connection details for the Linode.
+func (p *Provisioner) GetConnectionInfo(ctx context.Context, instanceID string) (*cloud.ConnectionInfo, error) {
+	linode, err := p.findLinodeByTag(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	host := ""
+	if len(linode.IPv4) > 0 {
+		host = linode.IPv4[0]
+	}
+
+	return &cloud.ConnectionInfo{
+		Protocol: cloud.ConnectionProtocolSSH,
+		Host:     host,
+		Port:     22,
+		Username: "root",
+	}, nil
+}
+
+// ExecuteCommand is not supported directly over the Linode API; callers
+// should connect over the SSH info returned by GetConnectionInfo.
+func (p *Provisioner) ExecuteCommand(ctx context.Context, instanceID, command string) (*cloud.CommandResult, error) {
+	return nil, fmt.Errorf("linode: ExecuteCommand requires an SSH connection, see GetConnectionInfo")
+}
+
+// GetLogs is not supported directly over the Linode API.
+func (p *Provisioner) GetLogs(ctx context.Context, instanceID string, lines int) (string, error) {
+	return "", fmt.Errorf("linode: GetLogs requires an SSH connection, see GetConnectionInfo")
+}
+
+func (p *Provisioner) linodeAction(ctx context.Context, instanceID, action string) error {
+	linode, err := p.findLinodeByTag(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/linode/instances/%d/%s", linode.ID, action)
+	if err := p.doRequest(ctx, http.MethodPost, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to %s linode %d: %w", action, linode.ID, err)
+	}
+	return nil
+}
+
+func (p *Provisioner) findLinodeByTag(ctx context.Context, instanceID string) (*linodeResource, error) {
+	var result struct {
+		Data []linodeResource `json:"data"`
+	}
+	path := "/linode/instances?tag=" + "hanzo-instance-" + instanceID
+	if err := p.doRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to look up linode: %w", err)
+	}
+	if len(result.Data) == 0 {
+		return nil, cloud.ErrInstanceNotFound
+	}
+	return &result.Data[0], nil
+}
+
+func (p *Provisioner) doRequest(ctx context.Context, method, path string, payload interface{}, out interface{}) error {
+	var bodyReader io.Reader
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		bodyReader = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiBaseURL+path, bodyReader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.cfg.Token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("linode API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("linode API returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// linodeResource is the subset of the Linode instance API response we care about.
+type linodeResource struct {
+	ID     int      `json:"id"`
+	Status string   `json:"status"`
+	Type   string   `json:"type"`
+	Image  string   `json:"image"`
+	Region string   `json:"region"`
+	Tags   []string `json:"tags"`
+	IPv4   []string `json:"ipv4"`
+}
+
+func linodeToInstance(instanceID string, l *linodeResource) *cloud.CloudInstance {
+	publicIP := ""
+	if len(l.IPv4) > 0 {
+		publicIP = l.IPv4[0]
+	}
+
+	return &cloud.CloudInstance{
+		ID:           instanceID,
+		Platform:     cloud.PlatformLinux,
+		State:        linodeStatusToState(l.Status),
+		Provider:     "linode",
+		InstanceID:   strconv.Itoa(l.ID),
+		InstanceType: l.Type,
+		ImageID:      l.Image,
+		Region:       l.Region,
+		PublicIP:     publicIP,
+		TeamID:       tagValue(l.Tags, "hanzo-team-"),
+	}
+}
+
+func tagValue(tags []string, prefix string) string {
+	for _, t := range tags {
+		if len(t) > len(prefix) && t[:len(prefix)] == prefix {
+			return t[len(prefix):]
+		}
+	}
+	return ""
+}
+
+func linodeStatusToState(status string) cloud.InstanceState {
+	switch status {
+	case "provisioning", "booting":
+		return cloud.InstanceStateProvisioning
+	case "running":
+		return cloud.InstanceStateRunning
+	case "offline", "shutting_down":
+		return cloud.InstanceStateStopped
+	case "deleting":
+		return cloud.InstanceStateTerminated
+	default:
+		return cloud.InstanceStateFailed
+	}
+}
@@ -2,26 +2,48 @@ package cloud
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
 
+	"github.com/hanzoai/agents/control-plane/internal/services"
 	"github.com/hanzoai/agents/control-plane/internal/storage"
 )
 
 // CloudManager dispatches cloud operations to the correct provisioner by platform.
 type CloudManager struct {
-	mu           sync.RWMutex
-	config       CloudConfig
-	store        storage.StorageProvider
-	provisioners map[Platform]CloudProvisioner
-	eventBus     *EventBus
-	billing      BillingAuthorizer
+	mu               sync.RWMutex
+	config           CloudConfig
+	store            storage.StorageProvider
+	provisioners     map[Platform]CloudProvisioner
+	eventBus         *EventBus
+	billing          BillingAuthorizer
+	holds            HoldService
+	credentials      CredentialBroker
+	credRefresher    *CredentialRefresher
+	drainHook        DrainHook
+	images           ImageResolver
+	registerer       prometheus.Registerer
+	instancesActive  *prometheus.GaugeVec
+	bootstrap        *BootstrapIssuer
+	freezes          *AccountFreezeService
+	windowsPasswords *WindowsPasswordPoller
+
+	rateLimitersMu     sync.Mutex
+	rateLimiters       map[string]*RateLimiter
+	rateLimiterMetrics *RateLimiterMetrics
 }
 
-// NewCloudManager creates a new CloudManager.
-func NewCloudManager(cfg CloudConfig, store storage.StorageProvider) *CloudManager {
+// NewCloudManager creates a new CloudManager. registerer may be nil, in
+// which case instance metrics are tracked in-process but never exposed, and
+// registered provisioners that implement MetricsAware get a nil registerer
+// too (their own metrics constructors must tolerate that the same way).
+func NewCloudManager(cfg CloudConfig, store storage.StorageProvider, registerer prometheus.Registerer) *CloudManager {
 	var billing BillingAuthorizer
 	if cfg.Billing.Enabled && cfg.Billing.ServiceURL != "" {
 		billing = NewHTTPBillingClient(cfg.Billing.ServiceURL, cfg.Billing.APIKey)
@@ -31,13 +53,43 @@ func NewCloudManager(cfg CloudConfig, store storage.StorageProvider) *CloudManag
 		log.Info().Msg("cloud billing disabled, all provisioning allowed")
 	}
 
-	return &CloudManager{
-		config:       cfg,
-		store:        store,
-		provisioners: make(map[Platform]CloudProvisioner),
-		eventBus:     NewEventBus(200),
-		billing:      billing,
-	}
+	instancesActive := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hanzo_cloud_instances_active",
+		Help: "Current number of cloud instances, by platform and state.",
+	}, []string{"platform", "state"})
+	if registerer != nil {
+		registerer.MustRegister(instancesActive)
+	}
+
+	manager := &CloudManager{
+		config:             cfg,
+		store:              store,
+		provisioners:       make(map[Platform]CloudProvisioner),
+		eventBus:           NewEventBus(200, "/cloud/hanzo-agents"),
+		billing:            billing,
+		credentials:        NoopCredentialBroker{},
+		registerer:         registerer,
+		instancesActive:    instancesActive,
+		rateLimiters:       make(map[string]*RateLimiter),
+		rateLimiterMetrics: NewRateLimiterMetrics(registerer),
+		bootstrap:          NewBootstrapIssuer(cfg.Bootstrap.SigningKey, cfg.Bootstrap.TokenTTL),
+	}
+	manager.credRefresher = NewCredentialRefresher(manager.credentials, manager.eventBus)
+	manager.freezes = NewAccountFreezeService(store, manager.eventBus, cfg.Freeze)
+	manager.windowsPasswords = NewWindowsPasswordPoller(manager.lookupProvisioner, store, manager.eventBus)
+	return manager
+}
+
+// lookupProvisioner returns the registered CloudProvisioner for platform, if
+// any. WindowsPasswordPoller receives it as a method value on the
+// *CloudManager, so each lookup reads the current provisioner map and sees
+// provisioners registered after NewCloudManager via RegisterProvisioner.
+func (m *CloudManager) lookupProvisioner(platform Platform) (CloudProvisioner, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.provisioners[platform]
+	return p, ok
 }
 
 // Billing returns the billing authorizer.
@@ -45,6 +97,44 @@ func (m *CloudManager) Billing() BillingAuthorizer {
 	return m.billing
 }
 
+// SetCredentialBroker installs a CredentialBroker used to rotate short-lived
+// credentials for provisioned instances (e.g. AWS STS AssumeRole). Call this
+// during server wiring after constructing the relevant provisioner; defaults
+// to NoopCredentialBroker when never called.
+func (m *CloudManager) SetCredentialBroker(broker CredentialBroker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.credentials = broker
+	m.credRefresher = NewCredentialRefresher(broker, m.eventBus)
+}
+
+// SetImageResolver installs an ImageResolver used to translate symbolic
+// "bot_package@version" image references into concrete provider image IDs
+// at provisioning time. Defaults to unset, in which case ProvisionRequests
+// without an explicit ImageOverride use the platform's configured default
+// image.
+func (m *CloudManager) SetImageResolver(resolver ImageResolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.images = resolver
+}
+
+// SetHoldService installs a HoldService (satisfied by
+// *services.BillingService) used to reserve an instance's estimated max
+// cost via PlaceHold at CreateInstance time and settle it via
+// CaptureHold/ReleaseHold once the instance terminates, instead of only
+// debiting after the fact. Call this during server wiring; hold placement
+// is skipped entirely (provisioning proceeds exactly as before) when never
+// called.
+func (m *CloudManager) SetHoldService(holds HoldService) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.holds = holds
+}
+
 // RegisterProvisioner registers a provisioner for one or more platforms.
 func (m *CloudManager) RegisterProvisioner(platforms []Platform, p CloudProvisioner) {
 	m.mu.Lock()
@@ -54,6 +144,58 @@ func (m *CloudManager) RegisterProvisioner(platforms []Platform, p CloudProvisio
 		m.provisioners[platform] = p
 		log.Info().Str("platform", string(platform)).Str("provider", p.ProviderName()).Msg("registered cloud provisioner")
 	}
+
+	if aware, ok := p.(MetricsAware); ok {
+		aware.SetMetricsRegisterer(m.registerer)
+	}
+}
+
+// RegisterConfiguredDrivers builds and registers a CloudProvisioner for
+// every enabled entry in Config().Drivers, using the provider's own factory
+// registered via RegisterProviderFactory. Call this after constructing the
+// manager and before serving traffic; AWS and K8s are wired up separately
+// since they need additional runtime dependencies (SSM clients, k8s
+// clientset) that a generic config section can't express.
+func (m *CloudManager) RegisterConfiguredDrivers() error {
+	for _, dc := range m.config.Drivers {
+		if !dc.Enabled {
+			continue
+		}
+
+		cfg, ok := driverConfig(m.config, dc.Name)
+		if !ok {
+			return fmt.Errorf("%w: no config section for driver %q", ErrInvalidPlatform, dc.Name)
+		}
+
+		prov, err := NewProviderProvisioner(dc.Name, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to build %q driver: %w", dc.Name, err)
+		}
+
+		m.RegisterProvisioner(dc.Platforms, prov)
+	}
+	return nil
+}
+
+// rateLimiterFor returns (creating if necessary) the RateLimiter for the
+// named provider, built from CloudConfig.RateLimits or
+// defaultRateLimiterConfig if the provider has no explicit entry.
+func (m *CloudManager) rateLimiterFor(provider string) *RateLimiter {
+	m.rateLimitersMu.Lock()
+	defer m.rateLimitersMu.Unlock()
+
+	if rl, ok := m.rateLimiters[provider]; ok {
+		return rl
+	}
+
+	cfg, ok := m.config.RateLimits[provider]
+	if !ok {
+		cfg = defaultRateLimiterConfig
+	}
+
+	rl := NewRateLimiter(provider, cfg, m.rateLimiterMetrics)
+	m.rateLimiters[provider] = rl
+	return rl
 }
 
 // EventBus returns the cloud event bus for subscribers.
@@ -66,12 +208,42 @@ func (m *CloudManager) Config() CloudConfig {
 	return m.config
 }
 
+// Bootstrap returns the issuer that mints and redeems instance bootstrap
+// tokens.
+func (m *CloudManager) Bootstrap() *BootstrapIssuer {
+	return m.bootstrap
+}
+
+// Freezes returns the account freeze service.
+func (m *CloudManager) Freezes() *AccountFreezeService {
+	return m.freezes
+}
+
 // CreateInstance provisions a new cloud instance.
 func (m *CloudManager) CreateInstance(ctx context.Context, req *ProvisionRequest) (*CloudInstance, error) {
 	if !m.config.Enabled {
 		return nil, ErrCloudDisabled
 	}
 
+	// Requirements.CapacityType, when set, overrides UseSpot (see its doc
+	// comment on InstanceRequirements) - normalize it here, before billing
+	// authorization and CreateInstance, so every UseSpot check below (spot
+	// metadata tagging, billing rate, the provisioner's spot launch options)
+	// sees one consistent signal instead of just the instance-type selector.
+	if req.Requirements != nil && req.Requirements.CapacityType != "" {
+		req.UseSpot = req.Requirements.CapacityType == "spot"
+	}
+
+	// Refuse to provision for a frozen account.
+	if req.TeamID != "" && m.freezes != nil {
+		frozen, err := m.freezes.IsFrozen(ctx, req.TeamID)
+		if err != nil {
+			log.Error().Err(err).Str("team", req.TeamID).Msg("failed to check account freeze state")
+		} else if frozen {
+			return nil, ErrAccountFrozen
+		}
+	}
+
 	// Check team instance limit.
 	if req.TeamID != "" && m.store != nil {
 		count, err := m.store.CountCloudInstancesByTeam(ctx, req.TeamID)
@@ -81,7 +253,11 @@ func (m *CloudManager) CreateInstance(ctx context.Context, req *ProvisionRequest
 	}
 
 	// Billing authorization check.
-	auth, err := m.billing.AuthorizeProvisioning(ctx, req.TeamID, req.Platform, req.InstanceType)
+	expectedLifetimeHours := req.ExpectedLifetimeHours
+	if expectedLifetimeHours <= 0 {
+		expectedLifetimeHours = 1
+	}
+	auth, err := m.billing.AuthorizeProvisioning(ctx, req.TeamID, req.Platform, req.InstanceType, expectedLifetimeHours)
 	if err != nil {
 		log.Error().Err(err).Str("team", req.TeamID).Msg("billing authorization check failed")
 		return nil, ErrBillingServiceUnavailable
@@ -90,26 +266,99 @@ func (m *CloudManager) CreateInstance(ctx context.Context, req *ProvisionRequest
 		log.Warn().Str("team", req.TeamID).Str("reason", auth.Reason).Msg("billing denied provisioning")
 		return nil, fmt.Errorf("%w: %s", ErrBillingNotAuthorized, auth.Reason)
 	}
+	for _, warning := range auth.Warnings {
+		log.Warn().Str("team", req.TeamID).Str("warning", warning).Msg("budget warning on provisioning")
+	}
 
 	prov, err := m.getProvisioner(req.Platform)
 	if err != nil {
 		return nil, err
 	}
 
+	if req.ImageOverride == "" && m.images != nil {
+		if imageID, ok := m.images.ResolveImage(ctx, req.Platform, req.BotPackage, req.BotVersion); ok {
+			req.ImageOverride = imageID
+		}
+	}
+
+	if req.Requirements != nil {
+		if selector, ok := prov.(InstanceTypeProvider); ok {
+			instanceType, err := selector.SelectInstanceType(ctx, *req.Requirements)
+			if err != nil {
+				return nil, fmt.Errorf("select instance type: %w", err)
+			}
+			req.InstanceType = instanceType
+		}
+	}
+
+	// Draw down the team's prepaid package minutes before reserving a
+	// metered Commerce hold at all - a run a package balance fully covers
+	// doesn't need funds reserved against it.
+	var covered bool
+	if m.holds != nil {
+		covered, err = m.holds.CheckCoverage(ctx, req.TeamID, string(req.Platform), req.TeamID, expectedLifetimeHours*60, 0)
+		if err != nil {
+			log.Warn().Err(err).Str("team", req.TeamID).Msg("package coverage check failed, falling back to metered hold")
+			covered = false
+		}
+	}
+
+	// Reserve the run's estimated max cost up front via a Commerce hold
+	// (the max plausible cost, captured down to the actual cost once the
+	// instance terminates), instead of only debiting after the fact.
+	// Skipped when the package balance above already covers it, or when no
+	// HoldService is wired in.
+	var holdID string
+	if m.holds != nil && !covered {
+		estimatedCents := int64(float64(ProviderHourlyCents(prov.ProviderName(), req.Platform, req.UseSpot)) * expectedLifetimeHours)
+		holdID, err = m.holds.PlaceHold(ctx, services.HoldParams{
+			User:        req.TeamID,
+			AmountCents: estimatedCents,
+			Notes:       fmt.Sprintf("cloud instance: %s/%s", req.Platform, req.BotPackage),
+			TTL:         time.Duration(expectedLifetimeHours*2) * time.Hour,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrInsufficientFunds) {
+				return nil, fmt.Errorf("%w: insufficient funds for estimated cost", ErrHoldDenied)
+			}
+			return nil, fmt.Errorf("place billing hold: %w", err)
+		}
+	}
+
 	m.eventBus.EmitInstanceEvent(EventInstanceRequested, "", map[string]string{
 		"platform":    string(req.Platform),
 		"bot_package": req.BotPackage,
 		"team_id":     req.TeamID,
 	})
 
+	if err := m.rateLimiterFor(prov.ProviderName()).Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	inst, err := prov.CreateInstance(ctx, req)
 	if err != nil {
+		if holdID != "" {
+			if relErr := m.holds.ReleaseHold(ctx, holdID); relErr != nil {
+				log.Error().Err(relErr).Str("hold_id", holdID).Msg("failed to release billing hold after failed provisioning")
+			}
+		}
 		return nil, err
 	}
 
-	// Attach billing metadata to instance.
-	inst.HourlyRateCents = auth.HourlyCents
+	if req.UseSpot {
+		inst.Metadata = withSpotMetadata(inst.Metadata, SpotMetadata{
+			Spot:               true,
+			MaxPriceCents:      req.MaxSpotPriceCents,
+			FallbackToOnDemand: req.FallbackToOnDemand,
+		})
+	}
+
+	// Attach billing metadata to instance. Providers other than AWS/K8s bill
+	// at their own rate, so re-resolve it now that we know which provider
+	// actually handled the request.
+	inst.HourlyRateCents = ProviderHourlyCents(inst.Provider, req.Platform, readSpotMetadata(inst.Metadata).Spot)
 	inst.BillingTier = auth.Tier
+	inst.BillingHoldID = holdID
 
 	// Persist to storage.
 	if m.store != nil {
@@ -118,7 +367,16 @@ func (m *CloudManager) CreateInstance(ctx context.Context, req *ProvisionRequest
 		}
 	}
 
-	m.eventBus.EmitInstanceEvent(EventInstanceProvisioning, inst.ID, inst)
+	m.eventBus.EmitPlatformEvent(EventInstanceProvisioning, inst.Platform, inst.ID, inst)
+
+	if m.config.AWS.EnforceIMDSv2 && inst.Provider == "aws" {
+		m.credRefresher.Watch(inst.ID)
+	}
+
+	if inst.Platform == PlatformWindows {
+		m.windowsPasswords.Watch(inst.ID)
+	}
+
 	return inst, nil
 }
 
@@ -209,7 +467,7 @@ func (m *CloudManager) StopInstance(ctx context.Context, instanceID string) erro
 		_ = m.store.UpdateCloudInstance(ctx, inst)
 	}
 
-	m.eventBus.EmitInstanceEvent(EventInstanceStopped, instanceID, nil)
+	m.eventBus.EmitPlatformEvent(EventInstanceStopped, inst.Platform, instanceID, nil)
 	return nil
 }
 
@@ -220,16 +478,34 @@ func (m *CloudManager) TerminateInstance(ctx context.Context, instanceID string)
 		return err
 	}
 
+	if err := m.rateLimiterFor(prov.ProviderName()).Wait(ctx); err != nil {
+		return err
+	}
+
 	if err := prov.TerminateInstance(ctx, instanceID); err != nil {
 		return err
 	}
 
+	// Settle the hold PlaceHold reserved in CreateInstance against the
+	// instance's actual accrued cost (tracked incrementally by the
+	// scheduler's accrueInstanceCost), debiting that amount and releasing
+	// the rest of the reservation back to the team's available balance.
+	if m.holds != nil && inst.BillingHoldID != "" {
+		if _, err := m.holds.CaptureHold(ctx, inst.BillingHoldID, int64(inst.AccruedCostCents)); err != nil {
+			log.Error().Err(err).Str("id", instanceID).Str("hold_id", inst.BillingHoldID).Msg("failed to capture billing hold at termination")
+		} else {
+			inst.BillingHoldID = ""
+		}
+	}
+
 	if m.store != nil {
 		inst.State = InstanceStateTerminated
 		_ = m.store.UpdateCloudInstance(ctx, inst)
 	}
 
-	m.eventBus.EmitInstanceEvent(EventInstanceTerminated, instanceID, nil)
+	m.credRefresher.StopWatching(instanceID)
+
+	m.eventBus.EmitPlatformEvent(EventInstanceTerminated, inst.Platform, instanceID, nil)
 	return nil
 }
 
@@ -260,6 +536,197 @@ func (m *CloudManager) GetLogs(ctx context.Context, instanceID string, lines int
 	return prov.GetLogs(ctx, instanceID, lines)
 }
 
+// SnapshotInstance images the given instance via its provisioner, for
+// callers such as the image builder that turn a provisioned instance into a
+// reusable golden image. Returns ErrInvalidPlatform if the instance's
+// provisioner does not implement Snapshotter.
+func (m *CloudManager) SnapshotInstance(ctx context.Context, instanceID string, tags map[string]string) (string, error) {
+	_, prov, err := m.resolveInstance(ctx, instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	snapshotter, ok := prov.(Snapshotter)
+	if !ok {
+		return "", fmt.Errorf("%w: provisioner %s cannot snapshot instances", ErrInvalidPlatform, prov.ProviderName())
+	}
+
+	return snapshotter.SnapshotInstance(ctx, instanceID, tags)
+}
+
+// StreamCommand starts an interactive exec session on an instance via its
+// provisioner. Returns ErrInvalidPlatform if the instance's provisioner does
+// not implement StreamingExecutor.
+func (m *CloudManager) StreamCommand(ctx context.Context, instanceID string, cmd []string, stdin io.Reader) (io.ReadCloser, io.ReadCloser, <-chan int, error) {
+	_, prov, err := m.resolveInstance(ctx, instanceID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	executor, ok := prov.(StreamingExecutor)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%w: provisioner %s does not support streaming exec", ErrInvalidPlatform, prov.ProviderName())
+	}
+
+	return executor.StreamCommand(ctx, instanceID, cmd, stdin)
+}
+
+// TailLogs streams an instance's logs via its provisioner. Returns
+// ErrInvalidPlatform if the instance's provisioner does not implement
+// StreamingExecutor.
+func (m *CloudManager) TailLogs(ctx context.Context, instanceID string, opts LogTailOptions) (<-chan LogLine, error) {
+	_, prov, err := m.resolveInstance(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	executor, ok := prov.(StreamingExecutor)
+	if !ok {
+		return nil, fmt.Errorf("%w: provisioner %s does not support streaming logs", ErrInvalidPlatform, prov.ProviderName())
+	}
+
+	return executor.TailLogs(ctx, instanceID, opts)
+}
+
+// refreshInstanceMetrics recomputes the hanzo_cloud_instances_active gauge
+// from current instance state, grouped by platform and state. Called
+// periodically from CloudInstanceMonitor rather than on every mutation,
+// since it's a full re-aggregation and the gauge only needs to be roughly
+// current for dashboards/alerting.
+func (m *CloudManager) refreshInstanceMetrics(ctx context.Context) {
+	instances, err := m.store.ListCloudInstances(ctx, InstanceFilters{})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list instances for metrics refresh")
+		return
+	}
+
+	type key struct{ platform, state string }
+	counts := make(map[key]int)
+	for _, inst := range instances {
+		counts[key{string(inst.Platform), string(inst.State)}]++
+	}
+
+	m.instancesActive.Reset()
+	for k, count := range counts {
+		m.instancesActive.WithLabelValues(k.platform, k.state).Set(float64(count))
+	}
+}
+
+// Reconcile cross-references storage against the live state reported by
+// every registered provisioner and heals drift that can build up after a
+// crash or manual intervention: instances the provider still has but
+// storage lost track of are re-imported, instances storage still thinks are
+// live but the provider no longer has are marked terminated, and any
+// provisioner that manages its own host pool (HostReconciler) is asked to
+// release hosts left pointing at an instance that no longer exists. This is
+// a full provider-side list per call, so it's meant to run far less often
+// than the per-instance sync in CloudInstanceMonitor.tick.
+func (m *CloudManager) Reconcile(ctx context.Context) error {
+	if m.store == nil {
+		return nil
+	}
+
+	stored, err := m.store.ListCloudInstances(ctx, InstanceFilters{})
+	if err != nil {
+		return fmt.Errorf("reconcile: failed to list stored instances: %w", err)
+	}
+	storedByID := make(map[string]*CloudInstance, len(stored))
+	for _, inst := range stored {
+		storedByID[inst.ID] = inst
+	}
+
+	m.mu.RLock()
+	provisioners := make([]CloudProvisioner, 0, len(m.provisioners))
+	seen := make(map[CloudProvisioner]bool, len(m.provisioners))
+	for _, prov := range m.provisioners {
+		if seen[prov] {
+			continue
+		}
+		seen[prov] = true
+		provisioners = append(provisioners, prov)
+	}
+	m.mu.RUnlock()
+
+	live := make(map[string]*CloudInstance)
+	for _, prov := range provisioners {
+		liveInstances, err := prov.ListInstances(ctx, InstanceFilters{})
+		if err != nil {
+			log.Warn().Err(err).Str("provider", prov.ProviderName()).Msg("reconcile: failed to list live instances")
+			continue
+		}
+		for _, inst := range liveInstances {
+			if inst.ID == "" {
+				continue // not one of ours (missing the hanzo.ai/cloud-instance tag)
+			}
+			live[inst.ID] = inst
+		}
+	}
+
+	// Instances the provider has but storage doesn't: re-import.
+	for id, inst := range live {
+		if _, ok := storedByID[id]; ok {
+			continue
+		}
+
+		now := time.Now().UTC()
+		inst.RequestedAt = now
+		inst.CreatedAt = now
+		inst.UpdatedAt = now
+		if err := m.store.CreateCloudInstance(ctx, inst); err != nil {
+			log.Error().Err(err).Str("id", id).Msg("reconcile: failed to re-import untracked instance")
+			continue
+		}
+
+		log.Info().Str("id", id).Str("provider", inst.Provider).Msg("reconcile: re-imported instance missing from storage")
+		m.eventBus.EmitPlatformEvent(EventInstanceReconciled, inst.Platform, id, inst)
+	}
+
+	// Instances storage thinks are live but the provider no longer has: mark terminated.
+	for id, inst := range storedByID {
+		if inst.State == InstanceStateTerminated || inst.State == InstanceStateFailed {
+			continue
+		}
+		if _, ok := live[id]; ok {
+			continue
+		}
+
+		log.Warn().Str("id", id).Msg("reconcile: instance missing from provider, marking terminated")
+
+		inst.State = InstanceStateTerminated
+		now := time.Now().UTC()
+		inst.TerminatedAt = &now
+		inst.UpdatedAt = now
+		if err := m.store.UpdateCloudInstance(ctx, inst); err != nil {
+			log.Error().Err(err).Str("id", id).Msg("reconcile: failed to mark orphaned instance terminated")
+			continue
+		}
+
+		m.eventBus.EmitPlatformEvent(EventInstanceOrphaned, inst.Platform, id, nil)
+	}
+
+	// Release any hosts left allocated to an instance that no longer exists.
+	liveIDs := make(map[string]bool, len(live))
+	for id := range live {
+		liveIDs[id] = true
+	}
+	for _, prov := range provisioners {
+		reconciler, ok := prov.(HostReconciler)
+		if !ok {
+			continue
+		}
+		released, err := reconciler.ReconcileOrphanedHosts(ctx, liveIDs)
+		if err != nil {
+			log.Warn().Err(err).Str("provider", prov.ProviderName()).Msg("reconcile: failed to reconcile orphaned hosts")
+			continue
+		}
+		if released > 0 {
+			log.Info().Int("count", released).Str("provider", prov.ProviderName()).Msg("reconcile: released orphaned hosts")
+		}
+	}
+
+	return nil
+}
+
 // GetSummary returns a dashboard summary of cloud instances.
 func (m *CloudManager) GetSummary(ctx context.Context) (*CloudSummary, error) {
 	if !m.config.Enabled {
@@ -299,17 +766,43 @@ func (m *CloudManager) GetSummary(ctx context.Context) (*CloudSummary, error) {
 		}
 	}
 
-	// Count active dedicated hosts.
+	// Count dedicated hosts by state. ActiveHosts covers every host still in
+	// the pool (allocated or available); released hosts have been returned
+	// to AWS and no longer count.
 	if m.store != nil {
 		hosts, err := m.store.ListDedicatedHosts(ctx)
 		if err == nil {
 			for _, h := range hosts {
-				if h.State == "allocated" {
+				switch h.State {
+				case "allocated":
+					summary.ActiveHosts++
+					summary.AllocatedHosts++
+				case "available":
 					summary.ActiveHosts++
+					summary.AvailableHosts++
 				}
 			}
+			if summary.ActiveHosts > 0 {
+				summary.HostUtilization = float64(summary.AllocatedHosts) / float64(summary.ActiveHosts)
+			}
+		}
+	}
+
+	// Surface live spot prices from any provisioner that tracks them.
+	m.mu.RLock()
+	for _, prov := range m.provisioners {
+		pricer, ok := prov.(SpotPricer)
+		if !ok {
+			continue
+		}
+		for instanceType, cents := range pricer.SpotPrices() {
+			if summary.SpotPrices == nil {
+				summary.SpotPrices = make(map[string]int)
+			}
+			summary.SpotPrices[instanceType] = cents
 		}
 	}
+	m.mu.RUnlock()
 
 	return summary, nil
 }
@@ -1,9 +1,68 @@
 package cloud
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ProviderFactory builds a CloudProvisioner from provider-specific config.
+// Backends register themselves via RegisterProviderFactory so that
+// CloudConfig can stay agnostic of which providers are compiled in,
+// following the pluggable-driver pattern used by tools like go-discover.
+type ProviderFactory func(cfg any) (CloudProvisioner, error)
+
+var (
+	providerFactoriesMu sync.RWMutex
+	providerFactories   = make(map[string]ProviderFactory)
+)
+
+// RegisterProviderFactory registers a named provider backend. Intended to be
+// called from provider packages' init() functions (e.g. cloud/azure).
+func RegisterProviderFactory(name string, factory ProviderFactory) {
+	providerFactoriesMu.Lock()
+	defer providerFactoriesMu.Unlock()
+	providerFactories[name] = factory
+}
+
+// NewProviderProvisioner builds a CloudProvisioner for the named provider
+// using its registered factory.
+func NewProviderProvisioner(name string, cfg any) (CloudProvisioner, error) {
+	providerFactoriesMu.RLock()
+	factory, ok := providerFactories[name]
+	providerFactoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: no provider backend registered for %q", ErrInvalidPlatform, name)
+	}
+	return factory(cfg)
+}
+
+// RegisteredProviders returns the names of all registered provider backends.
+func RegisteredProviders() []string {
+	providerFactoriesMu.RLock()
+	defer providerFactoriesMu.RUnlock()
+
+	names := make([]string, 0, len(providerFactories))
+	for name := range providerFactories {
+		names = append(names, name)
+	}
+	return names
+}
+
+// SpotPricer is an optional capability a CloudProvisioner may implement to
+// expose live spot/preemptible market pricing by instance type. CloudManager
+// type-asserts for it rather than adding it to CloudProvisioner, since most
+// backends (K8s, the bare-capacity providers) have no spot market to track.
+type SpotPricer interface {
+	SpotPrices() map[string]int
+}
 
 // CloudProvisioner defines the interface for cloud instance provisioners.
-// Each provider (K8s, AWS) implements this interface.
+// Each provider (K8s, AWS, and the pluggable backends registered via
+// RegisterProviderFactory) implements this interface.
 type CloudProvisioner interface {
 	// CreateInstance provisions a new cloud instance.
 	CreateInstance(ctx context.Context, req *ProvisionRequest) (*CloudInstance, error)
@@ -35,3 +94,80 @@ type CloudProvisioner interface {
 	// ProviderName returns the name of this provisioner (e.g., "k8s", "aws").
 	ProviderName() string
 }
+
+// Snapshotter is an optional capability implemented by provisioners that can
+// turn a running instance into a reusable image (an AMI, a GCE image, an
+// Azure managed image, ...). Not every backend supports this, so it is kept
+// as a separate interface and type-asserted against a registered
+// CloudProvisioner rather than added to the base interface.
+type Snapshotter interface {
+	// SnapshotInstance images the given instance and returns the resulting
+	// provider image ID.
+	SnapshotInstance(ctx context.Context, instanceID string, tags map[string]string) (string, error)
+}
+
+// MetricsAware is an optional capability a CloudProvisioner may implement to
+// receive the CloudManager's Prometheus registerer at registration time, so
+// it can expose its own API-call metrics (request counts, throttling, ...)
+// without CloudManager needing to know what each backend wants to track.
+// registerer may be nil if the manager was built without one; implementers
+// must tolerate that by skipping registration rather than panicking.
+type MetricsAware interface {
+	SetMetricsRegisterer(registerer prometheus.Registerer)
+}
+
+// StreamingExecutor is an optional capability a CloudProvisioner may
+// implement to keep an exec session or log tail open instead of buffering
+// everything into CommandResult/a string, for an interactive terminal or
+// live log view in the UI. CloudManager type-asserts for it rather than
+// adding it to CloudProvisioner, since not every backend can hold a
+// long-lived stream open the way K8s's SPDY exec and AWS's SSM session can.
+type StreamingExecutor interface {
+	// StreamCommand starts cmd on the instance and keeps the exec session
+	// open: stdin is read until closed or ctx is canceled, stdout/stderr
+	// stream live rather than buffering, and exitCh receives the process's
+	// exit code once before closing. The caller must close both returned
+	// readers once done with them.
+	StreamCommand(ctx context.Context, instanceID string, cmd []string, stdin io.Reader) (stdout io.ReadCloser, stderr io.ReadCloser, exitCh <-chan int, err error)
+
+	// TailLogs streams the instance's logs as LogLine values on the
+	// returned channel, which is closed when ctx is canceled or the
+	// underlying log stream ends (immediately, unless opts.Follow is set).
+	TailLogs(ctx context.Context, instanceID string, opts LogTailOptions) (<-chan LogLine, error)
+}
+
+// InstanceTypeProvider is an optional capability implemented by
+// provisioners that can resolve a ProvisionRequest's Requirements into a
+// concrete instance type (Karpenter-style right-sizing), rather than
+// requiring the caller to hardcode one. CloudManager.CreateInstance
+// type-asserts for it and, if req.Requirements is set, replaces
+// req.InstanceType with the selected type before handing off to
+// CreateInstance; providers without Requirements support simply ignore the
+// field and fall back to their own configured default.
+type InstanceTypeProvider interface {
+	// SelectInstanceType picks the cheapest instance type satisfying
+	// requirements, available in the provisioner's configured AZs/region.
+	SelectInstanceType(ctx context.Context, requirements InstanceRequirements) (string, error)
+}
+
+// Drainer is an optional capability implemented by provisioners that need
+// to take provider-specific action when an instance starts draining (e.g.
+// cordoning a Kubernetes node so the scheduler stops placing new work on
+// it) in addition to the generic DrainHook's bot-level checkpointing.
+// CloudManager.HandleInterruptionWarning type-asserts for it.
+type Drainer interface {
+	Drain(ctx context.Context, instanceID string) error
+}
+
+// HostReconciler is an optional capability implemented by provisioners that
+// manage their own pool of pre-allocated hosts (e.g. AWS Dedicated Hosts for
+// macOS). CloudManager.Reconcile type-asserts for it after reconciling
+// instance state, passing the set of instance IDs it just confirmed are
+// still live, so the provisioner can release any host left pointing at an
+// instance that no longer exists without CloudManager needing to know
+// anything about how that provider's hosts work.
+type HostReconciler interface {
+	// ReconcileOrphanedHosts releases hosts whose CurrentInstanceID is not in
+	// liveInstanceIDs and returns how many were released.
+	ReconcileOrphanedHosts(ctx context.Context, liveInstanceIDs map[string]bool) (int, error)
+}
@@ -0,0 +1,57 @@
+package cloud
+
+import (
+	"context"
+
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+)
+
+// QueueOp is the action RunQueue should take on a queued instance.
+type QueueOp string
+
+const (
+	// QueueOpSync re-reads an instance's live state from its provisioner.
+	QueueOpSync QueueOp = "sync"
+	// QueueOpTerminate terminates an instance whose provisioning RPC was
+	// abandoned (see Scheduler.FixStaleLocks).
+	QueueOpTerminate QueueOp = "terminate"
+	// QueueOpAccrue accrues usage cost for a running instance (see
+	// Scheduler.accrueUsageCosts).
+	QueueOpAccrue QueueOp = "accrue"
+)
+
+// QueueItem pairs an instance with the action a scheduler phase should take
+// on it.
+type QueueItem struct {
+	Instance *CloudInstance
+	Op       QueueOp
+}
+
+// Queue derives the set of instances due for a state sync this scheduling
+// pass, in place of the storage.ListCloudInstances call that used to live
+// directly in CloudInstanceMonitor's tick. Only Sync's pass reads it;
+// FixStaleLocks and accrueUsageCosts list their own instances from storage.
+type Queue struct {
+	store storage.StorageProvider
+}
+
+// NewQueue creates a Queue backed by store.
+func NewQueue(store storage.StorageProvider) *Queue {
+	return &Queue{store: store}
+}
+
+// Items returns the instances due for a state sync this pass: everything
+// storage currently considers running.
+func (q *Queue) Items(ctx context.Context) ([]QueueItem, error) {
+	runningState := InstanceStateRunning
+	running, err := q.store.ListCloudInstances(ctx, InstanceFilters{State: &runningState})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]QueueItem, 0, len(running))
+	for _, inst := range running {
+		items = append(items, QueueItem{Instance: inst, Op: QueueOpSync})
+	}
+	return items, nil
+}
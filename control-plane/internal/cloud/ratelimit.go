@@ -0,0 +1,167 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrProviderRateLimited is returned by RateLimiter.Wait once a provider has
+// racked up rateLimiterFailureThreshold consecutive timed-out waits, so
+// callers like Scheduler can back off that provider's phase instead of
+// hot-looping against an API that's clearly throttling it.
+var ErrProviderRateLimited = errors.New("provider rate limited: too many consecutive throttled calls")
+
+// rateLimiterFailureThreshold is how many consecutive Wait timeouts turn a
+// plain context deadline into ErrProviderRateLimited.
+const rateLimiterFailureThreshold = 5
+
+// RateLimiterConfig bounds how many provisioner API calls a RateLimiter
+// admits per Window (e.g. MaxCalls: 20, Window: time.Second for "20/s").
+type RateLimiterConfig struct {
+	MaxCalls int           `yaml:"max_calls" mapstructure:"max_calls"`
+	Window   time.Duration `yaml:"window" mapstructure:"window"`
+}
+
+// defaultRateLimiterConfig is used for any provider with no explicit entry
+// in CloudConfig.RateLimits.
+var defaultRateLimiterConfig = RateLimiterConfig{MaxCalls: 10, Window: time.Second}
+
+// RateLimiterMetrics holds the Prometheus collectors shared by every
+// provider's RateLimiter, labeled by provider name, so CloudManager
+// registers them once rather than per provider.
+type RateLimiterMetrics struct {
+	queueDepth  *prometheus.GaugeVec
+	waitSeconds *prometheus.HistogramVec
+}
+
+// NewRateLimiterMetrics builds the rate-limiter metrics and, if reg is
+// non-nil, registers them against it. Pass nil to get working collectors
+// that are simply never exposed to a scraper.
+func NewRateLimiterMetrics(reg prometheus.Registerer) *RateLimiterMetrics {
+	m := &RateLimiterMetrics{
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hanzo_cloud_provider_ratelimit_queue_depth",
+			Help: "Calls currently waiting for a provisioner rate-limiter token, by provider.",
+		}, []string{"provider"}),
+		waitSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "hanzo_cloud_provider_ratelimit_wait_seconds",
+			Help: "Time spent waiting for a provisioner rate-limiter token, by provider.",
+		}, []string{"provider"}),
+	}
+
+	if reg != nil {
+		reg.MustRegister(m.queueDepth, m.waitSeconds)
+	}
+
+	return m
+}
+
+// RateLimiter throttles calls to a single provisioner's API to at most
+// MaxCalls per Window, using a token bucket refilled on a ticker. It sits
+// in front of CloudManager's provisioner CreateInstance/TerminateInstance
+// calls and the Scheduler's per-instance GetInstance sync calls, so a tick
+// with hundreds of tracked instances can't hammer a provider's API past its
+// quota. CloudManager.GetInstance's own provisioner fallback does not wait
+// on it.
+type RateLimiter struct {
+	provider string
+	tokens   chan struct{}
+	stopOnce sync.Once
+	stopCh   chan struct{}
+
+	mu                sync.Mutex
+	consecutiveErrors int
+
+	metrics *RateLimiterMetrics
+}
+
+// NewRateLimiter creates a RateLimiter allowing cfg.MaxCalls calls per
+// cfg.Window for provider and starts its refill ticker. metrics may be nil,
+// in which case Wait still throttles, it just isn't observed.
+func NewRateLimiter(provider string, cfg RateLimiterConfig, metrics *RateLimiterMetrics) *RateLimiter {
+	if cfg.MaxCalls <= 0 {
+		cfg.MaxCalls = defaultRateLimiterConfig.MaxCalls
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultRateLimiterConfig.Window
+	}
+
+	rl := &RateLimiter{
+		provider: provider,
+		tokens:   make(chan struct{}, cfg.MaxCalls),
+		stopCh:   make(chan struct{}),
+		metrics:  metrics,
+	}
+	for i := 0; i < cfg.MaxCalls; i++ {
+		rl.tokens <- struct{}{}
+	}
+
+	go rl.refill(cfg.MaxCalls, cfg.Window)
+	return rl
+}
+
+func (rl *RateLimiter) refill(maxCalls int, window time.Duration) {
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rl.stopCh:
+			return
+		case <-ticker.C:
+			for i := 0; i < maxCalls; i++ {
+				select {
+				case rl.tokens <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// Stop halts the refill ticker. CloudManager keeps RateLimiters for its
+// lifetime, so this only matters for tests that construct one directly.
+func (rl *RateLimiter) Stop() {
+	rl.stopOnce.Do(func() { close(rl.stopCh) })
+}
+
+// Wait blocks until a token is available or ctx is done, recording queue
+// depth and wait time. Once rateLimiterFailureThreshold waits have failed
+// consecutively (ctx expiring while still queued), it returns
+// ErrProviderRateLimited instead of ctx.Err() so callers can distinguish
+// "this provider is throttled" from an ordinary caller-side timeout.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	if rl.metrics != nil {
+		rl.metrics.queueDepth.WithLabelValues(rl.provider).Inc()
+		defer rl.metrics.queueDepth.WithLabelValues(rl.provider).Dec()
+	}
+
+	start := time.Now()
+	defer func() {
+		if rl.metrics != nil {
+			rl.metrics.waitSeconds.WithLabelValues(rl.provider).Observe(time.Since(start).Seconds())
+		}
+	}()
+
+	select {
+	case <-rl.tokens:
+		rl.mu.Lock()
+		rl.consecutiveErrors = 0
+		rl.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		rl.mu.Lock()
+		rl.consecutiveErrors++
+		limited := rl.consecutiveErrors >= rateLimiterFailureThreshold
+		rl.mu.Unlock()
+
+		if limited {
+			return ErrProviderRateLimited
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,418 @@
+package cloud
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+)
+
+// Scheduler runs the reconciliation phases that used to live in
+// CloudInstanceMonitor.tick as one serial scan: FixStaleLocks, Sync,
+// RunQueue, and Bookkeeping. Splitting them out lets Sync and RunQueue
+// dispatch their per-instance provisioner calls to a bounded WorkerPool
+// instead of looping one instance at a time, and lets each phase run
+// independently (against a fake Clock in tests) instead of only as a
+// bundle on a wall-clock ticker. This is what lets the control plane scale
+// from dozens to thousands of tracked instances without a slow serial scan
+// blocking cost accrual and host cleanup.
+type Scheduler struct {
+	manager *CloudManager
+	store   storage.StorageProvider
+	config  CloudConfig
+	queue   *Queue
+	workers *WorkerPool
+	clock   Clock
+	metrics *SchedulerMetrics
+}
+
+// NewScheduler creates a Scheduler for manager's reconciliation phases.
+func NewScheduler(manager *CloudManager, store storage.StorageProvider, cfg CloudConfig) *Scheduler {
+	return &Scheduler{
+		manager: manager,
+		store:   store,
+		config:  cfg,
+		queue:   NewQueue(store),
+		workers: NewWorkerPool(cfg.WorkerConcurrency),
+		clock:   realClock{},
+		metrics: NewSchedulerMetrics(manager.registerer),
+	}
+}
+
+// RunOnce executes the stale-lock and sync phases in order. Bookkeeping
+// (cost accrual, idle host release) and the other reconcilers run as their
+// own independently scheduled jobs.JobScheduler entries instead of as part
+// of this pass — see CloudInstanceMonitor.jobList.
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	s.metrics.timePhase("fix_stale_locks", func() error {
+		stale := s.FixStaleLocks(ctx)
+		s.RunQueue(ctx, stale)
+		return nil
+	})
+
+	s.metrics.timePhase("sync", func() error {
+		items, err := s.queue.Items(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("scheduler: failed to build sync queue")
+			return err
+		}
+
+		report := s.Sync(ctx, items)
+		log.Debug().
+			Interface("synced", report.Synced).
+			Interface("changed", report.Changed).
+			Interface("errored", report.Errored).
+			Msg("scheduler: sync pass complete")
+		return nil
+	})
+}
+
+// FixStaleLocks finds instances stuck in "provisioning" past the configured
+// timeout — abandoned in-flight provisioner RPCs that left storage and the
+// provider out of sync — and returns them as termination work for RunQueue.
+// This promotes the old cleanupStaleProvisioning, which terminated inline;
+// splitting detection from execution lets the termination itself run
+// through the bounded worker pool alongside any other queued work.
+func (s *Scheduler) FixStaleLocks(ctx context.Context) []QueueItem {
+	provState := InstanceStateProvisioning
+	instances, err := s.store.ListCloudInstances(ctx, InstanceFilters{State: &provState})
+	if err != nil {
+		log.Error().Err(err).Msg("scheduler: failed to list provisioning instances")
+		return nil
+	}
+
+	cutoff := s.clock.Now().Add(-s.config.ProvisioningTimeout)
+	var stale []QueueItem
+	for _, inst := range instances {
+		if inst.CreatedAt.Before(cutoff) {
+			log.Warn().
+				Str("id", inst.ID).
+				Str("platform", string(inst.Platform)).
+				Time("created", inst.CreatedAt).
+				Msg("scheduler: found stale provisioning instance")
+			stale = append(stale, QueueItem{Instance: inst, Op: QueueOpTerminate})
+		}
+	}
+	return stale
+}
+
+// RunQueue dispatches queued terminations to the worker pool, bounded per
+// platform, so a burst of stale instances on one cloud can't block
+// termination of instances on another.
+func (s *Scheduler) RunQueue(ctx context.Context, items []QueueItem) {
+	s.workers.Run(items, func(item QueueItem) {
+		if item.Op != QueueOpTerminate {
+			return
+		}
+		s.terminateStale(ctx, item.Instance)
+	})
+}
+
+func (s *Scheduler) terminateStale(ctx context.Context, inst *CloudInstance) {
+	if err := s.manager.TerminateInstance(ctx, inst.ID); err != nil {
+		log.Error().Err(err).Str("id", inst.ID).Msg("scheduler: failed to terminate stale instance")
+		inst.State = InstanceStateFailed
+		inst.ErrorMessage = "provisioning timeout"
+		_ = s.store.UpdateCloudInstance(ctx, inst)
+	}
+
+	s.manager.EventBus().EmitPlatformEvent(EventInstanceFailed, inst.Platform, inst.ID, map[string]string{
+		"reason": "provisioning_timeout",
+	})
+}
+
+// Sync refreshes cloud state for each queued instance from its provisioner,
+// bounded per platform through the worker pool, and updates storage when
+// state has changed. Each instance commits its own update as soon as it
+// completes, so if ctx's deadline hits partway through a pass, everything
+// finished so far stays committed — there's nothing to roll back.
+func (s *Scheduler) Sync(ctx context.Context, items []QueueItem) *SyncReport {
+	report := newSyncReport()
+
+	s.workers.Run(items, func(item QueueItem) {
+		if item.Op != QueueOpSync {
+			return
+		}
+		s.syncInstance(ctx, item.Instance, report)
+	})
+
+	s.metrics.record("sync", report)
+	return report
+}
+
+func (s *Scheduler) syncInstance(ctx context.Context, inst *CloudInstance, report *SyncReport) {
+	report.recordSynced(inst.Platform)
+
+	prov, err := s.manager.getProvisioner(inst.Platform)
+	if err != nil {
+		report.recordErrored(inst.Platform)
+		return
+	}
+
+	if err := s.manager.rateLimiterFor(prov.ProviderName()).Wait(ctx); err != nil {
+		log.Warn().Err(err).Str("id", inst.ID).Str("platform", string(inst.Platform)).Msg("scheduler: rate limited, skipping sync this pass")
+		report.recordErrored(inst.Platform)
+		return
+	}
+
+	live, err := prov.GetInstance(ctx, inst.ID)
+	if err != nil {
+		log.Warn().Err(err).Str("id", inst.ID).Msg("scheduler: could not sync instance")
+		report.recordErrored(inst.Platform)
+		return
+	}
+
+	if live.State == inst.State {
+		return
+	}
+	report.recordChanged(inst.Platform)
+	s.metrics.recordTransition(inst.Platform, string(inst.State), string(live.State))
+
+	log.Info().
+		Str("id", inst.ID).
+		Str("old_state", string(inst.State)).
+		Str("new_state", string(live.State)).
+		Msg("scheduler: instance state changed")
+
+	inst.State = live.State
+	inst.PublicIP = live.PublicIP
+	inst.PrivateIP = live.PrivateIP
+	inst.UpdatedAt = s.clock.Now()
+
+	if live.State == InstanceStateTerminated {
+		now := s.clock.Now()
+		inst.TerminatedAt = &now
+	}
+	if live.State == InstanceStateRunning && inst.ProvisionedAt == nil {
+		now := s.clock.Now()
+		inst.ProvisionedAt = &now
+	}
+
+	_ = s.store.UpdateCloudInstance(ctx, inst)
+
+	switch live.State {
+	case InstanceStateRunning:
+		s.manager.EventBus().EmitPlatformEvent(EventInstanceRunning, inst.Platform, inst.ID, inst)
+	case InstanceStateTerminated:
+		s.manager.EventBus().EmitPlatformEvent(EventInstanceTerminated, inst.Platform, inst.ID, nil)
+	case InstanceStateFailed:
+		s.manager.EventBus().EmitPlatformEvent(EventInstanceFailed, inst.Platform, inst.ID, nil)
+	}
+}
+
+// Bookkeeping accrues usage costs for running instances and releases macOS
+// Dedicated Hosts that have sat idle past the configured threshold. Kept as
+// a convenience for callers that want both in one pass; the monitor's
+// JobScheduler instead calls AccrueUsageCosts and ReleaseIdleHosts
+// independently, on their own configured intervals.
+func (s *Scheduler) Bookkeeping(ctx context.Context) {
+	s.accrueUsageCosts(ctx)
+	s.releaseIdleHosts(ctx)
+}
+
+// AccrueUsageCosts is the exported entry point for the "accrue" job.
+func (s *Scheduler) AccrueUsageCosts(ctx context.Context) error {
+	s.accrueUsageCosts(ctx)
+	return nil
+}
+
+// ReleaseIdleHosts is the exported entry point for the "release_idle_hosts" job.
+func (s *Scheduler) ReleaseIdleHosts(ctx context.Context) error {
+	s.releaseIdleHosts(ctx)
+	return nil
+}
+
+// accrueUsageCosts calculates and reports compute costs for running
+// instances, fanned out through the worker pool the same way Sync is, since
+// this is also a per-instance storage write plus billing call.
+func (s *Scheduler) accrueUsageCosts(ctx context.Context) {
+	runningState := InstanceStateRunning
+	instances, err := s.store.ListCloudInstances(ctx, InstanceFilters{
+		State: &runningState,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("scheduler: failed to list running instances for billing")
+		return
+	}
+
+	items := make([]QueueItem, 0, len(instances))
+	for _, inst := range instances {
+		items = append(items, QueueItem{Instance: inst, Op: QueueOpAccrue})
+	}
+
+	report := newSyncReport()
+	s.workers.Run(items, func(item QueueItem) {
+		if item.Op != QueueOpAccrue {
+			return
+		}
+		s.accrueInstanceCost(ctx, item.Instance, report)
+	})
+
+	s.metrics.record("accrue", report)
+}
+
+func (s *Scheduler) accrueInstanceCost(ctx context.Context, inst *CloudInstance, report *SyncReport) {
+	report.recordSynced(inst.Platform)
+
+	s.refreshSpotRate(inst)
+
+	if inst.HourlyRateCents <= 0 {
+		return
+	}
+
+	intervalHours := s.config.Jobs.AccrueInterval.Hours()
+
+	// Accrue cost for this interval.
+	costCents := int(float64(inst.HourlyRateCents) * intervalHours)
+	if costCents < 1 {
+		costCents = 1 // minimum 1 cent per interval to avoid rounding to zero
+	}
+
+	inst.AccruedCostCents += costCents
+	if err := s.store.UpdateCloudInstance(ctx, inst); err != nil {
+		log.Error().Err(err).Str("id", inst.ID).Msg("scheduler: failed to update accrued cost")
+		report.recordErrored(inst.Platform)
+		return
+	}
+	report.recordChanged(inst.Platform)
+
+	// Report usage to billing service.
+	if err := s.manager.billing.ReportUsage(ctx, inst.ID, inst.Platform, intervalHours, inst.HourlyRateCents); err != nil {
+		log.Warn().Err(err).Str("id", inst.ID).Msg("scheduler: failed to report usage to billing")
+	}
+}
+
+// refreshSpotRate updates a spot instance's cached HourlyRateCents from the
+// provisioner's live SpotPricer, if one is tracking its instance type.
+// Falls back to silently leaving the existing rate (the static discount
+// estimate from ProviderHourlyCents) when no live price is available yet.
+func (s *Scheduler) refreshSpotRate(inst *CloudInstance) {
+	if !readSpotMetadata(inst.Metadata).Spot {
+		return
+	}
+
+	prov, err := s.manager.getProvisioner(inst.Platform)
+	if err != nil {
+		return
+	}
+
+	pricer, ok := prov.(SpotPricer)
+	if !ok {
+		return
+	}
+
+	cents, ok := pricer.SpotPrices()[inst.InstanceType]
+	if !ok || cents <= 0 {
+		return
+	}
+
+	inst.HourlyRateCents = cents
+}
+
+// ReconcileTeamQuotas compares each team's actual running-instance count
+// against CloudQuota's recorded Used* counts, warning when they've drifted.
+// This catches quota bookkeeping bugs (a failed webhook, a crashed
+// provision) independent of BudgetMonitor's burn-rate/spend tracking, which
+// only watches MonthlyBudgetCents.
+func (s *Scheduler) ReconcileTeamQuotas(ctx context.Context) error {
+	instances, err := s.store.ListCloudInstances(ctx, InstanceFilters{})
+	if err != nil {
+		return err
+	}
+
+	teamIDs := make(map[string]struct{})
+	for _, inst := range instances {
+		if inst.TeamID != "" {
+			teamIDs[inst.TeamID] = struct{}{}
+		}
+	}
+
+	for teamID := range teamIDs {
+		actual, err := s.store.CountCloudInstancesByTeam(ctx, teamID)
+		if err != nil {
+			log.Warn().Err(err).Str("team_id", teamID).Msg("scheduler: failed to count instances for quota reconcile")
+			continue
+		}
+
+		quota, err := s.manager.billing.GetTeamQuota(ctx, teamID)
+		if err != nil {
+			log.Warn().Err(err).Str("team_id", teamID).Msg("scheduler: failed to fetch team quota")
+			continue
+		}
+
+		recorded := quota.UsedLinux + quota.UsedWindows + quota.UsedMacOS
+		if actual != recorded {
+			log.Warn().
+				Str("team_id", teamID).
+				Int("actual_instances", actual).
+				Int("recorded_used", recorded).
+				Msg("scheduler: team quota usage has drifted from actual instance count")
+		}
+	}
+
+	return nil
+}
+
+// PruneTerminatedInstances removes CloudInstance rows that have been
+// terminated for longer than olderThan, keeping storage from growing
+// unbounded with instances nobody will query again.
+func (s *Scheduler) PruneTerminatedInstances(ctx context.Context, olderThan time.Duration) error {
+	cutoff := s.clock.Now().Add(-olderThan)
+
+	n, err := s.store.PruneTerminatedInstances(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Info().Int("count", n).Time("older_than", cutoff).Msg("scheduler: pruned terminated instances")
+	}
+	return nil
+}
+
+// releaseIdleHosts releases macOS Dedicated Hosts past the idle release threshold.
+func (s *Scheduler) releaseIdleHosts(ctx context.Context) {
+	if !s.config.AWS.Enabled {
+		return
+	}
+
+	hosts, err := s.store.ListDedicatedHosts(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("scheduler: failed to list dedicated hosts")
+		return
+	}
+
+	occupancy := make(map[string]int, len(hosts))
+	for _, host := range hosts {
+		occupancy[host.State]++
+	}
+	s.metrics.setDedicatedHostOccupancy(occupancy)
+
+	for _, host := range hosts {
+		if host.State != "allocated" || host.CurrentInstanceID != "" {
+			continue
+		}
+
+		if host.AllocatedAt == nil {
+			continue
+		}
+
+		idleSince := *host.AllocatedAt
+		if time.Since(idleSince) > s.config.AWS.MacOS.IdleHostRelease {
+			log.Info().
+				Str("host_id", host.HostID).
+				Time("allocated_at", idleSince).
+				Msg("releasing idle dedicated host")
+
+			now := time.Now().UTC()
+			host.State = "available"
+			host.ReleasedAt = &now
+			if err := s.store.UpdateDedicatedHost(ctx, host); err != nil {
+				log.Error().Err(err).Str("host_id", host.HostID).Msg("failed to release host")
+			} else {
+				s.manager.EventBus().EmitInstanceEvent(EventHostReleased, host.HostID, nil)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package cloud
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/hanzoai/agents/control-plane/internal/metrics"
+)
+
+// SyncReport tallies what one Scheduler pass (Sync or the accrual half of
+// Bookkeeping) did, broken down by platform, so RunOnce can log a summary
+// and SchedulerMetrics can export it. Safe for concurrent use by the
+// WorkerPool's goroutines.
+type SyncReport struct {
+	mu      sync.Mutex
+	Synced  map[Platform]int
+	Changed map[Platform]int
+	Errored map[Platform]int
+}
+
+func newSyncReport() *SyncReport {
+	return &SyncReport{
+		Synced:  make(map[Platform]int),
+		Changed: make(map[Platform]int),
+		Errored: make(map[Platform]int),
+	}
+}
+
+func (r *SyncReport) recordSynced(p Platform) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Synced[p]++
+}
+
+func (r *SyncReport) recordChanged(p Platform) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Changed[p]++
+}
+
+func (r *SyncReport) recordErrored(p Platform) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Errored[p]++
+}
+
+// SchedulerMetrics holds the Prometheus collectors recording what the
+// Scheduler's phases did: instancesProcessed by platform, phase ("sync" from
+// RunOnce's Sync, or "accrue" from the accrual pass), and result ("synced",
+// "changed", "errored"); phaseDuration and phaseErrors by RunOnce phase
+// ("fix_stale_locks" or "sync") alone.
+type SchedulerMetrics struct {
+	instancesProcessed *prometheus.CounterVec
+	transitionsTotal   *prometheus.CounterVec
+	phaseDuration      *prometheus.HistogramVec
+	phaseErrors        *prometheus.CounterVec
+	dedicatedHosts     *prometheus.GaugeVec
+}
+
+// NewSchedulerMetrics builds the scheduler metrics and, if reg is non-nil,
+// registers them against it. Pass nil to get working counters that are
+// simply never exposed to a scraper.
+func NewSchedulerMetrics(reg prometheus.Registerer) *SchedulerMetrics {
+	r := metrics.New(reg)
+	return &SchedulerMetrics{
+		instancesProcessed: r.CounterVec(prometheus.CounterOpts{
+			Name: "hanzo_cloud_scheduler_instances_total",
+			Help: "Instances processed by the scheduler's worker-pool phases, by platform, phase, and result.",
+		}, []string{"platform", "phase", "result"}),
+		transitionsTotal: r.CounterVec(prometheus.CounterOpts{
+			Name: "hanzo_cloud_instance_transitions_total",
+			Help: "Cloud instance state transitions observed during Sync, by platform, previous state, and new state.",
+		}, []string{"platform", "from", "to"}),
+		phaseDuration: r.HistogramVec(prometheus.HistogramOpts{
+			Name: "hanzo_cloud_monitor_phase_duration_seconds",
+			Help: "Duration of each CloudInstanceMonitor/Scheduler RunOnce phase.",
+		}, []string{"phase"}),
+		phaseErrors: r.CounterVec(prometheus.CounterOpts{
+			Name: "hanzo_cloud_monitor_phase_errors_total",
+			Help: "Errors encountered while running a CloudInstanceMonitor/Scheduler RunOnce phase.",
+		}, []string{"phase"}),
+		dedicatedHosts: r.GaugeVec(prometheus.GaugeOpts{
+			Name: "hanzo_cloud_dedicated_hosts",
+			Help: "Current number of macOS Dedicated Hosts tracked, by state.",
+		}, []string{"state"}),
+	}
+}
+
+func (m *SchedulerMetrics) record(phase string, report *SyncReport) {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	for platform, n := range report.Synced {
+		m.instancesProcessed.WithLabelValues(string(platform), phase, "synced").Add(float64(n))
+	}
+	for platform, n := range report.Changed {
+		m.instancesProcessed.WithLabelValues(string(platform), phase, "changed").Add(float64(n))
+	}
+	for platform, n := range report.Errored {
+		m.instancesProcessed.WithLabelValues(string(platform), phase, "errored").Add(float64(n))
+	}
+}
+
+// recordTransition records a single instance's observed state change.
+func (m *SchedulerMetrics) recordTransition(platform Platform, from, to string) {
+	m.transitionsTotal.WithLabelValues(string(platform), from, to).Inc()
+}
+
+// timePhase times fn under the given phase name, recording its duration and,
+// if it reports an error, incrementing phaseErrors.
+func (m *SchedulerMetrics) timePhase(phase string, fn func() error) {
+	start := time.Now()
+	err := fn()
+	m.phaseDuration.WithLabelValues(phase).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.phaseErrors.WithLabelValues(phase).Inc()
+	}
+}
+
+// setDedicatedHostOccupancy records the current split of dedicated hosts by
+// state, replacing whatever counts were set on the previous pass.
+func (m *SchedulerMetrics) setDedicatedHostOccupancy(counts map[string]int) {
+	m.dedicatedHosts.Reset()
+	for state, n := range counts {
+		m.dedicatedHosts.WithLabelValues(state).Set(float64(n))
+	}
+}
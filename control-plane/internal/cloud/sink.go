@@ -0,0 +1,142 @@
+package cloud
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Sink delivers CloudEvents to an external system - a webhook, a message
+// broker, an MQTT topic. Implementations live in internal/cloud/sinks, each
+// adapting a transport to this interface so EventBus can drive them all
+// identically.
+type Sink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	// Send delivers event, returning an error if delivery failed. A
+	// returned error triggers sinkWorker's retry-with-backoff; Send should
+	// not retry internally.
+	Send(ctx context.Context, event CloudEvent) error
+}
+
+// sinkConfig controls a sinkWorker's queue depth, retry backoff, and
+// per-send timeout.
+type sinkConfig struct {
+	queueSize      int
+	sendTimeout    time.Duration
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+var defaultSinkConfig = sinkConfig{
+	queueSize:      256,
+	sendTimeout:    10 * time.Second,
+	maxRetries:     5,
+	initialBackoff: 500 * time.Millisecond,
+	maxBackoff:     30 * time.Second,
+}
+
+// sinkWorker drives one Sink off a bounded queue, retrying a failed Send
+// with exponential backoff before giving up and dead-lettering the event.
+// Bounding the queue means a stuck or slow sink sheds load instead of
+// blocking EventBus.Publish or growing without limit.
+type sinkWorker struct {
+	name string
+	sink Sink
+	cfg  sinkConfig
+
+	queue      chan CloudEvent
+	deadLetter chan CloudEvent
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newSinkWorker(name string, s Sink, cfg sinkConfig) *sinkWorker {
+	w := &sinkWorker{
+		name:       name,
+		sink:       s,
+		cfg:        cfg,
+		queue:      make(chan CloudEvent, cfg.queueSize),
+		deadLetter: make(chan CloudEvent, cfg.queueSize),
+		stopCh:     make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.loop()
+	return w
+}
+
+// enqueue drops the event and logs a warning if the queue is full, rather
+// than blocking the publisher.
+func (w *sinkWorker) enqueue(event CloudEvent) {
+	select {
+	case w.queue <- event:
+	default:
+		log.Warn().Str("sink", w.name).Str("event_id", event.ID).Msg("cloud: sink queue full, dropping event")
+	}
+}
+
+// DeadLettered returns events that exhausted every retry without a
+// successful Send. Callers should drain this periodically; like queue, when
+// it is full the incoming event (here, the new dead letter) is dropped.
+func (w *sinkWorker) DeadLettered() <-chan CloudEvent {
+	return w.deadLetter
+}
+
+func (w *sinkWorker) stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+	w.wg.Wait()
+}
+
+func (w *sinkWorker) loop() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case event := <-w.queue:
+			w.send(event)
+		}
+	}
+}
+
+func (w *sinkWorker) send(event CloudEvent) {
+	backoff := w.cfg.initialBackoff
+	for attempt := 0; attempt <= w.cfg.maxRetries; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), w.cfg.sendTimeout)
+		err := w.sink.Send(ctx, event)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if attempt == w.cfg.maxRetries {
+			log.Error().Err(err).Str("sink", w.name).Str("event_id", event.ID).
+				Int("attempts", attempt+1).Msg("cloud: sink exhausted retries, dead-lettering event")
+			select {
+			case w.deadLetter <- event:
+			default:
+				log.Warn().Str("sink", w.name).Msg("cloud: dead letter queue full, dropping event")
+			}
+			return
+		}
+
+		log.Warn().Err(err).Str("sink", w.name).Str("event_id", event.ID).
+			Int("attempt", attempt+1).Dur("backoff", backoff).Msg("cloud: sink send failed, retrying")
+
+		select {
+		case <-w.stopCh:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > w.cfg.maxBackoff {
+			backoff = w.cfg.maxBackoff
+		}
+	}
+}
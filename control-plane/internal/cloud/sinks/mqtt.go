@@ -0,0 +1,48 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// MQTTPublisher is the minimal capability MQTTSink needs from an MQTT
+// client, satisfied by a thin wrapper around paho or any other MQTT
+// library - kept this small so the sink carries no direct client
+// dependency.
+type MQTTPublisher interface {
+	// Publish sends payload to topic at the given QoS level.
+	Publish(ctx context.Context, topic string, qos byte, payload []byte) error
+}
+
+// MQTTSink delivers CloudEvents to an MQTT topic via an injected
+// MQTTPublisher, JSON-encoding the full event as the payload.
+type MQTTSink struct {
+	name      string
+	topic     string
+	qos       byte
+	publisher MQTTPublisher
+}
+
+// NewMQTTSink creates an MQTTSink that publishes to topic at qos through
+// publisher.
+func NewMQTTSink(name, topic string, qos byte, publisher MQTTPublisher) *MQTTSink {
+	return &MQTTSink{name: name, topic: topic, qos: qos, publisher: publisher}
+}
+
+// Name implements cloud.Sink.
+func (s *MQTTSink) Name() string { return s.name }
+
+// Send implements cloud.Sink.
+func (s *MQTTSink) Send(ctx context.Context, event cloud.CloudEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("mqtt sink %s: marshal event: %w", s.name, err)
+	}
+	if err := s.publisher.Publish(ctx, s.topic, s.qos, payload); err != nil {
+		return fmt.Errorf("mqtt sink %s: %w", s.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// Publisher is the minimal capability StreamingSink needs from a message
+// broker client - satisfied by a thin wrapper around a NATS, Kafka, or
+// similar producer. Keeping it this small means StreamingSink has no
+// direct dependency on any one broker's client library; callers supply
+// whichever adapter fits their deployment.
+type Publisher interface {
+	// Publish sends payload to subject (NATS subject / Kafka topic / etc).
+	Publish(ctx context.Context, subject string, payload []byte) error
+}
+
+// StreamingSink delivers CloudEvents to a message broker topic via an
+// injected Publisher, JSON-encoding the full event as the payload (the
+// CloudEvents structured content mode, as opposed to WebhookSink's binary
+// mode).
+type StreamingSink struct {
+	name      string
+	subject   string
+	publisher Publisher
+}
+
+// NewStreamingSink creates a StreamingSink that publishes to subject
+// through publisher.
+func NewStreamingSink(name, subject string, publisher Publisher) *StreamingSink {
+	return &StreamingSink{name: name, subject: subject, publisher: publisher}
+}
+
+// Name implements cloud.Sink.
+func (s *StreamingSink) Name() string { return s.name }
+
+// Send implements cloud.Sink.
+func (s *StreamingSink) Send(ctx context.Context, event cloud.CloudEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("streaming sink %s: marshal event: %w", s.name, err)
+	}
+	if err := s.publisher.Publish(ctx, s.subject, payload); err != nil {
+		return fmt.Errorf("streaming sink %s: %w", s.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Package sinks provides cloud.Sink implementations that deliver
+// CloudEvents to external transports: plain HTTP webhooks, message
+// brokers, and MQTT.
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// WebhookSink delivers CloudEvents to an HTTP endpoint using the
+// CloudEvents binary content mode: the event's required and optional
+// attributes go in ce-* headers and Data is sent as-is in the body. See
+// https://github.com/cloudevents/spec/blob/main/cloudevents/bindings/http-protocol-binding.md.
+type WebhookSink struct {
+	name     string
+	endpoint string
+	client   *http.Client
+}
+
+// NewWebhookSink creates a WebhookSink posting to endpoint.
+func NewWebhookSink(name, endpoint string) *WebhookSink {
+	return &WebhookSink{
+		name:     name,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements cloud.Sink.
+func (w *WebhookSink) Name() string { return w.name }
+
+// Send implements cloud.Sink.
+func (w *WebhookSink) Send(ctx context.Context, event cloud.CloudEvent) error {
+	body := event.Data
+	if body == nil {
+		body = []byte("{}")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook sink %s: build request: %w", w.name, err)
+	}
+
+	contentType := event.DataContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("ce-specversion", event.SpecVersion)
+	req.Header.Set("ce-id", event.ID)
+	req.Header.Set("ce-source", event.Source)
+	req.Header.Set("ce-type", event.Type)
+	req.Header.Set("ce-time", event.Time.Format(time.RFC3339Nano))
+	if event.Subject != "" {
+		req.Header.Set("ce-subject", event.Subject)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook sink %s: %w", w.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook sink %s: endpoint returned %d", w.name, resp.StatusCode)
+	}
+	return nil
+}
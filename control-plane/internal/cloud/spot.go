@@ -0,0 +1,157 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DrainHook is invoked when a spot/preemptible instance receives an
+// interruption warning, giving callers a chance to checkpoint in-flight
+// work before the instance is reclaimed.
+type DrainHook func(ctx context.Context, inst *CloudInstance) error
+
+// SpotMetadata is persisted in CloudInstance.Metadata for spot/preemptible
+// instances so that the discounted billing rate and fallback behavior
+// survive a restart without depending on the original ProvisionRequest.
+type SpotMetadata struct {
+	Spot               bool `json:"spot"`
+	MaxPriceCents      int  `json:"max_spot_price_cents,omitempty"`
+	FallbackToOnDemand bool `json:"fallback_to_on_demand,omitempty"`
+}
+
+// withSpotMetadata merges spot bid metadata into an instance's existing
+// Metadata blob, preserving any other fields already present.
+func withSpotMetadata(existing json.RawMessage, sm SpotMetadata) json.RawMessage {
+	fields := map[string]interface{}{}
+	if len(existing) > 0 {
+		_ = json.Unmarshal(existing, &fields)
+	}
+	fields["spot"] = sm.Spot
+	if sm.MaxPriceCents > 0 {
+		fields["max_spot_price_cents"] = sm.MaxPriceCents
+	}
+	if sm.FallbackToOnDemand {
+		fields["fallback_to_on_demand"] = true
+	}
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return existing
+	}
+	return out
+}
+
+// readSpotMetadata extracts spot bid metadata from an instance's Metadata
+// blob, returning a zero-value SpotMetadata when absent or unparsable.
+func readSpotMetadata(raw json.RawMessage) SpotMetadata {
+	var sm SpotMetadata
+	if len(raw) == 0 {
+		return sm
+	}
+	_ = json.Unmarshal(raw, &sm)
+	return sm
+}
+
+// withoutSpotMetadata strips spot bid fields from a Metadata blob, used when
+// carrying tags/metadata over to an on-demand replacement instance.
+func withoutSpotMetadata(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return raw
+	}
+	delete(fields, "spot")
+	delete(fields, "max_spot_price_cents")
+	delete(fields, "fallback_to_on_demand")
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
+// SetDrainHook installs a callback invoked on spot interruption warnings so
+// callers can checkpoint work before the instance is reclaimed. Defaults to
+// a no-op when never called.
+func (m *CloudManager) SetDrainHook(hook DrainHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drainHook = hook
+}
+
+// HandleInterruptionWarning transitions a spot instance to InstanceStateDraining,
+// invokes the registered DrainHook, and — if the instance was provisioned
+// with FallbackToOnDemand — provisions an on-demand replacement carrying
+// over its tags and metadata before terminating the interrupted instance.
+// Providers report interruption warnings here however they observe them
+// (e.g. AWS's spot.interruption poller watching instance metadata via SSM).
+func (m *CloudManager) HandleInterruptionWarning(ctx context.Context, instanceID string) error {
+	inst, prov, err := m.resolveInstance(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	log.Warn().Str("instance_id", instanceID).Msg("spot interruption warning received")
+
+	inst.State = InstanceStateDraining
+	inst.UpdatedAt = time.Now().UTC()
+	if m.store != nil {
+		_ = m.store.UpdateCloudInstance(ctx, inst)
+	}
+	m.eventBus.EmitPlatformEvent(EventInstanceInterruptionWarning, inst.Platform, instanceID, nil)
+
+	if drainer, ok := prov.(Drainer); ok {
+		if err := drainer.Drain(ctx, instanceID); err != nil {
+			log.Error().Err(err).Str("instance_id", instanceID).Msg("provider drain failed")
+		}
+	}
+
+	m.mu.RLock()
+	hook := m.drainHook
+	m.mu.RUnlock()
+	if hook != nil {
+		if err := hook(ctx, inst); err != nil {
+			log.Error().Err(err).Str("instance_id", instanceID).Msg("drain hook failed")
+		}
+	}
+
+	if readSpotMetadata(inst.Metadata).FallbackToOnDemand {
+		if err := m.replaceWithOnDemand(ctx, inst); err != nil {
+			log.Error().Err(err).Str("instance_id", instanceID).Msg("failed to provision on-demand replacement")
+		}
+	}
+
+	return nil
+}
+
+// replaceWithOnDemand provisions an on-demand instance carrying over the
+// interrupted spot instance's tags and metadata, then terminates the
+// original once the replacement has been requested.
+func (m *CloudManager) replaceWithOnDemand(ctx context.Context, inst *CloudInstance) error {
+	replacement, err := m.CreateInstance(ctx, &ProvisionRequest{
+		Platform:     inst.Platform,
+		BotPackage:   inst.BotPackage,
+		BotVersion:   inst.BotVersion,
+		InstanceType: inst.InstanceType,
+		TeamID:       inst.TeamID,
+		Tags:         inst.Tags,
+		Metadata:     withoutSpotMetadata(inst.Metadata),
+	})
+	if err != nil {
+		return fmt.Errorf("provisioning on-demand replacement: %w", err)
+	}
+
+	log.Info().
+		Str("original_instance_id", inst.ID).
+		Str("replacement_instance_id", replacement.ID).
+		Msg("provisioned on-demand replacement for interrupted spot instance")
+
+	return m.TerminateInstance(ctx, inst.ID)
+}
@@ -0,0 +1,326 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+)
+
+// liveTask is a Task plus the runtime plumbing GetTaskHandler's SSE stream
+// and StartProvision/StartTerminate need: a done channel closed once the
+// task reaches TaskStateDone/TaskStateFailed, a stop channel so a caller can
+// give up waiting without leaking the EventBus correlation, and optional
+// callbacks fired on each transition. The embedded Task is what gets
+// persisted and serialized; everything else here is in-process only.
+type liveTask struct {
+	mu   sync.Mutex
+	task *Task
+
+	done     chan struct{}
+	doneOnce sync.Once
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	onStarted   func(*Task)
+	onStatus    func(*Task, TaskStatusEntry)
+	onCompleted func(*Task)
+}
+
+// TaskManagerOpts configures the Started/Status/Completed callbacks a
+// TaskManager fires for every task it runs. Any left nil are simply not
+// called. Started fires once, right after the task is created; Status fires
+// on every status-history append; Completed fires once, when the task
+// reaches TaskStateDone or TaskStateFailed.
+type TaskManagerOpts struct {
+	Started   func(*Task)
+	Status    func(*Task, TaskStatusEntry)
+	Completed func(*Task)
+}
+
+// TaskManager wraps CloudManager's provisioning operations with an async
+// task: the synchronous provisioner RPC (CreateInstance/TerminateInstance)
+// runs immediately, but the task itself doesn't complete until the
+// instance's state converges, which TaskManager learns about by subscribing
+// to CloudManager's EventBus rather than polling storage itself.
+type TaskManager struct {
+	manager *CloudManager
+	store   storage.StorageProvider
+	opts    TaskManagerOpts
+
+	mu    sync.RWMutex
+	tasks map[string]*liveTask
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewTaskManager creates a TaskManager and starts its EventBus subscription.
+// Call Stop when the server shuts down to release the subscription.
+func NewTaskManager(manager *CloudManager, store storage.StorageProvider, opts TaskManagerOpts) *TaskManager {
+	tm := &TaskManager{
+		manager: manager,
+		store:   store,
+		opts:    opts,
+		tasks:   make(map[string]*liveTask),
+		stopCh:  make(chan struct{}),
+	}
+	go tm.watchEvents()
+	return tm
+}
+
+// Stop unsubscribes from the EventBus, ending watchEvents.
+func (tm *TaskManager) Stop() {
+	tm.stopOnce.Do(func() {
+		close(tm.stopCh)
+	})
+}
+
+func (tm *TaskManager) watchEvents() {
+	subID, events := tm.manager.EventBus().Subscribe()
+	defer tm.manager.EventBus().Unsubscribe(subID)
+
+	for {
+		select {
+		case <-tm.stopCh:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			switch event.Type {
+			case EventInstanceRunning, EventInstanceTerminated:
+				tm.completeByInstance(event.InstanceID, TaskStateDone, "")
+			case EventInstanceFailed:
+				tm.completeByInstance(event.InstanceID, TaskStateFailed, "instance entered failed state")
+			}
+		}
+	}
+}
+
+func (tm *TaskManager) completeByInstance(instanceID string, state TaskState, errMsg string) {
+	if instanceID == "" {
+		return
+	}
+
+	tm.mu.RLock()
+	var lt *liveTask
+	for _, candidate := range tm.tasks {
+		candidate.mu.Lock()
+		match := candidate.task.InstanceID == instanceID && candidate.task.State == TaskStateRunning
+		candidate.mu.Unlock()
+		if match {
+			lt = candidate
+			break
+		}
+	}
+	tm.mu.RUnlock()
+
+	if lt == nil {
+		return
+	}
+
+	lt.appendStatus(fmt.Sprintf("instance %s reached a terminal state", instanceID))
+	lt.complete(state, errMsg)
+	tm.persist(lt.task)
+}
+
+// StartProvision runs CloudManager.CreateInstance and tracks the result as a
+// new Task: synchronous admission/billing/launch happens before this
+// returns, but the task stays TaskStateRunning until the monitor's Sync
+// phase reports the instance running or failed.
+func (tm *TaskManager) StartProvision(ctx context.Context, req *ProvisionRequest) (*Task, error) {
+	lt := tm.newTask(req.TeamID, "provision")
+	if err := tm.store.CreateTask(ctx, lt.task); err != nil {
+		log.Error().Err(err).Str("id", lt.task.ID).Msg("task manager: failed to persist new task")
+	}
+
+	inst, err := tm.manager.CreateInstance(ctx, req)
+	if err != nil {
+		lt.appendStatus("provisioning request failed: " + err.Error())
+		lt.complete(TaskStateFailed, err.Error())
+		tm.persist(lt.task)
+		return lt.task, err
+	}
+
+	lt.mu.Lock()
+	lt.task.InstanceID = inst.ID
+	lt.mu.Unlock()
+	lt.appendStatus(fmt.Sprintf("instance %s requested, waiting for running state", inst.ID))
+
+	if inst.State == InstanceStateRunning {
+		lt.appendStatus(fmt.Sprintf("instance %s already running", inst.ID))
+		lt.complete(TaskStateDone, "")
+	}
+	tm.persist(lt.task)
+
+	return lt.task, nil
+}
+
+// StartTerminate runs CloudManager.TerminateInstance and tracks the result
+// as a new Task, the same way StartProvision does for creation.
+func (tm *TaskManager) StartTerminate(ctx context.Context, owner, instanceID string) (*Task, error) {
+	lt := tm.newTask(owner, "terminate")
+	lt.mu.Lock()
+	lt.task.InstanceID = instanceID
+	lt.mu.Unlock()
+	if err := tm.store.CreateTask(ctx, lt.task); err != nil {
+		log.Error().Err(err).Str("id", lt.task.ID).Msg("task manager: failed to persist new task")
+	}
+
+	if err := tm.manager.TerminateInstance(ctx, instanceID); err != nil {
+		lt.appendStatus("termination request failed: " + err.Error())
+		lt.complete(TaskStateFailed, err.Error())
+		tm.persist(lt.task)
+		return lt.task, err
+	}
+
+	lt.appendStatus(fmt.Sprintf("termination of %s requested, waiting for confirmation", instanceID))
+	tm.persist(lt.task)
+
+	return lt.task, nil
+}
+
+// Get returns a snapshot of the task with the given ID.
+func (tm *TaskManager) Get(id string) (*Task, error) {
+	tm.mu.RLock()
+	lt, ok := tm.tasks[id]
+	tm.mu.RUnlock()
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	snapshot := *lt.task
+	return &snapshot, nil
+}
+
+// Wait blocks until the task completes, ctx is done, or the task's own stop
+// channel fires, returning the task's final snapshot.
+func (tm *TaskManager) Wait(ctx context.Context, id string) (*Task, error) {
+	tm.mu.RLock()
+	lt, ok := tm.tasks[id]
+	tm.mu.RUnlock()
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	select {
+	case <-lt.done:
+	case <-lt.stopCh:
+	case <-ctx.Done():
+	}
+
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	snapshot := *lt.task
+	return &snapshot, nil
+}
+
+// StatusUpdates returns a channel of status entries appended to the task
+// from this point on, closed once the task completes.
+// GetTaskHandler's SSE stream reads from this.
+func (tm *TaskManager) StatusUpdates(id string) (<-chan TaskStatusEntry, error) {
+	tm.mu.RLock()
+	lt, ok := tm.tasks[id]
+	tm.mu.RUnlock()
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	ch := make(chan TaskStatusEntry, 16)
+	lt.mu.Lock()
+	prevOnStatus := lt.onStatus
+	lt.onStatus = func(t *Task, entry TaskStatusEntry) {
+		if prevOnStatus != nil {
+			prevOnStatus(t, entry)
+		}
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+	lt.mu.Unlock()
+
+	go func() {
+		<-lt.done
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+func (tm *TaskManager) newTask(owner, op string) *liveTask {
+	now := time.Now().UTC()
+	lt := &liveTask{
+		task: &Task{
+			ID:        uuid.New().String(),
+			Owner:     owner,
+			Op:        op,
+			State:     TaskStateRunning,
+			Status:    []TaskStatusEntry{},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		done:        make(chan struct{}),
+		stopCh:      make(chan struct{}),
+		onStarted:   tm.opts.Started,
+		onStatus:    tm.opts.Status,
+		onCompleted: tm.opts.Completed,
+	}
+
+	tm.mu.Lock()
+	tm.tasks[lt.task.ID] = lt
+	tm.mu.Unlock()
+
+	if lt.onStarted != nil {
+		lt.onStarted(lt.task)
+	}
+
+	return lt
+}
+
+func (lt *liveTask) appendStatus(message string) {
+	entry := TaskStatusEntry{Time: time.Now().UTC(), Message: message}
+
+	lt.mu.Lock()
+	lt.task.Status = append(lt.task.Status, entry)
+	lt.task.UpdatedAt = entry.Time
+	onStatus := lt.onStatus
+	lt.mu.Unlock()
+
+	if onStatus != nil {
+		onStatus(lt.task, entry)
+	}
+}
+
+func (lt *liveTask) complete(state TaskState, errMsg string) {
+	lt.mu.Lock()
+	if lt.task.State != TaskStateRunning && lt.task.State != TaskStatePending {
+		lt.mu.Unlock()
+		return
+	}
+	lt.task.State = state
+	lt.task.Error = errMsg
+	lt.task.UpdatedAt = time.Now().UTC()
+	onCompleted := lt.onCompleted
+	lt.mu.Unlock()
+
+	lt.doneOnce.Do(func() { close(lt.done) })
+
+	if onCompleted != nil {
+		onCompleted(lt.task)
+	}
+}
+
+func (tm *TaskManager) persist(task *Task) {
+	if err := tm.store.UpdateTask(context.Background(), task); err != nil {
+		log.Error().Err(err).Str("id", task.ID).Msg("task manager: failed to persist task")
+	}
+}
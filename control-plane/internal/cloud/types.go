@@ -6,17 +6,26 @@ import (
 
 // Re-export public types for internal use.
 type (
-	CloudInstance      = types.CloudInstance
-	ProvisionRequest   = types.ProvisionRequest
-	ConnectionInfo     = types.ConnectionInfo
-	CommandResult      = types.CommandResult
-	DedicatedHost      = types.DedicatedHost
-	InstanceFilters    = types.InstanceFilters
-	CloudEvent         = types.CloudEvent
-	CloudSummary       = types.CloudSummary
-	Platform           = types.Platform
-	InstanceState      = types.InstanceState
-	ConnectionProtocol = types.ConnectionProtocol
+	CloudInstance        = types.CloudInstance
+	ProvisionRequest     = types.ProvisionRequest
+	ConnectionInfo       = types.ConnectionInfo
+	Credentials          = types.Credentials
+	CommandResult        = types.CommandResult
+	LogTailOptions       = types.LogTailOptions
+	LogLine              = types.LogLine
+	InstanceRequirements = types.InstanceRequirements
+	DedicatedHost        = types.DedicatedHost
+	InstanceFilters      = types.InstanceFilters
+	CloudEvent           = types.CloudEvent
+	CloudSummary         = types.CloudSummary
+	Platform             = types.Platform
+	InstanceState        = types.InstanceState
+	ConnectionProtocol   = types.ConnectionProtocol
+	ConnectionMode       = types.ConnectionMode
+	SSHKeyPair           = types.SSHKeyPair
+	Task                 = types.Task
+	TaskState            = types.TaskState
+	TaskStatusEntry      = types.TaskStatusEntry
 )
 
 // Re-export constants.
@@ -31,10 +40,20 @@ const (
 	InstanceStateStopped      = types.InstanceStateStopped
 	InstanceStateTerminated   = types.InstanceStateTerminated
 	InstanceStateFailed       = types.InstanceStateFailed
+	InstanceStateDraining     = types.InstanceStateDraining
 
 	ConnectionProtocolRDP  = types.ConnectionProtocolRDP
 	ConnectionProtocolVNC  = types.ConnectionProtocolVNC
 	ConnectionProtocolSSH  = types.ConnectionProtocolSSH
 	ConnectionProtocolExec = types.ConnectionProtocolExec
 	ConnectionProtocolSSM  = types.ConnectionProtocolSSM
+
+	ConnectionModeSSHDirect = types.ConnectionModeSSHDirect
+	ConnectionModeSSHViaSSM = types.ConnectionModeSSHViaSSM
+	ConnectionModeVNC       = types.ConnectionModeVNC
+
+	TaskStatePending = types.TaskStatePending
+	TaskStateRunning = types.TaskStateRunning
+	TaskStateDone    = types.TaskStateDone
+	TaskStateFailed  = types.TaskStateFailed
 )
@@ -0,0 +1,123 @@
+package cloud
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+)
+
+// windowsPasswordPollInterval is how often WindowsPasswordPoller checks
+// whether a Windows instance's RDP password has finished generating.
+const windowsPasswordPollInterval = 30 * time.Second
+
+// WindowsPasswordPoller watches newly-launched Windows instances until
+// GetConnectionInfo reports their RDP password is ready (GetPasswordData
+// stops returning empty, usually 4-15 minutes after launch), then persists
+// CloudInstance.WindowsPasswordReady and publishes EventWindowsPasswordReady
+// so the UI can enable its "Connect via RDP" button without polling.
+type WindowsPasswordPoller struct {
+	provisioners func(Platform) (CloudProvisioner, bool)
+	store        storage.StorageProvider
+	eventBus     *EventBus
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewWindowsPasswordPoller creates a poller. provisioners looks up the
+// CloudProvisioner for a platform, mirroring CloudManager's internal
+// dispatch table.
+func NewWindowsPasswordPoller(provisioners func(Platform) (CloudProvisioner, bool), store storage.StorageProvider, eventBus *EventBus) *WindowsPasswordPoller {
+	return &WindowsPasswordPoller{
+		provisioners: provisioners,
+		store:        store,
+		eventBus:     eventBus,
+		cancels:      make(map[string]context.CancelFunc),
+	}
+}
+
+// Watch starts polling instanceID until its password is ready or
+// StopWatching/context cancellation ends it early. A no-op if instanceID is
+// already being watched.
+func (w *WindowsPasswordPoller) Watch(instanceID string) {
+	w.mu.Lock()
+	if _, ok := w.cancels[instanceID]; ok {
+		w.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancels[instanceID] = cancel
+	w.mu.Unlock()
+
+	go w.run(ctx, instanceID)
+}
+
+// StopWatching cancels polling for instanceID, e.g. once it terminates
+// without ever reaching the running state.
+func (w *WindowsPasswordPoller) StopWatching(instanceID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if cancel, ok := w.cancels[instanceID]; ok {
+		cancel()
+		delete(w.cancels, instanceID)
+	}
+}
+
+func (w *WindowsPasswordPoller) run(ctx context.Context, instanceID string) {
+	defer w.StopWatching(instanceID)
+
+	ticker := time.NewTicker(windowsPasswordPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ready, err := w.checkOnce(ctx, instanceID)
+			if err != nil {
+				log.Warn().Err(err).Str("instance_id", instanceID).Msg("windows password poller: check failed")
+				continue
+			}
+			if ready {
+				return
+			}
+		}
+	}
+}
+
+func (w *WindowsPasswordPoller) checkOnce(ctx context.Context, instanceID string) (bool, error) {
+	inst, err := w.store.GetCloudInstance(ctx, instanceID)
+	if err != nil {
+		return false, err
+	}
+	if inst == nil || inst.WindowsPasswordReady {
+		return true, nil
+	}
+
+	provisioner, ok := w.provisioners(inst.Platform)
+	if !ok {
+		return true, nil
+	}
+
+	conn, err := provisioner.GetConnectionInfo(ctx, instanceID)
+	if err != nil {
+		return false, err
+	}
+	if conn == nil || conn.Extra["password_ready"] != "true" {
+		return false, nil
+	}
+
+	inst.WindowsPasswordReady = true
+	if err := w.store.UpdateCloudInstance(ctx, inst); err != nil {
+		return false, err
+	}
+
+	w.eventBus.EmitPlatformEvent(EventWindowsPasswordReady, inst.Platform, instanceID, nil)
+	return true, nil
+}
@@ -0,0 +1,57 @@
+package cloud
+
+import "sync"
+
+// defaultWorkerConcurrency is how many provisioner operations WorkerPool
+// runs at once per platform when CloudConfig.WorkerConcurrency is unset.
+const defaultWorkerConcurrency = 16
+
+// WorkerPool bounds how many provisioner operations run concurrently for a
+// single platform, so a burst of instances on one cloud can't starve
+// workers needed by another. One pool is shared across Scheduler's phases.
+type WorkerPool struct {
+	mu    sync.Mutex
+	sems  map[Platform]chan struct{}
+	limit int
+}
+
+// NewWorkerPool creates a WorkerPool allowing up to limit concurrent
+// operations per platform. limit <= 0 falls back to defaultWorkerConcurrency.
+func NewWorkerPool(limit int) *WorkerPool {
+	if limit <= 0 {
+		limit = defaultWorkerConcurrency
+	}
+	return &WorkerPool{
+		sems:  make(map[Platform]chan struct{}),
+		limit: limit,
+	}
+}
+
+func (wp *WorkerPool) semFor(platform Platform) chan struct{} {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	sem, ok := wp.sems[platform]
+	if !ok {
+		sem = make(chan struct{}, wp.limit)
+		wp.sems[platform] = sem
+	}
+	return sem
+}
+
+// Run executes fn for every item, bounding concurrency per item's platform
+// to the pool's configured limit, and blocks until all items have run.
+func (wp *WorkerPool) Run(items []QueueItem, fn func(QueueItem)) {
+	var wg sync.WaitGroup
+	for _, item := range items {
+		sem := wp.semFor(item.Instance.Platform)
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(it QueueItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(it)
+		}(item)
+	}
+	wg.Wait()
+}
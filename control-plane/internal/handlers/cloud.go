@@ -1,28 +1,45 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"path"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 
 	"github.com/hanzoai/agents/control-plane/internal/cloud"
+	"github.com/hanzoai/agents/control-plane/internal/cloud/broker"
 	"github.com/hanzoai/agents/control-plane/pkg/types"
 )
 
 // CloudHandlers holds dependencies for cloud API handlers.
 type CloudHandlers struct {
 	manager *cloud.CloudManager
+	broker  *broker.Gateway
+	exec    *broker.ExecGateway
+	tasks   *cloud.TaskManager
 }
 
 // NewCloudHandlers creates a new CloudHandlers instance.
-func NewCloudHandlers(manager *cloud.CloudManager) *CloudHandlers {
-	return &CloudHandlers{manager: manager}
+func NewCloudHandlers(manager *cloud.CloudManager, tasks *cloud.TaskManager) *CloudHandlers {
+	return &CloudHandlers{
+		manager: manager,
+		broker:  broker.NewGateway(manager, manager.Config().Broker),
+		exec:    broker.NewExecGateway(manager),
+		tasks:   tasks,
+	}
 }
 
-// CreateInstanceHandler handles POST /api/v1/cloud/instances
+// CreateInstanceHandler handles POST /api/v1/cloud/instances. Provisioning
+// can take minutes (an EC2 mac instance, or waiting on a Dedicated Host), so
+// this starts a Task and returns its ID immediately instead of blocking
+// until the instance is running; poll or stream GET /api/v1/tasks/:id for
+// progress.
 func (h *CloudHandlers) CreateInstanceHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
@@ -33,7 +50,7 @@ func (h *CloudHandlers) CreateInstanceHandler() gin.HandlerFunc {
 			return
 		}
 
-		inst, err := h.manager.CreateInstance(ctx, &req)
+		task, err := h.tasks.StartProvision(ctx, &req)
 		if err != nil {
 			status := http.StatusInternalServerError
 			switch {
@@ -52,12 +69,12 @@ func (h *CloudHandlers) CreateInstanceHandler() gin.HandlerFunc {
 			case errors.Is(err, cloud.ErrBillingServiceUnavailable):
 				status = http.StatusServiceUnavailable
 			}
-			c.JSON(status, gin.H{"error": err.Error()})
+			c.JSON(status, gin.H{"error": err.Error(), "task_id": task.ID})
 			return
 		}
 
-		log.Info().Str("id", inst.ID).Str("platform", string(inst.Platform)).Msg("cloud instance created")
-		c.JSON(http.StatusCreated, inst)
+		log.Info().Str("task_id", task.ID).Str("instance_id", task.InstanceID).Msg("cloud instance provisioning task started")
+		c.JSON(http.StatusAccepted, gin.H{"task_id": task.ID})
 	}
 }
 
@@ -262,6 +279,22 @@ func (h *CloudHandlers) ExecuteCommandHandler() gin.HandlerFunc {
 	}
 }
 
+// SessionHandler handles GET /api/v1/cloud/instances/:id/session, a
+// WebSocket endpoint that multiplexes a browser to the instance's RDP/VNC/SSH
+// backend via a Guacamole-compatible broker.
+func (h *CloudHandlers) SessionHandler() gin.HandlerFunc {
+	return h.broker.ServeWS
+}
+
+// ExecWSHandler handles GET /api/ui/v1/cloud/instances/:id/exec/ws, a
+// WebSocket endpoint for an interactive exec session into the instance (a
+// K8s pod's SPDY exec stream, or an EC2 instance's SSM session), for the UI
+// terminal view. Unlike ExecuteCommandHandler, output streams live instead
+// of buffering until the command finishes.
+func (h *CloudHandlers) ExecWSHandler() gin.HandlerFunc {
+	return h.exec.ServeWS
+}
+
 // GetQuotaHandler handles GET /api/v1/cloud/quota
 func (h *CloudHandlers) GetQuotaHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -281,3 +314,222 @@ func (h *CloudHandlers) GetQuotaHandler() gin.HandlerFunc {
 		c.JSON(http.StatusOK, quota)
 	}
 }
+
+// bootstrapExchangeRequest is the body POST /api/v1/cloud/bootstrap/exchange
+// expects: the one-time token RenderUserData embedded in the instance's
+// userdata.
+type bootstrapExchangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// BootstrapExchangeHandler handles POST /api/v1/cloud/bootstrap/exchange.
+// A freshly booted instance posts its bootstrap token here once to get back
+// its real HANZO_AGENTS_API_KEY; this endpoint is unauthenticated since the
+// instance has no credentials yet, and relies entirely on the token's
+// signature, short TTL, and single-use enforcement for security.
+func (h *CloudHandlers) BootstrapExchangeHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req bootstrapExchangeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+			return
+		}
+
+		instanceID, err := h.manager.Bootstrap().Exchange(req.Token)
+		if err != nil {
+			status := http.StatusUnauthorized
+			if errors.Is(err, cloud.ErrBootstrapTokenUsed) {
+				status = http.StatusConflict
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		log.Info().Str("instance_id", instanceID).Msg("cloud bootstrap token exchanged")
+		c.JSON(http.StatusOK, gin.H{"api_key": h.manager.Config().Bootstrap.AgentAPIKey})
+	}
+}
+
+// eventStreamKeepalive is how often StreamEventsHandler writes a
+// ":keepalive" comment frame, so intermediate proxies don't time out an
+// idle connection.
+const eventStreamKeepalive = 15 * time.Second
+
+// eventFilter narrows which events StreamEventsHandler/GetRecentEventsHandler
+// forward, built from the request's ?type=/?instance_id=/?team_id= query
+// params. An empty field matches everything.
+type eventFilter struct {
+	typePattern string
+	instanceID  string
+	teamID      string
+}
+
+func parseEventFilter(c *gin.Context) eventFilter {
+	return eventFilter{
+		typePattern: c.Query("type"),
+		instanceID:  c.Query("instance_id"),
+		teamID:      c.Query("team_id"),
+	}
+}
+
+// matches reports whether event satisfies every filter field that was set.
+// typePattern is a shell-style glob (path.Match), e.g. "instance.*".
+func (f eventFilter) matches(event types.CloudEvent) bool {
+	if f.typePattern != "" {
+		if ok, err := path.Match(f.typePattern, event.Type); err != nil || !ok {
+			return false
+		}
+	}
+	if f.instanceID != "" && event.InstanceID != f.instanceID {
+		return false
+	}
+	if f.teamID != "" && eventTeamID(event) != f.teamID {
+		return false
+	}
+	return true
+}
+
+// eventTeamID best-effort extracts a "team_id" field from event.Data, since
+// CloudEvent itself carries no team attribution - only whatever payload the
+// emitting call site attached (often a CloudInstance, which does have one).
+// Events whose payload has no team_id never match a ?team_id= filter.
+func eventTeamID(event types.CloudEvent) string {
+	if len(event.Data) == 0 {
+		return ""
+	}
+	var payload struct {
+		TeamID string `json:"team_id"`
+	}
+	if err := json.Unmarshal(event.Data, &payload); err != nil {
+		return ""
+	}
+	return payload.TeamID
+}
+
+// eventIndexByID returns the index of the event with the given ID in
+// events, or -1 if not found.
+func eventIndexByID(events []types.CloudEvent, id string) int {
+	for i, event := range events {
+		if event.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func writeCloudSSEEvent(c *gin.Context, event types.CloudEvent) {
+	fmt.Fprintf(c.Writer, "id: %s\n", event.ID)
+	fmt.Fprintf(c.Writer, "event: %s\n", event.Type)
+	if event.Data != nil {
+		fmt.Fprintf(c.Writer, "data: %s\n\n", string(event.Data))
+	} else {
+		fmt.Fprintf(c.Writer, "data: {\"instance_id\":\"%s\"}\n\n", event.InstanceID)
+	}
+}
+
+// StreamEventsHandler handles GET /api/v1/cloud/events as a Server-Sent
+// Events stream. On connect it replays events from EventBus's buffer - by
+// Last-Event-ID if the client reconnected, else the last ?replay= events
+// (default 0, i.e. no replay) - then forwards new events as they're
+// published until the client disconnects. ?type=/?instance_id=/?team_id=
+// filter which events are sent; see eventFilter.
+func (h *CloudHandlers) StreamEventsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventBus := h.manager.EventBus()
+		filter := parseEventFilter(c)
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+
+		var replay []types.CloudEvent
+		if lastID := c.GetHeader("Last-Event-ID"); lastID != "" {
+			buffered := eventBus.Recent(0)
+			if idx := eventIndexByID(buffered, lastID); idx >= 0 {
+				replay = buffered[idx+1:]
+			} else {
+				replay = buffered
+			}
+		} else if n, err := strconv.Atoi(c.Query("replay")); err == nil && n > 0 {
+			replay = eventBus.Recent(n)
+		}
+
+		// Subscribe before replaying so nothing published during replay is
+		// missed between the two.
+		subID, ch := eventBus.Subscribe()
+		defer eventBus.Unsubscribe(subID)
+
+		for _, event := range replay {
+			if filter.matches(event) {
+				writeCloudSSEEvent(c, event)
+			}
+		}
+		c.Writer.Flush()
+
+		keepalive := time.NewTicker(eventStreamKeepalive)
+		defer keepalive.Stop()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+				if filter.matches(event) {
+					writeCloudSSEEvent(c, event)
+					c.Writer.Flush()
+				}
+			case <-keepalive.C:
+				fmt.Fprint(c.Writer, ": keepalive\n\n")
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// GetRecentEventsHandler handles GET /api/v1/cloud/events?since=<rfc3339>&limit=<n>,
+// a non-streaming snapshot of EventBus's buffer for clients that just want
+// a point-in-time read rather than a live stream. Supports the same
+// ?type=/?instance_id=/?team_id= filters as StreamEventsHandler.
+func (h *CloudHandlers) GetRecentEventsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := 100
+		if v := c.Query("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				limit = n
+			}
+		}
+
+		var since time.Time
+		if v := c.Query("since"); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since: " + err.Error()})
+				return
+			}
+			since = parsed
+		}
+
+		filter := parseEventFilter(c)
+		buffered := h.manager.EventBus().Recent(0)
+
+		filtered := make([]types.CloudEvent, 0, len(buffered))
+		for _, event := range buffered {
+			if !since.IsZero() && !event.Time.After(since) {
+				continue
+			}
+			if !filter.matches(event) {
+				continue
+			}
+			filtered = append(filtered, event)
+		}
+		if len(filtered) > limit {
+			filtered = filtered[len(filtered)-limit:]
+		}
+
+		c.JSON(http.StatusOK, gin.H{"events": filtered, "count": len(filtered)})
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hanzoai/agents/control-plane/internal/cloud"
+)
+
+// TaskHandlers holds dependencies for the async task API.
+type TaskHandlers struct {
+	tasks *cloud.TaskManager
+}
+
+// NewTaskHandlers creates a new TaskHandlers instance.
+func NewTaskHandlers(tasks *cloud.TaskManager) *TaskHandlers {
+	return &TaskHandlers{tasks: tasks}
+}
+
+// GetTaskHandler handles GET /api/v1/tasks/:id. By default it returns the
+// task's current snapshot; pass ?stream=1 to instead keep the connection
+// open and stream each status update as an SSE event until the task
+// completes.
+func (h *TaskHandlers) GetTaskHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+
+		if c.Query("stream") == "" {
+			task, err := h.tasks.Get(id)
+			if err != nil {
+				if errors.Is(err, cloud.ErrTaskNotFound) {
+					c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, task)
+			return
+		}
+
+		h.streamTask(c, id)
+	}
+}
+
+func (h *TaskHandlers) streamTask(c *gin.Context, id string) {
+	task, err := h.tasks.Get(id)
+	if err != nil {
+		if errors.Is(err, cloud.ErrTaskNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	updates, err := h.tasks.StatusUpdates(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	c.SSEvent("task", task)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry, ok := <-updates:
+			if !ok {
+				final, err := h.tasks.Get(id)
+				if err == nil {
+					c.SSEvent("task", final)
+					c.Writer.Flush()
+				}
+				return
+			}
+			c.SSEvent("status", entry)
+			c.Writer.Flush()
+		}
+	}
+}
+
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hanzoai/agents/control-plane/internal/services"
+	"github.com/hanzoai/agents/control-plane/pkg/types"
+)
+
+// ledgerEntryEventType is the CloudEvent type stamped on every ledger entry
+// streamed by StreamLedgerEventsHandler.
+const ledgerEntryEventType = "billing.ledger_entry"
+
+// BillingHandler provides UI-friendly billing admin endpoints.
+type BillingHandler struct {
+	billing *services.BillingService
+}
+
+// NewBillingHandler creates a new BillingHandler.
+func NewBillingHandler(billing *services.BillingService) *BillingHandler {
+	return &BillingHandler{billing: billing}
+}
+
+// ListDeadLetterDebitsHandler handles GET /api/ui/v1/billing/outbox/dead-letter
+func (h *BillingHandler) ListDeadLetterDebitsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	records, err := h.billing.ListDeadLetterDebits(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"debits": records,
+		"count":  len(records),
+	})
+}
+
+// ListLedgerHandler handles GET /api/ui/v1/billing/ledger?user=...&limit=...&offset=...
+func (h *BillingHandler) ListLedgerHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.Query("user")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user is required"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	entries, err := h.billing.Ledger().List(ctx, userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// StreamLedgerEventsHandler handles GET /api/ui/v1/billing/events (SSE),
+// reusing writeSSEEvent from CloudHandler.StreamEventsHandler so the UI's
+// live audit log shares one SSE client implementation with cloud events.
+func (h *BillingHandler) StreamLedgerEventsHandler(c *gin.Context) {
+	l := h.billing.Ledger()
+
+	subID, ch := l.Subscribe()
+	defer l.Unsubscribe(subID)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	for _, entry := range l.Recent(20) {
+		writeSSEEvent(c.Writer, ledgerEntryToCloudEvent(entry))
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case entry, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeSSEEvent(c.Writer, ledgerEntryToCloudEvent(entry))
+			c.Writer.Flush()
+		case <-ticker.C:
+			c.Writer.Write([]byte(": ping\n\n"))
+			c.Writer.Flush()
+		}
+	}
+}
+
+func ledgerEntryToCloudEvent(entry *types.LedgerEntry) types.CloudEvent {
+	data, _ := json.Marshal(entry)
+	return types.CloudEvent{
+		Type:    ledgerEntryEventType,
+		Subject: entry.User,
+		Time:    entry.Timestamp,
+		Data:    data,
+	}
+}
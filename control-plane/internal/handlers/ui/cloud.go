@@ -11,17 +11,24 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/hanzoai/agents/control-plane/internal/cloud"
+	"github.com/hanzoai/agents/control-plane/internal/services"
 	"github.com/hanzoai/agents/control-plane/pkg/types"
 )
 
 // CloudHandler provides UI-friendly cloud instance endpoints.
 type CloudHandler struct {
 	manager *cloud.CloudManager
+	// pricing looks up a user's remaining prepaid package balances for
+	// GetSummaryHandler. May be nil, in which case package_balances is
+	// omitted from the summary response.
+	pricing *services.PricingService
 }
 
-// NewCloudHandler creates a new CloudHandler.
-func NewCloudHandler(manager *cloud.CloudManager) *CloudHandler {
-	return &CloudHandler{manager: manager}
+// NewCloudHandler creates a new CloudHandler. pricing may be nil if no
+// PricingService is configured, in which case GetSummaryHandler's response
+// never includes package_balances.
+func NewCloudHandler(manager *cloud.CloudManager, pricing *services.PricingService) *CloudHandler {
+	return &CloudHandler{manager: manager, pricing: pricing}
 }
 
 // ListInstancesHandler handles GET /api/ui/v1/cloud/instances
@@ -109,6 +116,9 @@ func (h *CloudHandler) GetInstanceDetailsHandler(c *gin.Context) {
 	var connInfo *types.ConnectionInfo
 	if inst.State == types.InstanceStateRunning {
 		connInfo, _ = h.manager.GetConnectionInfo(ctx, id)
+		if connInfo != nil && connInfo.Password != "" && !hasReadCredentialsScope(c) {
+			connInfo.Password = ""
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -117,6 +127,25 @@ func (h *CloudHandler) GetInstanceDetailsHandler(c *gin.Context) {
 	})
 }
 
+// readCredentialsScope gates access to a decrypted ConnectionInfo.Password
+// (e.g. a Windows instance's RDP password decrypted server-side by
+// aws.KeyManager). Callers without it still see connection metadata and,
+// for Windows, the encrypted password blob — just not the plaintext.
+const readCredentialsScope = "cloud:read_credentials"
+
+// hasReadCredentialsScope reports whether the authenticated caller holds
+// readCredentialsScope. No scope-issuing auth middleware is wired up yet in
+// this deployment, so this currently always returns false — the safe
+// default of withholding plaintext credentials until one is.
+func hasReadCredentialsScope(c *gin.Context) bool {
+	for _, s := range c.GetStringSlice("scopes") {
+		if s == readCredentialsScope {
+			return true
+		}
+	}
+	return false
+}
+
 // GetSummaryHandler handles GET /api/ui/v1/cloud/summary
 func (h *CloudHandler) GetSummaryHandler(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -134,10 +163,25 @@ func (h *CloudHandler) GetSummaryHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"enabled": true,
 		"summary": summary,
-	})
+	}
+
+	// Surface the caller's remaining prepaid package balances alongside the
+	// fleet summary, so the UI can show how much of a run's cost a package
+	// already covers without a second round trip. Omitted unless the
+	// caller names a user_id and a PricingService is installed.
+	if userID := c.Query("user_id"); userID != "" && h.pricing != nil {
+		balances, err := h.pricing.GetSummary(ctx, userID)
+		if err != nil {
+			log.Warn().Err(err).Str("user", userID).Msg("failed to load package balances for cloud summary")
+		} else {
+			resp["package_balances"] = balances
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // StreamEventsHandler handles GET /api/ui/v1/cloud/events (SSE)
@@ -181,6 +225,74 @@ func (h *CloudHandler) StreamEventsHandler(c *gin.Context) {
 	}
 }
 
+// ListFreezesHandler handles GET /api/ui/v1/cloud/freezes
+func (h *CloudHandler) ListFreezesHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.Query("user_id")
+	activeOnly := c.Query("active") != "false"
+
+	freezes, err := h.manager.Freezes().List(ctx, userID, activeOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"freezes": freezes,
+		"count":   len(freezes),
+	})
+}
+
+// freezeRequest is the body for POST /api/ui/v1/cloud/freezes.
+type freezeRequest struct {
+	UserID   string                 `json:"user_id" binding:"required"`
+	Type     types.FreezeType       `json:"type" binding:"required"`
+	Reason   string                 `json:"reason" binding:"required"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// CreateFreezeHandler handles POST /api/ui/v1/cloud/freezes
+func (h *CloudHandler) CreateFreezeHandler(c *gin.Context) {
+	var req freezeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	freeze, err := h.manager.Freezes().Freeze(c.Request.Context(), req.UserID, req.Type, req.Reason, req.Metadata)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"freeze": freeze})
+}
+
+// UnfreezeHandler handles DELETE /api/ui/v1/cloud/freezes/:user_id
+func (h *CloudHandler) UnfreezeHandler(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	if err := h.manager.Freezes().Unfreeze(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account unfrozen"})
+}
+
+// EscalateFreezeHandler handles POST /api/ui/v1/cloud/freezes/:user_id/escalate
+func (h *CloudHandler) EscalateFreezeHandler(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	if err := h.manager.Freezes().Escalate(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account freeze escalated"})
+}
+
 func writeSSEEvent(w io.Writer, event types.CloudEvent) {
 	fmt.Fprintf(w, "event: %s\n", event.Type)
 	fmt.Fprintf(w, "id: %s\n", event.ID)
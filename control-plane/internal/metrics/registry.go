@@ -0,0 +1,71 @@
+// Package metrics provides a thin, shared helper for building
+// Prometheus-compatible collectors, so each subsystem's metrics file (see
+// cloud/jobs/metrics.go, cloud/scheduler_metrics.go, storage/metrics.go) can
+// construct and register its counters/gauges/histograms the same way
+// without repeating the nil-registerer check at every call site.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Registry wraps a prometheus.Registerer and registers each collector it
+// builds. The wrapped Registerer may be nil, in which case collectors are
+// still created and usable — they're just never exposed to a scraper. Tests
+// that want to assert a counter changed can pass prometheus.NewRegistry()
+// in place of the production registerer and read it back directly.
+type Registry struct {
+	reg prometheus.Registerer
+}
+
+// New wraps reg, which may be nil, in a Registry.
+func New(reg prometheus.Registerer) *Registry {
+	return &Registry{reg: reg}
+}
+
+// Counter builds and registers a Counter.
+func (r *Registry) Counter(opts prometheus.CounterOpts) prometheus.Counter {
+	c := prometheus.NewCounter(opts)
+	r.register(c)
+	return c
+}
+
+// CounterVec builds and registers a CounterVec.
+func (r *Registry) CounterVec(opts prometheus.CounterOpts, labels []string) *prometheus.CounterVec {
+	c := prometheus.NewCounterVec(opts, labels)
+	r.register(c)
+	return c
+}
+
+// Gauge builds and registers a Gauge.
+func (r *Registry) Gauge(opts prometheus.GaugeOpts) prometheus.Gauge {
+	g := prometheus.NewGauge(opts)
+	r.register(g)
+	return g
+}
+
+// GaugeVec builds and registers a GaugeVec.
+func (r *Registry) GaugeVec(opts prometheus.GaugeOpts, labels []string) *prometheus.GaugeVec {
+	g := prometheus.NewGaugeVec(opts, labels)
+	r.register(g)
+	return g
+}
+
+// Histogram builds and registers a Histogram.
+func (r *Registry) Histogram(opts prometheus.HistogramOpts) prometheus.Histogram {
+	h := prometheus.NewHistogram(opts)
+	r.register(h)
+	return h
+}
+
+// HistogramVec builds and registers a HistogramVec.
+func (r *Registry) HistogramVec(opts prometheus.HistogramOpts, labels []string) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(opts, labels)
+	r.register(h)
+	return h
+}
+
+func (r *Registry) register(c prometheus.Collector) {
+	if r.reg == nil {
+		return
+	}
+	r.reg.MustRegister(c)
+}
@@ -1,13 +1,19 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
 )
 
 // IAMConfig holds IAM authentication configuration for the middleware.
@@ -19,6 +25,73 @@ type IAMConfig struct {
 	ClientSecret   string
 	Organization   string
 	Application    string
+
+	// JWKSEndpoint, if set, enables local JWT verification: Bearer tokens
+	// that parse as a JWT are checked against a key fetched from this URL
+	// instead of calling /api/userinfo. Opaque tokens, and JWTs whose kid
+	// isn't found even after an on-demand JWKS refresh, still go through
+	// the existing /api/userinfo path.
+	JWKSEndpoint string
+	// Issuer and Audiences are checked against a verified JWT's iss/aud
+	// claims. Audiences matches if the token's aud contains any of these.
+	Issuer    string
+	Audiences []string
+	// AllowedAlgs restricts which JWS "alg" values are accepted. Defaults
+	// to []string{"RS256"} if empty.
+	AllowedAlgs []string
+	// ClaimMap overrides which JWT claim populates each IAMUserInfo field.
+	// Fields left zero fall back to defaultClaimMap.
+	ClaimMap ClaimMap
+
+	// Sessions, if set, changes what the session cookie is taken to mean:
+	// its value is looked up as a session ID rather than trusted directly
+	// as a bearer token, so a leaked cookie can be revoked without
+	// invalidating the IAM token it wraps. Revoked or expired sessions are
+	// rejected before the wrapped token is ever validated. Left nil, the
+	// cookie value is used as the token directly, as before.
+	Sessions SessionStore
+	// RefreshWindow is how far ahead of a session's ExpiresAt IAMAuth starts
+	// transparently refreshing it via the IAM refresh_token grant. Defaults
+	// to defaultRefreshWindow if zero. Only applies when Sessions is set and
+	// the session carries a RefreshToken.
+	RefreshWindow time.Duration
+}
+
+// defaultRefreshWindow is used when IAMConfig.RefreshWindow is unset.
+const defaultRefreshWindow = 5 * time.Minute
+
+// ClaimMap names the JWT claims that populate IAMUserInfo when verifying a
+// token locally via JWKSEndpoint.
+type ClaimMap struct {
+	Subject string
+	Email   string
+	Name    string
+	Org     string
+}
+
+// defaultClaimMap is the standard OIDC claim set: "sub", "email", "name",
+// and a non-standard "org" claim for the user's organization/tenant.
+var defaultClaimMap = ClaimMap{
+	Subject: "sub",
+	Email:   "email",
+	Name:    "name",
+	Org:     "org",
+}
+
+func (m ClaimMap) withDefaults() ClaimMap {
+	if m.Subject == "" {
+		m.Subject = defaultClaimMap.Subject
+	}
+	if m.Email == "" {
+		m.Email = defaultClaimMap.Email
+	}
+	if m.Name == "" {
+		m.Name = defaultClaimMap.Name
+	}
+	if m.Org == "" {
+		m.Org = defaultClaimMap.Org
+	}
+	return m
 }
 
 // IAMUserInfo represents the user identity returned by IAM userinfo endpoint.
@@ -44,9 +117,18 @@ const (
 	// tokenCacheTTL is how long validated tokens are cached.
 	tokenCacheTTL = 60 * time.Second
 
+	// negativeCacheTTL is how long a token that failed local JWT
+	// verification (bad signature, issuer, audience, or expiry) is
+	// remembered as invalid, so it isn't reverified on every request.
+	negativeCacheTTL = 60 * time.Second
+
 	// sessionCookieName is the cookie used for browser-based OAuth sessions.
 	SessionCookieName = "hanzo_agents_session"
 
+	// csrfHeaderName is the header CombinedAuth requires session-cookie
+	// requests to echo a session's CSRFSecret back in for unsafe methods.
+	csrfHeaderName = "X-CSRF-Token"
+
 	// Gin context keys for IAM user info.
 	ContextKeyIAMUser     = "iam_user"
 	ContextKeyIAMUserID   = "iam_user_id"
@@ -54,11 +136,40 @@ const (
 	ContextKeyIAMEmail    = "iam_user_email"
 	ContextKeyIAMOrg      = "iam_user_org"
 	ContextKeyAuthMethod  = "auth_method"
+	// ContextKeySigV4ARN holds the caller ARN STS resolved for a request
+	// authenticated via SigV4Auth.
+	ContextKeySigV4ARN = "sigv4_arn"
 )
 
 // tokenCache is a concurrent-safe cache for validated IAM tokens.
 var tokenCache sync.Map
 
+// negativeTokenCache is a concurrent-safe cache of token -> expiry time for
+// tokens proven invalid by local JWT verification. Separate from tokenCache
+// so a flood of requests bearing the same bad token don't each pay the cost
+// of re-parsing and re-verifying its signature.
+var negativeTokenCache sync.Map
+
+// errInvalidJWT marks a token as definitively invalid - a JWT with a known
+// kid but a bad signature, issuer, audience, or expiry - as opposed to
+// merely unrecognized (an opaque token, or a JWT with an unknown kid),
+// which falls back to /api/userinfo instead of being rejected outright.
+var errInvalidJWT = errors.New("iam: invalid jwt")
+
+// isNegativelyCached reports whether token was recently proven invalid.
+func isNegativelyCached(token string) bool {
+	v, ok := negativeTokenCache.Load(token)
+	if !ok {
+		return false
+	}
+	expiresAt := v.(time.Time)
+	if time.Now().After(expiresAt) {
+		negativeTokenCache.Delete(token)
+		return false
+	}
+	return true
+}
+
 // IAMAuth validates requests using IAM Bearer tokens or session cookies.
 // If IAM validation succeeds, user identity is set in gin context.
 // If IAM validation fails, the middleware does NOT abort - it allows the
@@ -66,16 +177,31 @@ var tokenCache sync.Map
 func IAMAuth(config IAMConfig) gin.HandlerFunc {
 	client := &http.Client{Timeout: 5 * time.Second}
 
+	var jwks *jwksCache
+	if config.JWKSEndpoint != "" {
+		jwks = newJWKSCache(config.JWKSEndpoint)
+	}
+
 	return func(c *gin.Context) {
 		if !config.Enabled {
 			c.Next()
 			return
 		}
 
-		// Extract token: try Authorization header first, then session cookie.
-		token := extractBearerToken(c)
-		if token == "" {
-			token = extractSessionCookie(c)
+		// Extract token: try Authorization header first, then session
+		// cookie. cacheKey is what tokenCache is keyed on - normally the
+		// token itself, but the session ID when config.Sessions is set, so
+		// a Revoke (which only knows the session ID) can find it.
+		token, cacheKey, sessionID, csrfSecret := extractBearerToken(c), "", "", ""
+		if token != "" {
+			cacheKey = token
+		} else {
+			token, sessionID, csrfSecret = resolveSessionToken(c, config, client)
+			cacheKey = sessionID
+		}
+
+		if enforceCSRF(c, token, sessionID, csrfSecret) {
+			return
 		}
 
 		if token == "" {
@@ -85,36 +211,186 @@ func IAMAuth(config IAMConfig) gin.HandlerFunc {
 		}
 
 		// Check token cache first.
-		if entry, ok := tokenCache.Load(token); ok {
+		if entry, ok := tokenCache.Load(cacheKey); ok {
 			cached := entry.(*tokenCacheEntry)
 			if time.Now().Before(cached.expiresAt) {
+				touchSessionAsync(config.Sessions, sessionID)
 				setIAMUserContext(c, cached.user)
 				c.Next()
 				return
 			}
 			// Expired entry, remove it.
-			tokenCache.Delete(token)
+			tokenCache.Delete(cacheKey)
 		}
 
-		// Validate token against IAM userinfo endpoint.
-		userInfo, err := validateTokenWithIAM(client, config.Endpoint, token)
+		if isNegativelyCached(token) {
+			c.Next()
+			return
+		}
+
+		// Verify the token, locally against the JWKS if it's a JWT, falling
+		// back to the IAM userinfo endpoint otherwise.
+		userInfo, err := resolveIAMUser(client, jwks, config, token)
 		if err != nil {
+			if errors.Is(err, errInvalidJWT) {
+				negativeTokenCache.Store(token, time.Now().Add(negativeCacheTTL))
+			}
 			// IAM validation failed. Do NOT abort - let API key middleware try.
 			c.Next()
 			return
 		}
 
 		// Cache the validated token.
-		tokenCache.Store(token, &tokenCacheEntry{
+		tokenCache.Store(cacheKey, &tokenCacheEntry{
 			user:      userInfo,
 			expiresAt: time.Now().Add(tokenCacheTTL),
 		})
 
+		touchSessionAsync(config.Sessions, sessionID)
 		setIAMUserContext(c, userInfo)
 		c.Next()
 	}
 }
 
+// resolveIAMUser validates token, verifying its signature locally against
+// jwks when it looks like a JWT, and falling back to the /api/userinfo
+// network call for opaque tokens or JWTs whose kid isn't in the JWKS.
+func resolveIAMUser(client *http.Client, jwks *jwksCache, config IAMConfig, token string) (*IAMUserInfo, error) {
+	if jwks != nil && looksLikeJWT(token) {
+		user, handled, err := verifyJWTLocally(jwks, config, token)
+		if handled {
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", errInvalidJWT, err)
+			}
+			return user, nil
+		}
+		// kid unknown even after refresh - fall through to the opaque path.
+	}
+
+	return validateTokenWithIAM(client, config.Endpoint, token)
+}
+
+// looksLikeJWT reports whether token is shaped like a JWT (three
+// dot-separated segments), without parsing it.
+func looksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// verifyJWTLocally validates token's signature against jwks and its
+// iss/aud/exp/nbf claims, returning the extracted IAMUserInfo. handled is
+// false only when the token's kid isn't found in the JWKS even after an
+// on-demand refresh - an inconclusive result, distinct from a proven-bad
+// signature or claim, that tells the caller to fall back to
+// /api/userinfo instead of rejecting the token.
+func verifyJWTLocally(jwks *jwksCache, config IAMConfig, token string) (user *IAMUserInfo, handled bool, err error) {
+	algs := config.AllowedAlgs
+	if len(algs) == 0 {
+		algs = []string{"RS256"}
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(algs), jwt.WithExpirationRequired()}
+	if config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(config.Issuer))
+	}
+	parser := jwt.NewParser(opts...)
+
+	claims := jwt.MapClaims{}
+	var kidErr error
+	parsedToken, parseErr := parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("jwt missing kid header")
+		}
+		key, err := jwks.key(kid)
+		if err != nil {
+			kidErr = err
+			return nil, err
+		}
+		return key, nil
+	})
+	if errors.Is(kidErr, errJWKSUnknownKid) {
+		return nil, false, nil
+	}
+	if parseErr != nil {
+		return nil, true, parseErr
+	}
+	if !parsedToken.Valid {
+		return nil, true, fmt.Errorf("jwt failed validation")
+	}
+
+	if len(config.Audiences) > 0 {
+		aud, _ := claims.GetAudience()
+		if !audienceMatches(aud, config.Audiences) {
+			return nil, true, fmt.Errorf("token audience not accepted")
+		}
+	}
+
+	cm := config.ClaimMap.withDefaults()
+	info := &IAMUserInfo{
+		ID:    claimString(claims, cm.Subject),
+		Email: claimString(claims, cm.Email),
+		Name:  claimString(claims, cm.Name),
+		Owner: claimString(claims, cm.Org),
+	}
+	if info.ID == "" && info.Email == "" {
+		return nil, true, fmt.Errorf("jwt missing both subject and email claims")
+	}
+
+	return info, true, nil
+}
+
+func claimString(claims jwt.MapClaims, key string) string {
+	if key == "" {
+		return ""
+	}
+	v, ok := claims[key]
+	if !ok {
+		return ""
+	}
+	s, _ := v.(string)
+	return s
+}
+
+func audienceMatches(tokenAud jwt.ClaimStrings, allowed []string) bool {
+	for _, a := range tokenAud {
+		for _, want := range allowed {
+			if a == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isUnsafeMethod reports whether method can mutate state, per RFC 7231 -
+// the set of methods CSRF protection needs to cover.
+func isUnsafeMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// enforceCSRF aborts the request with 403 and returns true if it's a
+// cookie-authenticated (sessionID != ""), state-changing request whose
+// X-CSRF-Token header doesn't match the session's csrfSecret - the standard
+// double-submit mitigation for opaque session cookies. A Bearer token never
+// rides along with a forged cross-site request involuntarily, so it's
+// exempt. Shared by IAMAuth and CombinedAuth, since both resolve the same
+// session cookie via resolveSessionToken.
+func enforceCSRF(c *gin.Context, token, sessionID, csrfSecret string) bool {
+	if token != "" && sessionID != "" && isUnsafeMethod(c.Request.Method) && c.GetHeader(csrfHeaderName) != csrfSecret {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error":   "csrf_failed",
+			"message": "missing or invalid CSRF token",
+		})
+		return true
+	}
+	return false
+}
+
 // extractBearerToken extracts a Bearer token from the Authorization header.
 func extractBearerToken(c *gin.Context) string {
 	authHeader := c.GetHeader("Authorization")
@@ -124,7 +400,9 @@ func extractBearerToken(c *gin.Context) string {
 	return ""
 }
 
-// extractSessionCookie extracts the access token from the session cookie.
+// extractSessionCookie extracts the raw session cookie value, which is
+// either a bearer token (config.Sessions unset) or an opaque session ID
+// (config.Sessions set) depending on how the cookie was minted at login.
 func extractSessionCookie(c *gin.Context) string {
 	cookie, err := c.Cookie(SessionCookieName)
 	if err != nil {
@@ -133,6 +411,147 @@ func extractSessionCookie(c *gin.Context) string {
 	return cookie
 }
 
+// resolveSessionToken resolves the session cookie to an IAM token. When
+// config.Sessions is nil, the cookie value is the token itself, for
+// backward compatibility with deployments that haven't adopted
+// SessionStore. Otherwise the cookie value is a session ID: it's looked up
+// and rejected outright if missing, expired, or revoked, before its
+// wrapped token is returned. If the session's ExpiresAt falls within
+// config.RefreshWindow, it's transparently refreshed via the IAM
+// refresh_token grant first. The returned sessionID and csrfSecret are ""
+// in the legacy (no SessionStore) case.
+func resolveSessionToken(c *gin.Context, config IAMConfig, client *http.Client) (token, sessionID, csrfSecret string) {
+	cookie := extractSessionCookie(c)
+	if cookie == "" {
+		return "", "", ""
+	}
+	if config.Sessions == nil {
+		return cookie, "", ""
+	}
+
+	sess, err := config.Sessions.Get(c.Request.Context(), cookie)
+	if err != nil || sess == nil {
+		return "", "", ""
+	}
+	if revoked, _ := config.Sessions.IsRevoked(c.Request.Context(), cookie); revoked {
+		return "", "", ""
+	}
+
+	sess = maybeRefreshSession(c.Request.Context(), client, config, sess)
+	return sess.Token, sess.ID, sess.CSRFSecret
+}
+
+// maybeRefreshSession transparently rotates sess's token set via the IAM
+// refresh_token grant if it's within config.RefreshWindow of expiring and
+// carries a RefreshToken. Refresh failures are logged and swallowed: sess
+// is returned unchanged, so the request proceeds on the still-valid (if
+// soon to expire) token rather than failing the request over a refresh
+// hiccup.
+func maybeRefreshSession(ctx context.Context, client *http.Client, config IAMConfig, sess *Session) *Session {
+	if sess.RefreshToken == "" || config.Sessions == nil {
+		return sess
+	}
+	window := config.RefreshWindow
+	if window <= 0 {
+		window = defaultRefreshWindow
+	}
+	if time.Until(sess.ExpiresAt) > window {
+		return sess
+	}
+
+	tokens, ttl, err := refreshIAMToken(client, config, sess.RefreshToken)
+	if err != nil {
+		log.Debug().Err(err).Str("session_id", sess.ID).Msg("iam: failed to refresh session token")
+		return sess
+	}
+
+	if err := config.Sessions.Rotate(ctx, sess.ID, tokens, ttl); err != nil {
+		log.Warn().Err(err).Str("session_id", sess.ID).Msg("iam: failed to persist refreshed session token")
+		return sess
+	}
+
+	sess.Token = tokens.AccessToken
+	sess.RefreshToken = tokens.RefreshToken
+	sess.IDToken = tokens.IDToken
+	sess.ExpiresAt = time.Now().UTC().Add(ttl)
+	return sess
+}
+
+// iamRefreshTokenResponse mirrors the fields of routes.IAMTokenResponse
+// that refreshIAMToken needs. It's redeclared here, rather than imported,
+// because routes already imports middleware and Go doesn't allow the
+// reverse.
+type iamRefreshTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+}
+
+// refreshIAMToken exchanges refreshToken for a new token set via IAM's
+// refresh_token grant, the same token endpoint /auth/callback uses for the
+// authorization_code grant.
+func refreshIAMToken(client *http.Client, config IAMConfig, refreshToken string) (SessionTokens, time.Duration, error) {
+	tokenURL := strings.TrimRight(config.Endpoint, "/") + "/api/login/oauth/access_token"
+
+	formData := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {config.ClientID},
+		"client_secret": {config.ClientSecret},
+	}
+
+	resp, err := client.PostForm(tokenURL, formData)
+	if err != nil {
+		return SessionTokens{}, 0, fmt.Errorf("iam: refresh request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return SessionTokens{}, 0, fmt.Errorf("iam: refresh returned status %d", resp.StatusCode)
+	}
+
+	var tokenResp iamRefreshTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return SessionTokens{}, 0, fmt.Errorf("iam: failed to parse refresh response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return SessionTokens{}, 0, fmt.Errorf("iam: refresh response had no access token")
+	}
+
+	ttl := time.Duration(tokenResp.ExpiresIn) * time.Second
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+
+	// IAM may omit refresh_token on a refresh response, meaning the same
+	// refresh token stays valid; keep the one we already have in that case.
+	newRefreshToken := tokenResp.RefreshToken
+	if newRefreshToken == "" {
+		newRefreshToken = refreshToken
+	}
+
+	return SessionTokens{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: newRefreshToken,
+		IDToken:      tokenResp.IDToken,
+	}, ttl, nil
+}
+
+// touchSessionAsync updates a session's last-seen timestamp off the
+// request path, so an active "your devices" view stays current without
+// adding SessionStore latency to every authenticated request.
+func touchSessionAsync(store SessionStore, sessionID string) {
+	if store == nil || sessionID == "" {
+		return
+	}
+	go func() {
+		if err := store.Touch(context.Background(), sessionID); err != nil {
+			log.Debug().Err(err).Str("session_id", sessionID).Msg("iam: failed to touch session")
+		}
+	}()
+}
+
 // validateTokenWithIAM calls the IAM userinfo endpoint to validate a token.
 func validateTokenWithIAM(client *http.Client, iamEndpoint, token string) (*IAMUserInfo, error) {
 	endpoint := strings.TrimRight(iamEndpoint, "/") + "/api/userinfo"
@@ -196,6 +615,17 @@ func IsIAMAuthenticated(c *gin.Context) bool {
 	return exists && method == "iam"
 }
 
+// GetSigV4ARN returns the caller ARN for a request authenticated via
+// SigV4Auth, or "" if the request wasn't.
+func GetSigV4ARN(c *gin.Context) string {
+	val, exists := c.Get(ContextKeySigV4ARN)
+	if !exists {
+		return ""
+	}
+	arn, _ := val.(string)
+	return arn
+}
+
 // IAMValidationError represents an error from IAM token validation.
 type IAMValidationError struct {
 	StatusCode int
@@ -205,9 +635,10 @@ func (e *IAMValidationError) Error() string {
 	return "IAM token validation failed"
 }
 
-// CombinedAuth creates a middleware that tries IAM auth first, then falls back
-// to API key authentication. A request must pass at least one method.
-func CombinedAuth(iamConfig IAMConfig, apiKeyConfig AuthConfig) gin.HandlerFunc {
+// CombinedAuth creates a middleware that tries IAM auth first, then SigV4
+// (if configured), then falls back to API key authentication. A request
+// must pass at least one method.
+func CombinedAuth(iamConfig IAMConfig, apiKeyConfig AuthConfig, sigV4Config SigV4Config) gin.HandlerFunc {
 	skipPathSet := make(map[string]struct{}, len(apiKeyConfig.SkipPaths))
 	for _, p := range apiKeyConfig.SkipPaths {
 		skipPathSet[p] = struct{}{}
@@ -215,9 +646,14 @@ func CombinedAuth(iamConfig IAMConfig, apiKeyConfig AuthConfig) gin.HandlerFunc
 
 	client := &http.Client{Timeout: 5 * time.Second}
 
+	var jwks *jwksCache
+	if iamConfig.Enabled && iamConfig.JWKSEndpoint != "" {
+		jwks = newJWKSCache(iamConfig.JWKSEndpoint)
+	}
+
 	return func(c *gin.Context) {
 		// No auth configured at all, allow everything.
-		if !iamConfig.Enabled && apiKeyConfig.APIKey == "" {
+		if !iamConfig.Enabled && !sigV4Config.Enabled && apiKeyConfig.APIKey == "" {
 			c.Next()
 			return
 		}
@@ -242,31 +678,57 @@ func CombinedAuth(iamConfig IAMConfig, apiKeyConfig AuthConfig) gin.HandlerFunc
 
 		// --- Try IAM auth first ---
 		if iamConfig.Enabled {
-			token := extractBearerToken(c)
-			if token == "" {
-				token = extractSessionCookie(c)
+			token, cacheKey, sessionID, csrfSecret := extractBearerToken(c), "", "", ""
+			if token != "" {
+				cacheKey = token
+			} else {
+				token, sessionID, csrfSecret = resolveSessionToken(c, iamConfig, client)
+				cacheKey = sessionID
+			}
+
+			if enforceCSRF(c, token, sessionID, csrfSecret) {
+				return
 			}
 
 			if token != "" {
 				// Check cache.
-				if entry, ok := tokenCache.Load(token); ok {
+				if entry, ok := tokenCache.Load(cacheKey); ok {
 					cached := entry.(*tokenCacheEntry)
 					if time.Now().Before(cached.expiresAt) {
+						touchSessionAsync(iamConfig.Sessions, sessionID)
 						setIAMUserContext(c, cached.user)
 						c.Next()
 						return
 					}
-					tokenCache.Delete(token)
+					tokenCache.Delete(cacheKey)
 				}
 
-				// Validate against IAM.
-				userInfo, err := validateTokenWithIAM(client, iamConfig.Endpoint, token)
-				if err == nil {
-					tokenCache.Store(token, &tokenCacheEntry{
-						user:      userInfo,
-						expiresAt: time.Now().Add(tokenCacheTTL),
-					})
-					setIAMUserContext(c, userInfo)
+				if !isNegativelyCached(token) {
+					userInfo, err := resolveIAMUser(client, jwks, iamConfig, token)
+					if err == nil {
+						tokenCache.Store(cacheKey, &tokenCacheEntry{
+							user:      userInfo,
+							expiresAt: time.Now().Add(tokenCacheTTL),
+						})
+						touchSessionAsync(iamConfig.Sessions, sessionID)
+						setIAMUserContext(c, userInfo)
+						c.Next()
+						return
+					}
+					if errors.Is(err, errInvalidJWT) {
+						negativeTokenCache.Store(token, time.Now().Add(negativeCacheTTL))
+					}
+				}
+			}
+		}
+
+		// --- Try SigV4 next ---
+		if sigV4Config.Enabled {
+			if identity, err := ResolveCallerIdentity(client, sigV4Config, c.Request); err == nil && identity != nil {
+				if userID, ok := MapARNToUser(sigV4Config.ARNMappings, identity.Arn); ok {
+					c.Set(ContextKeyIAMUserID, userID)
+					c.Set(ContextKeySigV4ARN, identity.Arn)
+					c.Set(ContextKeyAuthMethod, "sigv4")
 					c.Next()
 					return
 				}
@@ -275,17 +737,17 @@ func CombinedAuth(iamConfig IAMConfig, apiKeyConfig AuthConfig) gin.HandlerFunc
 
 		// --- Fall back to API key auth ---
 		if apiKeyConfig.APIKey == "" {
-			// No API key configured and IAM didn't authenticate.
-			// If IAM is enabled but no token was provided, require auth.
-			if iamConfig.Enabled {
+			// No API key configured and neither IAM nor SigV4 authenticated.
+			// If either is enabled, require auth rather than allow through.
+			if iamConfig.Enabled || sigV4Config.Enabled {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 					"error":       "unauthorized",
 					"message":     "authentication required",
-					"iam_enabled": true,
+					"iam_enabled": iamConfig.Enabled,
 				})
 				return
 			}
-			// Neither IAM nor API key configured - allow through.
+			// Nothing configured - allow through.
 			c.Next()
 			return
 		}
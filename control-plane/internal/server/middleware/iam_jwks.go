@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// jwksRefreshInterval is how often jwksCache re-fetches the IAM JWKS in the
+// background, independent of any on-demand refresh triggered by a kid miss.
+const jwksRefreshInterval = 15 * time.Minute
+
+// jwksMinRefreshInterval debounces on-demand refreshes triggered by an
+// unknown kid, so a flood of requests bearing a stale or bogus kid can't
+// hammer the JWKS endpoint.
+const jwksMinRefreshInterval = 10 * time.Second
+
+// errJWKSUnknownKid means the token's kid wasn't found even after an
+// on-demand refresh - it's inconclusive, not a proven-bad signature, so the
+// caller should fall back to the opaque-token /api/userinfo path rather than
+// rejecting the token outright.
+var errJWKSUnknownKid = errors.New("iam: kid not found in jwks")
+
+// jwk is the subset of a JSON Web Key this cache understands: RSA ("RSA")
+// and EC ("EC", curves P-256/P-384/P-521) public signing keys.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwksDocument struct {
+	Keys []jwk `json:"keys"`
+}
+
+// jwksCache fetches an IAM JWKS once and refreshes it on a background
+// ticker, so verifying a JWT's signature never costs a network round trip
+// per request. An unknown kid triggers one debounced on-demand refresh to
+// pick up a key rotation that landed between periodic refreshes.
+type jwksCache struct {
+	endpoint string
+	client   *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+
+	lastFetchMu sync.Mutex
+	lastFetch   time.Time
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// newJWKSCache creates a jwksCache for endpoint, fetches it once
+// synchronously (logging, not failing, if that first fetch errors), and
+// starts its background refresh loop.
+func newJWKSCache(endpoint string) *jwksCache {
+	c := &jwksCache{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		keys:     make(map[string]crypto.PublicKey),
+		stopCh:   make(chan struct{}),
+	}
+
+	if err := c.refresh(); err != nil {
+		log.Warn().Err(err).Str("endpoint", endpoint).Msg("iam: initial JWKS fetch failed")
+	}
+
+	go c.refreshLoop()
+	return c
+}
+
+func (c *jwksCache) refreshLoop() {
+	ticker := time.NewTicker(jwksRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			if err := c.refresh(); err != nil {
+				log.Warn().Err(err).Str("endpoint", c.endpoint).Msg("iam: periodic JWKS refresh failed")
+			}
+		}
+	}
+}
+
+// Stop halts the background refresh loop. IAMAuth keeps its jwksCache for
+// the process lifetime, so this mainly matters for tests.
+func (c *jwksCache) Stop() {
+	c.stopOnce.Do(func() { close(c.stopCh) })
+}
+
+// key returns the public key for kid, triggering a debounced on-demand
+// refresh if kid isn't currently known.
+func (c *jwksCache) key(kid string) (crypto.PublicKey, error) {
+	c.mu.RLock()
+	key, ok := c.keys[kid]
+	c.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if !c.tryOnDemandRefresh() {
+		return nil, errJWKSUnknownKid
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if key, ok = c.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, errJWKSUnknownKid
+}
+
+func (c *jwksCache) tryOnDemandRefresh() bool {
+	c.lastFetchMu.Lock()
+	if time.Since(c.lastFetch) < jwksMinRefreshInterval {
+		c.lastFetchMu.Unlock()
+		return false
+	}
+	c.lastFetchMu.Unlock()
+
+	if err := c.refresh(); err != nil {
+		log.Warn().Err(err).Str("endpoint", c.endpoint).Msg("iam: on-demand JWKS refresh failed")
+		return false
+	}
+	return true
+}
+
+func (c *jwksCache) refresh() error {
+	c.lastFetchMu.Lock()
+	c.lastFetch = time.Now()
+	c.lastFetchMu.Unlock()
+
+	req, err := http.NewRequest(http.MethodGet, c.endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks endpoint returned %d", resp.StatusCode)
+	}
+
+	var doc jwksDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			log.Warn().Err(err).Str("kid", k.Kid).Msg("iam: skipping unparseable JWK")
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	c.mu.Lock()
+	c.keys = keys
+	c.mu.Unlock()
+	return nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported EC curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokenReviewAPIVersion is the authentication.k8s.io API version this
+// handler speaks. kube-apiserver's webhook token authenticator (and any
+// other client implementing the same protocol) negotiates no higher.
+const tokenReviewAPIVersion = "authentication.k8s.io/v1"
+
+// tokenReviewRequest is the subset of a TokenReview object this handler
+// reads. kube-apiserver only ever sets spec.token.
+type tokenReviewRequest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Spec       struct {
+		Token string `json:"token"`
+	} `json:"spec"`
+}
+
+// tokenReviewResponse mirrors k8s.io/api/authentication/v1.TokenReview's
+// wire shape closely enough for kube-apiserver (or anything else speaking
+// the webhook token authenticator protocol) to parse it directly.
+type tokenReviewResponse struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Status     tokenReviewStatus `json:"status"`
+}
+
+type tokenReviewStatus struct {
+	Authenticated bool             `json:"authenticated"`
+	User          *tokenReviewUser `json:"user,omitempty"`
+	Error         string           `json:"error,omitempty"`
+}
+
+type tokenReviewUser struct {
+	Username string              `json:"username"`
+	UID      string              `json:"uid"`
+	Groups   []string            `json:"groups,omitempty"`
+	Extra    map[string][]string `json:"extra,omitempty"`
+}
+
+// TokenReviewHandler validates a token the same way IAMAuth does (local JWT
+// verification against the configured JWKS, falling back to
+// /api/userinfo) and returns the result as a Kubernetes TokenReview, so the
+// control plane can be pointed at directly as kube-apiserver's
+// --authentication-token-webhook-config-file backend, or any other service
+// that already speaks the TokenReview protocol, without bespoke
+// integration against /api/userinfo.
+func TokenReviewHandler(iamConfig IAMConfig) gin.HandlerFunc {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	var jwks *jwksCache
+	if iamConfig.JWKSEndpoint != "" {
+		jwks = newJWKSCache(iamConfig.JWKSEndpoint)
+	}
+
+	return func(c *gin.Context) {
+		var req tokenReviewRequest
+		if err := c.ShouldBindJSON(&req); err != nil || req.Spec.Token == "" {
+			c.JSON(http.StatusBadRequest, tokenReviewResponse{
+				APIVersion: tokenReviewAPIVersion,
+				Kind:       "TokenReview",
+				Status:     tokenReviewStatus{Authenticated: false, Error: "spec.token is required"},
+			})
+			return
+		}
+
+		userInfo, err := resolveIAMUser(client, jwks, iamConfig, req.Spec.Token)
+		if err != nil {
+			c.JSON(http.StatusOK, tokenReviewResponse{
+				APIVersion: tokenReviewAPIVersion,
+				Kind:       "TokenReview",
+				Status:     tokenReviewStatus{Authenticated: false, Error: err.Error()},
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, tokenReviewResponse{
+			APIVersion: tokenReviewAPIVersion,
+			Kind:       "TokenReview",
+			Status: tokenReviewStatus{
+				Authenticated: true,
+				User:          tokenReviewUserFromIAM(userInfo),
+			},
+		})
+	}
+}
+
+// tokenReviewUserFromIAM maps an IAMUserInfo onto a TokenReview user, the
+// same way RBAC systems built on kube-apiserver expect: IsAdmin or
+// IsGlobalAdmin becomes membership in the conventional "system:masters" superuser group, and the
+// user's organization is surfaced as the "org" extra attribute so an
+// Authorizer can key on it.
+func tokenReviewUserFromIAM(user *IAMUserInfo) *tokenReviewUser {
+	groups := []string{"system:authenticated"}
+	if user.IsAdmin || user.IsGlobalAdmin {
+		groups = append(groups, "system:masters")
+	}
+
+	out := &tokenReviewUser{
+		Username: user.Email,
+		UID:      user.ID,
+		Groups:   groups,
+	}
+	if user.Owner != "" {
+		out.Extra = map[string][]string{"org": {user.Owner}}
+	}
+	return out
+}
+
+// Authorizer decides whether a request is permitted after authentication
+// has already succeeded, mirroring Kubernetes' SubjectAccessReview webhook:
+// given the authenticated user and the request it's about to make, it
+// reports whether access is allowed.
+type Authorizer interface {
+	Authorize(user *IAMUserInfo, path, method string) (allowed bool, reason string)
+}
+
+// RBACRule grants access to requests whose path starts with PathPrefix and
+// whose method is Method (or "*" for any method), for users in Org (or "*"
+// for any organization).
+type RBACRule struct {
+	Org        string
+	PathPrefix string
+	Method     string
+}
+
+func (r RBACRule) matches(org, path, method string) bool {
+	if r.Org != "*" && r.Org != org {
+		return false
+	}
+	if !strings.HasPrefix(path, r.PathPrefix) {
+		return false
+	}
+	if r.Method != "*" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	return true
+}
+
+// RBACAuthorizer is the default Authorizer: a request is allowed if any
+// configured rule matches the user's organization, the request path, and
+// method. Global admins (system:masters, per tokenReviewUserFromIAM) always
+// pass regardless of rules.
+type RBACAuthorizer struct {
+	Rules []RBACRule
+}
+
+// NewRBACAuthorizer builds an RBACAuthorizer from config-loaded rules.
+func NewRBACAuthorizer(rules []RBACRule) *RBACAuthorizer {
+	return &RBACAuthorizer{Rules: rules}
+}
+
+func (a *RBACAuthorizer) Authorize(user *IAMUserInfo, path, method string) (bool, string) {
+	if user == nil {
+		return false, "no authenticated user"
+	}
+	if user.IsAdmin || user.IsGlobalAdmin {
+		return true, "admin"
+	}
+	for _, rule := range a.Rules {
+		if rule.matches(user.Owner, path, method) {
+			return true, "matched rule"
+		}
+	}
+	return false, "no matching rule for org"
+}
+
+// WithAuthorizer wraps an existing CombinedAuth-style middleware, invoking
+// authorizer.Authorize once IAM authentication has set a user in context.
+// Requests authenticated via API key (no IAM user in context) or to paths
+// CombinedAuth already let through are not subject to authorization here.
+func WithAuthorizer(authorizer Authorizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := GetIAMUser(c)
+		if user == nil {
+			c.Next()
+			return
+		}
+
+		allowed, reason := authorizer.Authorize(user, c.Request.URL.Path, c.Request.Method)
+		if !allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "forbidden",
+				"message": reason,
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OAuthState is the server-side record RegisterAuthRoutes binds to a single
+// /auth/login attempt's random `state` value, so /auth/callback can recover
+// the PKCE verifier, the redirect URI used in the authorize request, and the
+// originally requested return path without trusting anything the browser
+// sends beyond the opaque state token itself.
+type OAuthState struct {
+	CodeVerifier string
+	RedirectURI  string
+	ReturnTo     string
+	CreatedAt    time.Time
+}
+
+// StateStore persists OAuthStates between /auth/login and /auth/callback.
+// Entries are single-use: Consume both looks up and deletes in one step, so
+// a replayed state value can never succeed twice.
+type StateStore interface {
+	// Create records data under state, expiring after ttl.
+	Create(ctx context.Context, state string, data OAuthState, ttl time.Duration) error
+	// Consume looks up and deletes state in one step. Returns nil, nil if
+	// state is unknown or has expired.
+	Consume(ctx context.Context, state string) (*OAuthState, error)
+}
+
+// NewOAuthState generates a cryptographically random state token and PKCE
+// code verifier for a single /auth/login attempt. Both are 32 raw random
+// bytes, base64url-encoded to 43 characters - within the 43-128 character
+// range RFC 7636 requires of a code verifier.
+func NewOAuthState() (state, verifier string, err error) {
+	state, err = randomURLSafe(32)
+	if err != nil {
+		return "", "", fmt.Errorf("middleware: failed to generate oauth state: %w", err)
+	}
+	verifier, err = randomURLSafe(32)
+	if err != nil {
+		return "", "", fmt.Errorf("middleware: failed to generate pkce verifier: %w", err)
+	}
+	return state, verifier, nil
+}
+
+func randomURLSafe(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+type stateEntry struct {
+	data      OAuthState
+	expiresAt time.Time
+}
+
+// MemoryStateStore is an in-process StateStore. Like MemorySessionStore, a
+// multi-replica deployment needs a shared backend instead; this is correct
+// for a single replica and is RegisterAuthRoutes' default when no StateStore
+// is supplied.
+type MemoryStateStore struct {
+	mu      sync.Mutex
+	entries map[string]stateEntry
+}
+
+// NewMemoryStateStore creates an empty MemoryStateStore.
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{entries: make(map[string]stateEntry)}
+}
+
+func (s *MemoryStateStore) Create(_ context.Context, state string, data OAuthState, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpiredLocked()
+	s.entries[state] = stateEntry{data: data, expiresAt: time.Now().UTC().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryStateStore) Consume(_ context.Context, state string) (*OAuthState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[state]
+	delete(s.entries, state)
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, nil
+	}
+	data := entry.data
+	return &data, nil
+}
+
+// evictExpiredLocked drops expired entries that were never consumed (e.g. an
+// abandoned login attempt), so entries doesn't grow without bound. Callers
+// must hold mu.
+func (s *MemoryStateStore) evictExpiredLocked() {
+	now := time.Now().UTC()
+	for state, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, state)
+		}
+	}
+}
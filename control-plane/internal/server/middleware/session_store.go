@@ -0,0 +1,272 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Session records one authenticated browser/device, keyed by an opaque
+// session ID carried in the hanzo_agents_session cookie - never the raw
+// IAM access token, so a leaked cookie can be revoked without knowing, or
+// needing to invalidate, the token itself.
+type Session struct {
+	ID     string
+	UserID string
+	// Token is the underlying IAM access token this session wraps. IAMAuth
+	// resolves a session cookie to this before validating it as normal.
+	Token string
+	// RefreshToken and IDToken are the other two members of the token set
+	// IAM issued alongside Token. RefreshToken lets IAMAuth transparently
+	// rotate Token as ExpiresAt approaches instead of forcing the user back
+	// through /auth/login; IDToken is carried through unused today but kept
+	// alongside the rest of the set for whatever IAM-side revocation or
+	// identity checks need it later.
+	RefreshToken string
+	IDToken      string
+	// CSRFSecret is generated once at Create and never rotated. Callers
+	// authenticated via this session's cookie must echo it back in an
+	// X-CSRF-Token header on state-changing requests; see the CSRF check in
+	// CombinedAuth.
+	CSRFSecret string
+	IP         string
+	Device     DeviceInfo
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+// SessionTokens bundles the IAM token set a session wraps, so Create and
+// Rotate don't need an ever-growing list of individual string parameters.
+type SessionTokens struct {
+	AccessToken  string
+	RefreshToken string
+	IDToken      string
+}
+
+// DeviceInfo is parsed from a session's User-Agent header at login time,
+// so a "your devices" view can show something more useful than a raw UA
+// string.
+type DeviceInfo struct {
+	OS       string `json:"os"`
+	Browser  string `json:"browser"`
+	Device   string `json:"device"`
+	IsMobile bool   `json:"is_mobile"`
+}
+
+// parseDeviceInfo derives a best-effort DeviceInfo from a User-Agent
+// header. It recognizes the handful of OS/browser substrings that cover
+// the overwhelming majority of real traffic rather than fully parsing UA
+// grammar, which has no single authoritative spec and changes constantly.
+func parseDeviceInfo(ua string) DeviceInfo {
+	lower := strings.ToLower(ua)
+	info := DeviceInfo{OS: "unknown", Browser: "unknown", Device: "desktop"}
+
+	switch {
+	case strings.Contains(lower, "windows"):
+		info.OS = "windows"
+	case strings.Contains(lower, "iphone"), strings.Contains(lower, "ipad"):
+		info.OS = "ios"
+	case strings.Contains(lower, "mac os"), strings.Contains(lower, "macintosh"):
+		info.OS = "macos"
+	case strings.Contains(lower, "android"):
+		info.OS = "android"
+	case strings.Contains(lower, "linux"):
+		info.OS = "linux"
+	}
+
+	switch {
+	case strings.Contains(lower, "edg/"):
+		info.Browser = "edge"
+	case strings.Contains(lower, "chrome/"):
+		info.Browser = "chrome"
+	case strings.Contains(lower, "firefox/"):
+		info.Browser = "firefox"
+	case strings.Contains(lower, "safari/") && !strings.Contains(lower, "chrome/"):
+		info.Browser = "safari"
+	}
+
+	info.IsMobile = strings.Contains(lower, "mobile") || strings.Contains(lower, "android") || strings.Contains(lower, "iphone")
+	switch {
+	case info.IsMobile:
+		info.Device = "mobile"
+	case strings.Contains(lower, "ipad") || strings.Contains(lower, "tablet"):
+		info.Device = "tablet"
+	}
+
+	return info
+}
+
+// SessionStore persists Sessions and their revocation state. IAMAuth
+// consults it (when IAMConfig.Sessions is set) to resolve a session
+// cookie to its underlying token and to reject revoked sessions before
+// trusting a cached validation result.
+type SessionStore interface {
+	// Create records a new session for userID, deriving Device from
+	// userAgent and generating a fresh CSRFSecret, and returns it with a
+	// freshly generated ID.
+	Create(ctx context.Context, userID string, tokens SessionTokens, ip, userAgent string, ttl time.Duration) (*Session, error)
+	// Get looks up a session by ID. Returns nil, nil if not found, expired,
+	// or revoked.
+	Get(ctx context.Context, sessionID string) (*Session, error)
+	// Touch updates a session's LastSeenAt to now.
+	Touch(ctx context.Context, sessionID string) error
+	// Rotate replaces a session's token set and extends ExpiresAt by ttl,
+	// for IAMAuth's transparent refresh-token grant. CSRFSecret, UserID, and
+	// the other identifying fields are left untouched.
+	Rotate(ctx context.Context, sessionID string, tokens SessionTokens, ttl time.Duration) error
+	// Revoke invalidates a session, so Get and IsRevoked both reflect it
+	// can no longer be used. Implementations that back multiple replicas
+	// (RedisSessionStore) also broadcast the revocation so every
+	// replica's tokenCache evicts it.
+	Revoke(ctx context.Context, sessionID string) error
+	// IsRevoked reports whether sessionID has been revoked.
+	IsRevoked(ctx context.Context, sessionID string) (bool, error)
+	// List returns every live (non-revoked, non-expired) session for
+	// userID, for a "your devices" view.
+	List(ctx context.Context, userID string) ([]*Session, error)
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// newCSRFSecret generates a per-session CSRF token, the same shape as a
+// session ID but a distinct value so leaking one doesn't leak the other.
+func newCSRFSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// MemorySessionStore is an in-process SessionStore. It's correct for a
+// single-replica deployment or tests, but a Revoke on one process is
+// invisible to others; use RedisSessionStore wherever sessions must be
+// revocable across replicas.
+type MemorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+	revoked  map[string]time.Time // sessionID -> original ExpiresAt, evicted lazily
+}
+
+// NewMemorySessionStore creates an empty MemorySessionStore.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]*Session),
+		revoked:  make(map[string]time.Time),
+	}
+}
+
+func (s *MemorySessionStore) Create(_ context.Context, userID string, tokens SessionTokens, ip, userAgent string, ttl time.Duration) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to generate session id: %w", err)
+	}
+	csrfSecret, err := newCSRFSecret()
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to generate csrf secret: %w", err)
+	}
+
+	now := time.Now().UTC()
+	sess := &Session{
+		ID:           id,
+		UserID:       userID,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		IDToken:      tokens.IDToken,
+		CSRFSecret:   csrfSecret,
+		IP:           ip,
+		Device:       parseDeviceInfo(userAgent),
+		CreatedAt:    now,
+		LastSeenAt:   now,
+		ExpiresAt:    now.Add(ttl),
+	}
+
+	s.mu.Lock()
+	s.sessions[id] = sess
+	s.mu.Unlock()
+	return sess, nil
+}
+
+func (s *MemorySessionStore) Get(_ context.Context, sessionID string) (*Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, ok := s.sessions[sessionID]
+	if !ok || time.Now().After(sess.ExpiresAt) {
+		return nil, nil
+	}
+	copied := *sess
+	return &copied, nil
+}
+
+func (s *MemorySessionStore) Touch(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, ok := s.sessions[sessionID]; ok {
+		sess.LastSeenAt = time.Now().UTC()
+	}
+	return nil
+}
+
+func (s *MemorySessionStore) Rotate(_ context.Context, sessionID string, tokens SessionTokens, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("middleware: session %s not found", sessionID)
+	}
+	sess.Token = tokens.AccessToken
+	sess.RefreshToken = tokens.RefreshToken
+	sess.IDToken = tokens.IDToken
+	sess.ExpiresAt = time.Now().UTC().Add(ttl)
+	return nil
+}
+
+func (s *MemorySessionStore) Revoke(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, expiresAt := range s.revoked {
+		if now.After(expiresAt) {
+			delete(s.revoked, id)
+		}
+	}
+	if sess, ok := s.sessions[sessionID]; ok {
+		s.revoked[sessionID] = sess.ExpiresAt
+		delete(s.sessions, sessionID)
+	}
+	return nil
+}
+
+func (s *MemorySessionStore) IsRevoked(_ context.Context, sessionID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.revoked[sessionID]
+	return ok, nil
+}
+
+func (s *MemorySessionStore) List(_ context.Context, userID string) ([]*Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now().UTC()
+	var out []*Session
+	for _, sess := range s.sessions {
+		if sess.UserID == userID && now.Before(sess.ExpiresAt) {
+			copied := *sess
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// postgresSessionsSchema creates the sessions table if it doesn't already
+// exist. NewPostgresSessionStore runs it once at construction, the same way
+// CredentialResolver and MemoryStateStore are each self-sufficient rather
+// than relying on an external migration step.
+const postgresSessionsSchema = `
+CREATE TABLE IF NOT EXISTS hanzo_sessions (
+	id            TEXT PRIMARY KEY,
+	user_id       TEXT NOT NULL,
+	token         TEXT NOT NULL,
+	refresh_token TEXT NOT NULL DEFAULT '',
+	id_token      TEXT NOT NULL DEFAULT '',
+	csrf_secret   TEXT NOT NULL,
+	ip            TEXT NOT NULL DEFAULT '',
+	device        JSONB NOT NULL DEFAULT '{}',
+	created_at    TIMESTAMPTZ NOT NULL,
+	last_seen_at  TIMESTAMPTZ NOT NULL,
+	expires_at    TIMESTAMPTZ NOT NULL,
+	revoked_at    TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS hanzo_sessions_user_id_idx ON hanzo_sessions (user_id);
+`
+
+// PostgresSessionStore is a SessionStore backed by a Postgres table, for
+// deployments that already run Postgres and would rather not stand up Redis
+// just for session storage. Unlike RedisSessionStore, a Revoke here isn't
+// broadcast to other replicas' tokenCache - Postgres has no built-in pub/sub
+// client vendored in this build - so another replica keeps the revoked
+// session's cache entry until tokenCacheTTL lapses. The revocation itself
+// still takes effect on every replica at once: IAMAuth looks the session up
+// in this shared table before it consults tokenCache, and Get returns nil
+// for a revoked row.
+type PostgresSessionStore struct {
+	db *sql.DB
+}
+
+// NewPostgresSessionStore creates the sessions table (if missing) on db and
+// returns a PostgresSessionStore over it.
+func NewPostgresSessionStore(ctx context.Context, db *sql.DB) (*PostgresSessionStore, error) {
+	if _, err := db.ExecContext(ctx, postgresSessionsSchema); err != nil {
+		return nil, fmt.Errorf("middleware: failed to prepare sessions table: %w", err)
+	}
+	return &PostgresSessionStore{db: db}, nil
+}
+
+func (s *PostgresSessionStore) Create(ctx context.Context, userID string, tokens SessionTokens, ip, userAgent string, ttl time.Duration) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to generate session id: %w", err)
+	}
+	csrfSecret, err := newCSRFSecret()
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to generate csrf secret: %w", err)
+	}
+
+	now := time.Now().UTC()
+	sess := &Session{
+		ID:           id,
+		UserID:       userID,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		IDToken:      tokens.IDToken,
+		CSRFSecret:   csrfSecret,
+		IP:           ip,
+		Device:       parseDeviceInfo(userAgent),
+		CreatedAt:    now,
+		LastSeenAt:   now,
+		ExpiresAt:    now.Add(ttl),
+	}
+
+	device, err := json.Marshal(sess.Device)
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to marshal device info: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO hanzo_sessions
+			(id, user_id, token, refresh_token, id_token, csrf_secret, ip, device, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		sess.ID, sess.UserID, sess.Token, sess.RefreshToken, sess.IDToken, sess.CSRFSecret,
+		sess.IP, device, sess.CreatedAt, sess.LastSeenAt, sess.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to insert session: %w", err)
+	}
+	return sess, nil
+}
+
+func (s *PostgresSessionStore) scanSession(row *sql.Row) (*Session, error) {
+	var sess Session
+	var device []byte
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&sess.ID, &sess.UserID, &sess.Token, &sess.RefreshToken, &sess.IDToken, &sess.CSRFSecret,
+		&sess.IP, &device, &sess.CreatedAt, &sess.LastSeenAt, &sess.ExpiresAt, &revokedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to scan session: %w", err)
+	}
+	if revokedAt.Valid || time.Now().After(sess.ExpiresAt) {
+		return nil, nil
+	}
+	if err := json.Unmarshal(device, &sess.Device); err != nil {
+		return nil, fmt.Errorf("middleware: failed to unmarshal device info: %w", err)
+	}
+	return &sess, nil
+}
+
+func (s *PostgresSessionStore) Get(ctx context.Context, sessionID string) (*Session, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, user_id, token, refresh_token, id_token, csrf_secret, ip, device, created_at, last_seen_at, expires_at, revoked_at
+		FROM hanzo_sessions WHERE id = $1`, sessionID)
+	return s.scanSession(row)
+}
+
+func (s *PostgresSessionStore) Touch(ctx context.Context, sessionID string) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE hanzo_sessions SET last_seen_at = $1 WHERE id = $2`, time.Now().UTC(), sessionID)
+	return err
+}
+
+func (s *PostgresSessionStore) Rotate(ctx context.Context, sessionID string, tokens SessionTokens, ttl time.Duration) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE hanzo_sessions
+		SET token = $1, refresh_token = $2, id_token = $3, expires_at = $4
+		WHERE id = $5`,
+		tokens.AccessToken, tokens.RefreshToken, tokens.IDToken, time.Now().UTC().Add(ttl), sessionID,
+	)
+	if err != nil {
+		return fmt.Errorf("middleware: failed to rotate session: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("middleware: session %s not found", sessionID)
+	}
+	return nil
+}
+
+func (s *PostgresSessionStore) Revoke(ctx context.Context, sessionID string) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE hanzo_sessions SET revoked_at = $1 WHERE id = $2`, time.Now().UTC(), sessionID)
+	return err
+}
+
+func (s *PostgresSessionStore) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	var revokedAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, `SELECT revoked_at FROM hanzo_sessions WHERE id = $1`, sessionID).Scan(&revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return revokedAt.Valid, nil
+}
+
+func (s *PostgresSessionStore) List(ctx context.Context, userID string) ([]*Session, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, token, refresh_token, id_token, csrf_secret, ip, device, created_at, last_seen_at, expires_at, revoked_at
+		FROM hanzo_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2`, userID, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Session
+	for rows.Next() {
+		var sess Session
+		var device []byte
+		var revokedAt sql.NullTime
+		if err := rows.Scan(
+			&sess.ID, &sess.UserID, &sess.Token, &sess.RefreshToken, &sess.IDToken, &sess.CSRFSecret,
+			&sess.IP, &device, &sess.CreatedAt, &sess.LastSeenAt, &sess.ExpiresAt, &revokedAt,
+		); err != nil {
+			return nil, fmt.Errorf("middleware: failed to scan session: %w", err)
+		}
+		if err := json.Unmarshal(device, &sess.Device); err != nil {
+			return nil, fmt.Errorf("middleware: failed to unmarshal device info: %w", err)
+		}
+		out = append(out, &sess)
+	}
+	return out, rows.Err()
+}
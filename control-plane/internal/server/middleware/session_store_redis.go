@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// sessionRevocationChannel is the pub/sub channel RedisSessionStore uses to
+// tell every replica's tokenCache to evict a revoked session, independent
+// of each replica's own local cache TTL.
+const sessionRevocationChannel = "hanzo:session-revocations"
+
+// RedisClient is the minimal subset of a Redis client RedisSessionStore
+// needs: get/set/delete for session records, plus pub/sub for revocation
+// broadcast. It's defined here rather than importing a concrete driver
+// (go-redis, redigo, ...) since none is vendored in this build; callers
+// wire up whichever driver they use behind this interface, the same way
+// internal/cloud/sinks injects Publisher/MQTTPublisher for transports this
+// build doesn't vendor a client for.
+type RedisClient interface {
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Del(ctx context.Context, key string) error
+	Publish(ctx context.Context, channel string, message []byte) error
+	// Subscribe returns a channel of raw messages published to channel.
+	// The returned channel is closed when ctx is done.
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+}
+
+// RedisSessionStore is a SessionStore backed by Redis, so sessions and
+// their revocation state are visible across every replica of the control
+// plane rather than only the process that created them.
+type RedisSessionStore struct {
+	client RedisClient
+}
+
+// NewRedisSessionStore creates a RedisSessionStore over client and starts
+// a background subscriber that evicts tokenCache whenever any replica
+// (including this one) revokes a session.
+func NewRedisSessionStore(ctx context.Context, client RedisClient) (*RedisSessionStore, error) {
+	s := &RedisSessionStore{client: client}
+
+	msgs, err := client.Subscribe(ctx, sessionRevocationChannel)
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to subscribe to session revocations: %w", err)
+	}
+	go s.watchRevocations(msgs)
+
+	return s, nil
+}
+
+func (s *RedisSessionStore) watchRevocations(msgs <-chan []byte) {
+	for msg := range msgs {
+		sessionID := string(msg)
+		tokenCache.Delete(sessionID)
+		log.Debug().Str("session_id", sessionID).Msg("session revoked, evicted from local token cache")
+	}
+}
+
+func sessionKey(id string) string { return "session:" + id }
+
+func (s *RedisSessionStore) Create(ctx context.Context, userID string, tokens SessionTokens, ip, userAgent string, ttl time.Duration) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to generate session id: %w", err)
+	}
+	csrfSecret, err := newCSRFSecret()
+	if err != nil {
+		return nil, fmt.Errorf("middleware: failed to generate csrf secret: %w", err)
+	}
+
+	now := time.Now().UTC()
+	sess := &Session{
+		ID:           id,
+		UserID:       userID,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		IDToken:      tokens.IDToken,
+		CSRFSecret:   csrfSecret,
+		IP:           ip,
+		Device:       parseDeviceInfo(userAgent),
+		CreatedAt:    now,
+		LastSeenAt:   now,
+		ExpiresAt:    now.Add(ttl),
+	}
+
+	if err := s.put(ctx, sess, ttl); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+func (s *RedisSessionStore) put(ctx context.Context, sess *Session, ttl time.Duration) error {
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("middleware: failed to marshal session: %w", err)
+	}
+	return s.client.Set(ctx, sessionKey(sess.ID), data, ttl)
+}
+
+func (s *RedisSessionStore) Get(ctx context.Context, sessionID string) (*Session, error) {
+	data, err := s.client.Get(ctx, sessionKey(sessionID))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var sess Session
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return nil, fmt.Errorf("middleware: failed to unmarshal session: %w", err)
+	}
+	if time.Now().After(sess.ExpiresAt) {
+		return nil, nil
+	}
+	return &sess, nil
+}
+
+func (s *RedisSessionStore) Touch(ctx context.Context, sessionID string) error {
+	sess, err := s.Get(ctx, sessionID)
+	if err != nil || sess == nil {
+		return err
+	}
+	sess.LastSeenAt = time.Now().UTC()
+	return s.put(ctx, sess, time.Until(sess.ExpiresAt))
+}
+
+func (s *RedisSessionStore) Rotate(ctx context.Context, sessionID string, tokens SessionTokens, ttl time.Duration) error {
+	sess, err := s.Get(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if sess == nil {
+		return fmt.Errorf("middleware: session %s not found", sessionID)
+	}
+	sess.Token = tokens.AccessToken
+	sess.RefreshToken = tokens.RefreshToken
+	sess.IDToken = tokens.IDToken
+	sess.ExpiresAt = time.Now().UTC().Add(ttl)
+	return s.put(ctx, sess, ttl)
+}
+
+func (s *RedisSessionStore) Revoke(ctx context.Context, sessionID string) error {
+	if err := s.client.Del(ctx, sessionKey(sessionID)); err != nil {
+		return err
+	}
+	return s.client.Publish(ctx, sessionRevocationChannel, []byte(sessionID))
+}
+
+// IsRevoked reports whether sessionID is absent from the store: Revoke
+// deletes the key outright rather than tombstoning it, since the pub/sub
+// broadcast (not a tombstone read) is what evicts other replicas' caches.
+func (s *RedisSessionStore) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	sess, err := s.Get(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	return sess == nil, nil
+}
+
+// List is unimplemented for RedisSessionStore: Redis has no native
+// secondary index, and this build has no vendored Redis client to build
+// one (e.g. a SCAN + per-user set) against. A deployment that needs
+// GET /api/v1/auth/sessions backed by Redis should maintain a
+// "session:user:<id>" set alongside the session keys at Create/Revoke
+// time; left as a TODO until a concrete Redis driver is vendored.
+func (s *RedisSessionStore) List(_ context.Context, _ string) ([]*Session, error) {
+	return nil, fmt.Errorf("middleware: RedisSessionStore.List is not implemented")
+}
@@ -0,0 +1,255 @@
+package middleware
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultSTSEndpoint is used when SigV4Config.STSEndpoint is unset.
+const defaultSTSEndpoint = "https://sts.amazonaws.com"
+
+// defaultMaxPresignedURLExpiry bounds how long a presigned GetCallerIdentity
+// URL is accepted for when SigV4Config.MaxPresignedURLExpiry is unset.
+const defaultMaxPresignedURLExpiry = 15 * time.Minute
+
+// presignedURLHeader carries a presigned sts:GetCallerIdentity URL the
+// caller generated with their own AWS credentials, for the "shareable
+// link" flow: anyone holding the URL can authenticate with it until it
+// expires, without ever handling a long-lived API key.
+const presignedURLHeader = "X-Amz-Caller-Identity-Url"
+
+// ARNMapping maps an IAM ARN (optionally with * and ? wildcards, matched
+// the same way path.Match matches path segments) to the internal principal
+// SigV4Auth sets as the authenticated user. Mappings are tried in order;
+// the first match wins.
+type ARNMapping struct {
+	ARNPattern string
+	UserID     string
+}
+
+// SigV4Config holds configuration for SigV4Auth.
+type SigV4Config struct {
+	Enabled bool
+	// STSEndpoint is where GetCallerIdentity requests are forwarded.
+	// Defaults to defaultSTSEndpoint.
+	STSEndpoint string
+	// ARNMappings maps the caller ARN STS returns to an internal principal.
+	// An ARN that matches no mapping is rejected.
+	ARNMappings []ARNMapping
+	// MaxPresignedURLExpiry caps how long a presignedURLHeader URL's
+	// X-Amz-Expires may request. Defaults to defaultMaxPresignedURLExpiry.
+	MaxPresignedURLExpiry time.Duration
+}
+
+// CallerIdentity is the subset of sts:GetCallerIdentity's result SigV4Auth
+// needs.
+type CallerIdentity struct {
+	Arn     string
+	Account string
+	UserID  string
+}
+
+// getCallerIdentityResponse mirrors the XML shape of STS's
+// GetCallerIdentity response.
+type getCallerIdentityResponse struct {
+	XMLName xml.Name `xml:"GetCallerIdentityResponse"`
+	Result  struct {
+		Arn     string `xml:"Arn"`
+		Account string `xml:"Account"`
+		UserID  string `xml:"UserId"`
+	} `xml:"GetCallerIdentityResult"`
+}
+
+// sigv4CredentialRe extracts the access key ID from a SigV4 Authorization
+// header's Credential field, e.g.
+// "AWS4-HMAC-SHA256 Credential=AKIAEXAMPLE/20260729/us-east-1/sts/aws4_request, ...".
+// SigV4Auth never needs the access key ID itself (STS resolves identity for
+// it), but a present, well-formed Credential field is what distinguishes a
+// SigV4-signed request from a bearer token or API key sharing the same
+// Authorization header.
+var sigv4CredentialRe = regexp.MustCompile(`Credential=([^/,\s]+)/`)
+
+// SigV4Auth authenticates requests carrying an AWS SigV4-signed
+// Authorization header or a presigned sts:GetCallerIdentity URL
+// (presignedURLHeader), by forwarding the caller's own signature to STS's
+// GetCallerIdentity and mapping the resulting ARN to an internal principal
+// via config.ARNMappings. Like IAMAuth, it does NOT abort on failure - it
+// lets the next middleware (API key or IAM auth) try.
+func SigV4Auth(config SigV4Config) gin.HandlerFunc {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	return func(c *gin.Context) {
+		if !config.Enabled {
+			c.Next()
+			return
+		}
+
+		identity, err := ResolveCallerIdentity(client, config, c.Request)
+		if err != nil || identity == nil {
+			c.Next()
+			return
+		}
+
+		userID, ok := MapARNToUser(config.ARNMappings, identity.Arn)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		c.Set(ContextKeyIAMUserID, userID)
+		c.Set(ContextKeySigV4ARN, identity.Arn)
+		c.Set(ContextKeyAuthMethod, "sigv4")
+		c.Next()
+	}
+}
+
+// ResolveCallerIdentity picks whichever of the two supported SigV4
+// credential forms req carries and forwards it to STS, returning the
+// resulting CallerIdentity. Returns nil, nil if req carries neither form.
+// Exported so callers like /api/v1/auth/userinfo, which resolve identity
+// independently of the SigV4Auth middleware, can reuse the same logic.
+func ResolveCallerIdentity(client *http.Client, config SigV4Config, req *http.Request) (*CallerIdentity, error) {
+	if presignedURL := req.Header.Get(presignedURLHeader); presignedURL != "" {
+		return callerIdentityFromPresignedURL(client, config, presignedURL)
+	}
+	if authHeader := req.Header.Get("Authorization"); strings.HasPrefix(authHeader, "AWS4-HMAC-SHA256 ") {
+		return callerIdentityFromHeaders(client, config, req)
+	}
+	return nil, nil
+}
+
+// callerIdentityFromHeaders forwards the client's SigV4 Authorization
+// header (and its supporting X-Amz-* headers) to STS as a GetCallerIdentity
+// call, exactly as the client signed it - the control plane never sees, and
+// doesn't need, the caller's actual AWS secret key.
+func callerIdentityFromHeaders(client *http.Client, config SigV4Config, req *http.Request) (*CallerIdentity, error) {
+	authHeader := req.Header.Get("Authorization")
+	if !sigv4CredentialRe.MatchString(authHeader) {
+		return nil, fmt.Errorf("sigv4: malformed Authorization header")
+	}
+
+	endpoint := config.STSEndpoint
+	if endpoint == "" {
+		endpoint = defaultSTSEndpoint
+	}
+
+	stsReq, err := http.NewRequest(http.MethodGet, endpoint+"/?Action=GetCallerIdentity&Version=2011-06-15", nil)
+	if err != nil {
+		return nil, err
+	}
+	stsReq.Header.Set("Authorization", authHeader)
+	if d := req.Header.Get("X-Amz-Date"); d != "" {
+		stsReq.Header.Set("X-Amz-Date", d)
+	}
+	if t := req.Header.Get("X-Amz-Security-Token"); t != "" {
+		stsReq.Header.Set("X-Amz-Security-Token", t)
+	}
+
+	return doGetCallerIdentity(client, stsReq)
+}
+
+// callerIdentityFromPresignedURL validates and replays a presigned
+// sts:GetCallerIdentity URL the client generated with their own
+// credentials - the "shareable link" flow. rawURL must target
+// config.STSEndpoint, name the GetCallerIdentity action, and carry an
+// X-Amz-Expires no larger than config.MaxPresignedURLExpiry, so a leaked
+// link can't be replayed indefinitely.
+func callerIdentityFromPresignedURL(client *http.Client, config SigV4Config, rawURL string) (*CallerIdentity, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("sigv4: invalid presigned url: %w", err)
+	}
+
+	endpoint := config.STSEndpoint
+	if endpoint == "" {
+		endpoint = defaultSTSEndpoint
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("sigv4: invalid configured STS endpoint: %w", err)
+	}
+	if parsed.Host != endpointURL.Host {
+		return nil, fmt.Errorf("sigv4: presigned url host %q does not match configured STS endpoint", parsed.Host)
+	}
+
+	query := parsed.Query()
+	if query.Get("Action") != "GetCallerIdentity" {
+		return nil, fmt.Errorf("sigv4: presigned url is not a GetCallerIdentity request")
+	}
+
+	maxExpiry := config.MaxPresignedURLExpiry
+	if maxExpiry <= 0 {
+		maxExpiry = defaultMaxPresignedURLExpiry
+	}
+	expiresSeconds, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+	if err != nil || expiresSeconds <= 0 {
+		return nil, fmt.Errorf("sigv4: presigned url missing a valid X-Amz-Expires")
+	}
+	if time.Duration(expiresSeconds)*time.Second > maxExpiry {
+		return nil, fmt.Errorf("sigv4: presigned url X-Amz-Expires exceeds the %s maximum", maxExpiry)
+	}
+
+	signedAt, err := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+	if err != nil {
+		return nil, fmt.Errorf("sigv4: presigned url missing a valid X-Amz-Date")
+	}
+	if time.Now().After(signedAt.Add(time.Duration(expiresSeconds) * time.Second)) {
+		return nil, fmt.Errorf("sigv4: presigned url has expired")
+	}
+
+	stsReq, err := http.NewRequest(http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return doGetCallerIdentity(client, stsReq)
+}
+
+// doGetCallerIdentity sends stsReq and parses its GetCallerIdentity
+// response.
+func doGetCallerIdentity(client *http.Client, stsReq *http.Request) (*CallerIdentity, error) {
+	resp, err := client.Do(stsReq)
+	if err != nil {
+		return nil, fmt.Errorf("sigv4: failed to reach STS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sigv4: STS rejected the signed request with status %d", resp.StatusCode)
+	}
+
+	var parsed getCallerIdentityResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("sigv4: failed to parse STS response: %w", err)
+	}
+	if parsed.Result.Arn == "" {
+		return nil, fmt.Errorf("sigv4: STS response had no caller ARN")
+	}
+
+	return &CallerIdentity{
+		Arn:     parsed.Result.Arn,
+		Account: parsed.Result.Account,
+		UserID:  parsed.Result.UserID,
+	}, nil
+}
+
+// MapARNToUser returns the internal principal the first matching mapping
+// names for arn, trying mappings in order. ARNPattern is matched with
+// path.Match, so "arn:aws:iam::123456789012:role/*" matches any role in
+// that account.
+func MapARNToUser(mappings []ARNMapping, arn string) (string, bool) {
+	for _, m := range mappings {
+		if matched, err := path.Match(m.ARNPattern, arn); err == nil && matched {
+			return m.UserID, true
+		}
+	}
+	return "", false
+}
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,10 +12,22 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
 	"github.com/hanzoai/agents/control-plane/internal/config"
 	"github.com/hanzoai/agents/control-plane/internal/server/middleware"
 )
 
+// oauthStateCookieName names the short-lived cookie binding a browser to
+// its own /auth/login attempt, so /auth/callback can require the `state`
+// query value to match both this cookie and a live StateStore entry before
+// trusting it.
+const oauthStateCookieName = "oauth_state"
+
+// oauthStateTTL bounds how long an /auth/login attempt's state and PKCE
+// verifier remain redeemable, both in the StateStore and the state cookie.
+const oauthStateTTL = 10 * time.Minute
+
 // IAMTokenResponse represents the response from IAM token endpoint.
 type IAMTokenResponse struct {
 	AccessToken  string `json:"access_token"`
@@ -26,15 +40,28 @@ type IAMTokenResponse struct {
 
 // AuthInfoResponse is returned by /api/v1/auth/userinfo and /api/v1/auth/info.
 type AuthInfoResponse struct {
-	Authenticated bool                  `json:"authenticated"`
-	Method        string                `json:"method,omitempty"`    // "iam" or "api_key"
-	IAMEnabled    bool                  `json:"iam_enabled"`
+	Authenticated bool                    `json:"authenticated"`
+	Method        string                  `json:"method,omitempty"` // "iam", "sigv4", or "api_key"
+	IAMEnabled    bool                    `json:"iam_enabled"`
 	User          *middleware.IAMUserInfo `json:"user,omitempty"`
+	// CallerARN is set when Method is "sigv4": the IAM ARN STS resolved for
+	// the caller's signed request.
+	CallerARN string `json:"caller_arn,omitempty"`
 }
 
 // RegisterAuthRoutes registers OAuth/IAM authentication routes on the router.
 // These routes must be registered BEFORE the auth middleware is applied.
-func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig) {
+// sessions may be nil, in which case the session cookie holds the IAM
+// access token directly (the legacy behavior) and /api/v1/auth/sessions
+// reports itself unavailable. states may also be nil, in which case
+// RegisterAuthRoutes falls back to an in-process middleware.MemoryStateStore;
+// pass a shared backend in any multi-replica deployment. sigV4Config.Enabled
+// may be left false to leave SigV4 authentication unconfigured, in which
+// case /api/v1/auth/userinfo never reports method "sigv4".
+func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig, sessions middleware.SessionStore, states middleware.StateStore, sigV4Config middleware.SigV4Config) {
+	if states == nil {
+		states = middleware.NewMemoryStateStore()
+	}
 	if !authCfg.IAMEnabled {
 		// Even if IAM is disabled, register the info endpoint so the frontend
 		// can discover that IAM is not available.
@@ -55,17 +82,43 @@ func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig) {
 
 	// GET /auth/login - Redirect to IAM authorization page.
 	router.GET("/auth/login", func(c *gin.Context) {
-		// Build the Casdoor authorize URL.
 		redirectURI := buildRedirectURI(c, authCfg)
-		state := fmt.Sprintf("%d", time.Now().UnixNano()) // Simple state for CSRF protection
+
+		state, verifier, err := middleware.NewOAuthState()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "state_generation_failed",
+				"message": "failed to start login",
+			})
+			return
+		}
+		challenge := pkceChallengeS256(verifier)
+		returnTo := sanitizeReturnTo(c.Query("return_to"))
+
+		if err := states.Create(c.Request.Context(), state, middleware.OAuthState{
+			CodeVerifier: verifier,
+			RedirectURI:  redirectURI,
+			ReturnTo:     returnTo,
+			CreatedAt:    time.Now().UTC(),
+		}, oauthStateTTL); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "state_store_failed",
+				"message": "failed to start login",
+			})
+			return
+		}
+
+		secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+		c.SetCookie(oauthStateCookieName, state, int(oauthStateTTL.Seconds()), "/auth", "", secure, true)
 
 		authorizeURL := fmt.Sprintf(
-			"%s/login/oauth/authorize?client_id=%s&response_type=code&redirect_uri=%s&scope=%s&state=%s",
+			"%s/login/oauth/authorize?client_id=%s&response_type=code&redirect_uri=%s&scope=%s&state=%s&code_challenge=%s&code_challenge_method=S256",
 			publicEndpoint,
 			url.QueryEscape(authCfg.IAMClientID),
 			url.QueryEscape(redirectURI),
 			url.QueryEscape("openid profile email"),
 			url.QueryEscape(state),
+			url.QueryEscape(challenge),
 		)
 
 		c.Redirect(http.StatusFound, authorizeURL)
@@ -82,16 +135,41 @@ func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig) {
 			return
 		}
 
+		// The state query value must match both the oauth_state cookie and a
+		// live, not-yet-consumed StateStore entry - a mismatch or a replayed
+		// state (Consume is single-use) means this isn't a response to a
+		// login we actually initiated.
+		stateParam := c.Query("state")
+		cookieState, cookieErr := c.Cookie(oauthStateCookieName)
+		c.SetCookie(oauthStateCookieName, "", -1, "/auth", "", false, true)
+
+		if stateParam == "" || cookieErr != nil || cookieState == "" || stateParam != cookieState {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_state",
+				"message": "oauth state mismatch",
+			})
+			return
+		}
+
+		oauthState, err := states.Consume(c.Request.Context(), stateParam)
+		if err != nil || oauthState == nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_state",
+				"message": "oauth state expired or already used",
+			})
+			return
+		}
+
 		// Exchange code for tokens using the internal IAM endpoint.
 		tokenURL := internalEndpoint + "/api/login/oauth/access_token"
-		redirectURI := buildRedirectURI(c, authCfg)
 
 		formData := url.Values{
 			"grant_type":    {"authorization_code"},
 			"code":          {code},
-			"redirect_uri":  {redirectURI},
+			"redirect_uri":  {oauthState.RedirectURI},
 			"client_id":     {authCfg.IAMClientID},
 			"client_secret": {authCfg.IAMClientSecret},
+			"code_verifier": {oauthState.CodeVerifier},
 		}
 
 		httpClient := &http.Client{Timeout: 10 * time.Second}
@@ -148,9 +226,38 @@ func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig) {
 		// Determine if we should set Secure flag based on the request.
 		secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
 
+		// Default to the legacy behavior (cookie holds the token itself);
+		// when a SessionStore is configured, wrap the token in a session
+		// and put its ID in the cookie instead, so the token never leaves
+		// the server and a leaked cookie can be revoked independently of it.
+		cookieValue := tokenResp.AccessToken
+		if sessions != nil {
+			userID := ""
+			if info, err := fetchIAMUserInfo(internalEndpoint, tokenResp.AccessToken); err == nil {
+				userID = info.ID
+				if userID == "" {
+					userID = info.Email
+				}
+			}
+			tokens := middleware.SessionTokens{
+				AccessToken:  tokenResp.AccessToken,
+				RefreshToken: tokenResp.RefreshToken,
+				IDToken:      tokenResp.IDToken,
+			}
+			sess, err := sessions.Create(c.Request.Context(), userID, tokens, c.ClientIP(), c.Request.UserAgent(), time.Duration(maxAge)*time.Second)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":   "session_create_failed",
+					"message": "failed to create session",
+				})
+				return
+			}
+			cookieValue = sess.ID
+		}
+
 		c.SetCookie(
 			middleware.SessionCookieName,
-			tokenResp.AccessToken,
+			cookieValue,
 			maxAge,
 			"/",
 			"",   // Domain - let browser infer
@@ -158,8 +265,8 @@ func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig) {
 			true, // HttpOnly
 		)
 
-		// Redirect to the UI.
-		c.Redirect(http.StatusFound, "/ui/")
+		// Redirect back to wherever the login was originally initiated from.
+		c.Redirect(http.StatusFound, oauthState.ReturnTo)
 	})
 
 	// GET /api/v1/auth/userinfo - Return current user info from IAM token.
@@ -171,9 +278,7 @@ func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig) {
 			token = strings.TrimPrefix(authHeader, "Bearer ")
 		}
 		if token == "" {
-			if cookie, err := c.Cookie(middleware.SessionCookieName); err == nil {
-				token = cookie
-			}
+			token = cookieToken(c, sessions)
 		}
 
 		// Also check API key auth.
@@ -182,6 +287,25 @@ func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig) {
 			apiKey = c.Query("api_key")
 		}
 
+		// Also check for a SigV4-signed request (Authorization header or a
+		// presigned GetCallerIdentity URL), independent of the Bearer/cookie
+		// token and API key above - a SigV4 Authorization header never has
+		// the "Bearer " prefix, so token is never taken from it.
+		if sigV4Config.Enabled {
+			httpClient := &http.Client{Timeout: 5 * time.Second}
+			if identity, err := middleware.ResolveCallerIdentity(httpClient, sigV4Config, c.Request); err == nil && identity != nil {
+				if _, ok := middleware.MapARNToUser(sigV4Config.ARNMappings, identity.Arn); ok {
+					c.JSON(http.StatusOK, AuthInfoResponse{
+						Authenticated: true,
+						Method:        "sigv4",
+						IAMEnabled:    true,
+						CallerARN:     identity.Arn,
+					})
+					return
+				}
+			}
+		}
+
 		if token == "" && apiKey == "" {
 			c.JSON(http.StatusUnauthorized, AuthInfoResponse{
 				Authenticated: false,
@@ -251,8 +375,17 @@ func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig) {
 		})
 	})
 
-	// POST /auth/logout - Clear session cookie.
+	// POST /auth/logout - Revoke the session at IAM and clear the cookie.
 	router.POST("/auth/logout", func(c *gin.Context) {
+		if sessions != nil {
+			if cookie, err := c.Cookie(middleware.SessionCookieName); err == nil {
+				if sess, err := sessions.Get(c.Request.Context(), cookie); err == nil && sess != nil {
+					revokeIAMToken(internalEndpoint, authCfg, sess.Token)
+				}
+				_ = sessions.Revoke(c.Request.Context(), cookie)
+			}
+		}
+
 		secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
 		c.SetCookie(
 			middleware.SessionCookieName,
@@ -265,6 +398,191 @@ func RegisterAuthRoutes(router *gin.Engine, authCfg config.AuthConfig) {
 		)
 		c.JSON(http.StatusOK, gin.H{"message": "logged out"})
 	})
+
+	// GET /api/v1/auth/csrf - Return the current session's CSRF token, for
+	// the frontend to echo back as an X-CSRF-Token header on state-changing
+	// requests. Only meaningful when sessions is configured; bearer-token
+	// callers aren't subject to CSRF checks in the first place.
+	router.GET("/api/v1/auth/csrf", func(c *gin.Context) {
+		if sessions == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "sessions not configured"})
+			return
+		}
+
+		cookie, err := c.Cookie(middleware.SessionCookieName)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "no session cookie"})
+			return
+		}
+		sess, err := sessions.Get(c.Request.Context(), cookie)
+		if err != nil || sess == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired session"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"csrf_token": sess.CSRFSecret})
+	})
+
+	// GET /api/v1/auth/sessions - List the current user's active sessions
+	// ("your devices"). Requires the caller to be authenticated via a
+	// session cookie; API key or raw bearer token requests have no
+	// session to enumerate from.
+	router.GET("/api/v1/auth/sessions", func(c *gin.Context) {
+		if sessions == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "sessions not configured"})
+			return
+		}
+
+		cookie, err := c.Cookie(middleware.SessionCookieName)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "no session cookie"})
+			return
+		}
+		current, err := sessions.Get(c.Request.Context(), cookie)
+		if err != nil || current == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired session"})
+			return
+		}
+
+		list, err := sessions.List(c.Request.Context(), current.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list sessions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"sessions": list, "current_session_id": current.ID})
+	})
+
+	// DELETE /api/v1/auth/sessions/:id - Revoke one of the current user's
+	// sessions, e.g. to sign a lost device out remotely.
+	router.DELETE("/api/v1/auth/sessions/:id", func(c *gin.Context) {
+		if sessions == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "sessions not configured"})
+			return
+		}
+
+		cookie, err := c.Cookie(middleware.SessionCookieName)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "no session cookie"})
+			return
+		}
+		current, err := sessions.Get(c.Request.Context(), cookie)
+		if err != nil || current == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired session"})
+			return
+		}
+
+		targetID := c.Param("id")
+		target, err := sessions.Get(c.Request.Context(), targetID)
+		if err != nil || target == nil || target.UserID != current.UserID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+
+		if err := sessions.Revoke(c.Request.Context(), targetID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke session"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "session revoked"})
+	})
+}
+
+// cookieToken resolves the session cookie to an IAM access token: the
+// cookie value directly when sessions is nil (legacy behavior), or the
+// token a session ID wraps when it's configured.
+func cookieToken(c *gin.Context, sessions middleware.SessionStore) string {
+	cookie, err := c.Cookie(middleware.SessionCookieName)
+	if err != nil || cookie == "" {
+		return ""
+	}
+	if sessions == nil {
+		return cookie
+	}
+
+	sess, err := sessions.Get(c.Request.Context(), cookie)
+	if err != nil || sess == nil {
+		return ""
+	}
+	return sess.Token
+}
+
+// fetchIAMUserInfo calls the IAM userinfo endpoint directly, for call
+// sites (like the OAuth callback) that need the user's identity before any
+// middleware has had a chance to resolve it.
+func fetchIAMUserInfo(internalEndpoint, token string) (*middleware.IAMUserInfo, error) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+
+	req, err := http.NewRequest("GET", internalEndpoint+"/api/userinfo", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("iam userinfo returned status %d", resp.StatusCode)
+	}
+
+	var userInfo middleware.IAMUserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
+		return nil, err
+	}
+	if userInfo.ID == "" && userInfo.Email == "" {
+		return nil, fmt.Errorf("iam userinfo returned no identity")
+	}
+	return &userInfo, nil
+}
+
+// revokeIAMToken asks IAM to revoke token, per the RFC 7009 token
+// revocation endpoint convention (the same /api/login/oauth/ prefix as the
+// authorize and access_token endpoints used elsewhere in this file).
+// Best-effort: a failure here is logged, not surfaced, since the session is
+// deleted server-side regardless and that alone stops this control plane
+// from accepting the token again.
+func revokeIAMToken(internalEndpoint string, authCfg config.AuthConfig, token string) {
+	if token == "" {
+		return
+	}
+
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	formData := url.Values{
+		"token":         {token},
+		"client_id":     {authCfg.IAMClientID},
+		"client_secret": {authCfg.IAMClientSecret},
+	}
+
+	resp, err := httpClient.PostForm(internalEndpoint+"/api/login/oauth/revoke", formData)
+	if err != nil {
+		log.Warn().Err(err).Msg("auth: failed to reach IAM for token revocation")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Warn().Int("status", resp.StatusCode).Msg("auth: IAM rejected token revocation")
+	}
+}
+
+// pkceChallengeS256 derives the PKCE S256 code challenge for verifier, per
+// RFC 7636: base64url(sha256(verifier)), no padding.
+func pkceChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// sanitizeReturnTo restricts return_to to a same-origin absolute path, so a
+// crafted /auth/login?return_to=https://evil.example can't turn the login
+// flow into an open redirect. Anything else falls back to the UI root.
+func sanitizeReturnTo(raw string) string {
+	if raw == "" || !strings.HasPrefix(raw, "/") || strings.HasPrefix(raw, "//") || strings.Contains(raw, "\\") {
+		return "/ui/"
+	}
+	return raw
 }
 
 // buildRedirectURI constructs the OAuth redirect URI from the current request.
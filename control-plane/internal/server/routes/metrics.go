@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// RegisterMetricsRoutes exposes gatherer's collected metrics at /metrics in
+// the Prometheus text exposition format. Like /health, this route must stay
+// reachable without auth (see middleware.CombinedAuth's always-allowed
+// paths), so register
+// it alongside the other unauthenticated routes.
+func RegisterMetricsRoutes(router *gin.Engine, gatherer prometheus.Gatherer) {
+	handler := promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+	router.GET("/metrics", gin.WrapH(handler))
+}
@@ -7,19 +7,41 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/hanzoai/agents/control-plane/internal/logger"
+	"github.com/hanzoai/agents/control-plane/internal/metrics"
+	"github.com/hanzoai/agents/control-plane/internal/services/ledger"
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+	"github.com/hanzoai/agents/control-plane/pkg/types"
 )
 
 // Sentinel errors for billing operations.
 var (
 	ErrInsufficientFunds   = errors.New("insufficient funds")
 	ErrCommerceUnavailable = errors.New("commerce service unavailable")
+	// ErrAccountFrozen is returned when a billing operation is attempted
+	// for a user with an active account freeze.
+	ErrAccountFrozen = errors.New("account is frozen")
 )
 
+// FreezeChecker is the subset of internal/cloud's AccountFreezeService that
+// BillingService consults, defined here rather than imported so this
+// package doesn't depend on internal/cloud. Satisfied structurally by
+// *cloud.AccountFreezeService; callers wire it in via SetFreezeChecker.
+type FreezeChecker interface {
+	IsFrozen(ctx context.Context, userID string) (bool, error)
+	RecordInsufficientFunds(ctx context.Context, userID string) error
+	RecordSuccessfulTopUp(ctx context.Context, userID string) error
+}
+
 // BillingConfig holds billing configuration.
 type BillingConfig struct {
 	CommerceURL string // BILLING_COMMERCE_URL
@@ -38,6 +60,10 @@ type DebitParams struct {
 	ExecutionID string
 	BotID       string
 	Notes       string
+	// PartnerAttribution identifies which partner's price model this debit
+	// should be attributed to, mirroring types.ProvisionRequest's field of
+	// the same name, so cost estimates in the UI can be split per partner.
+	PartnerAttribution string
 }
 
 // BalanceResponse represents the Commerce API balance response.
@@ -49,6 +75,28 @@ type BalanceResponse struct {
 	Available int64  `json:"available"`
 }
 
+// HoldParams describes a request to place a hold against a user's balance,
+// reserving funds for a not-yet-known final cost (e.g. the max plausible
+// cost of a cloud instance run) without debiting them outright.
+type HoldParams struct {
+	User        string
+	AmountCents int64
+	Currency    string
+	Notes       string
+	// TTL bounds how long Commerce keeps the hold before auto-releasing
+	// it. ExtendHold pushes this out for long-running instances.
+	TTL time.Duration
+}
+
+// HoldResponse represents the Commerce API hold response.
+type HoldResponse struct {
+	HoldID    string `json:"holdId"`
+	User      string `json:"user"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
 // UsageResponse represents the Commerce API usage recording response.
 type UsageResponse struct {
 	TransactionID string `json:"transactionId"`
@@ -66,6 +114,70 @@ type RefundResponse struct {
 	Currency      string `json:"currency"`
 }
 
+// BillingOutboxConfig tunes BillingService's debit retry worker.
+type BillingOutboxConfig struct {
+	// BaseBackoff is the delay before the first retry; each subsequent
+	// retry doubles it (jittered), capped at MaxBackoff. Defaults to 1s.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the jittered exponential backoff. Defaults to 1h.
+	MaxBackoff time.Duration
+	// MaxAttempts is how many sends (the initial send plus retries) a
+	// record gets before it's moved to the dead-letter status. Defaults
+	// to 10.
+	MaxAttempts int
+	// PollInterval is how often the drain worker checks for due records.
+	// Defaults to 5s.
+	PollInterval time.Duration
+	// BatchSize bounds how many due records one drain tick processes.
+	// Defaults to 20.
+	BatchSize int
+}
+
+func (c BillingOutboxConfig) withDefaults() BillingOutboxConfig {
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Hour
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 10
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = 5 * time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 20
+	}
+	return c
+}
+
+// billingOutboxMetrics holds the Prometheus collectors for BillingService's
+// debit outbox, rebuilt by SetMetricsRegisterer.
+type billingOutboxMetrics struct {
+	queueDepth prometheus.Gauge
+	dlqSize    prometheus.Gauge
+	retries    prometheus.Counter
+}
+
+func newBillingOutboxMetrics(reg prometheus.Registerer) *billingOutboxMetrics {
+	r := metrics.New(reg)
+	return &billingOutboxMetrics{
+		queueDepth: r.Gauge(prometheus.GaugeOpts{
+			Name: "hanzo_billing_outbox_pending_total",
+			Help: "Number of billing debit outbox records currently pending retry.",
+		}),
+		dlqSize: r.Gauge(prometheus.GaugeOpts{
+			Name: "hanzo_billing_outbox_dead_letter_total",
+			Help: "Number of billing debit outbox records that exhausted their retry budget.",
+		}),
+		retries: r.Counter(prometheus.CounterOpts{
+			Name: "hanzo_billing_outbox_retries_total",
+			Help: "Number of billing debit outbox retry attempts made.",
+		}),
+	}
+}
+
 // BillingService provides billing operations via the Commerce API.
 // Billing is always enabled — every execution is balance-gated and charged.
 type BillingService struct {
@@ -73,6 +185,106 @@ type BillingService struct {
 	adminToken  string
 	client      *http.Client
 	currency    string
+	freezes     FreezeChecker
+	packages    *PricingService
+	ledger      *ledger.Ledger
+
+	outbox       *storage.LocalStorage
+	outboxConfig BillingOutboxConfig
+	outboxMetric *billingOutboxMetrics
+
+	outboxStopOnce sync.Once
+	outboxStopCh   chan struct{}
+}
+
+// SetOutboxStore installs a durable outbox for DebitActualCost/DebitUpfront,
+// so a debit intent survives a crash between recording it and Commerce
+// confirming it, and can be retried by StartOutboxWorker instead of being
+// silently dropped. Defaults to unset, in which case debits are attempted
+// once and failures are only logged, matching the prior behavior.
+func (b *BillingService) SetOutboxStore(store *storage.LocalStorage, cfg BillingOutboxConfig) {
+	b.outbox = store
+	b.outboxConfig = cfg.withDefaults()
+	if b.outboxMetric == nil {
+		b.outboxMetric = newBillingOutboxMetrics(nil)
+	}
+}
+
+// SetMetricsRegisterer rebuilds the outbox's Prometheus collectors against
+// reg. Call once during server wiring; safe to skip, in which case the
+// metrics are tracked in-process but never exposed to a scraper.
+func (b *BillingService) SetMetricsRegisterer(reg prometheus.Registerer) {
+	b.outboxMetric = newBillingOutboxMetrics(reg)
+}
+
+// SetFreezeChecker installs a FreezeChecker so CheckBalance refuses frozen
+// accounts and NotifyInsufficientFunds/NotifyTopUp can drive auto-freeze and
+// auto-unfreeze. Defaults to unset, in which case freeze state is never
+// consulted.
+func (b *BillingService) SetFreezeChecker(checker FreezeChecker) {
+	b.freezes = checker
+}
+
+// NotifyInsufficientFunds tells the installed FreezeChecker that userID hit
+// ErrInsufficientFunds, so it can auto-freeze the account after enough
+// consecutive occurrences. Callers should invoke this wherever they surface
+// ErrInsufficientFunds to a caller. A no-op if no FreezeChecker is set.
+func (b *BillingService) NotifyInsufficientFunds(ctx context.Context, userID string) {
+	if b.freezes == nil {
+		return
+	}
+	if err := b.freezes.RecordInsufficientFunds(ctx, userID); err != nil {
+		logger.Logger.Error().Err(err).Str("user", userID).Msg("failed to record insufficient funds for auto-freeze")
+	}
+}
+
+// NotifyTopUp tells the installed FreezeChecker that userID successfully
+// topped up, so a billing freeze can be auto-lifted. A no-op if no
+// FreezeChecker is set.
+func (b *BillingService) NotifyTopUp(ctx context.Context, userID string) {
+	if b.freezes == nil {
+		return
+	}
+	if err := b.freezes.RecordSuccessfulTopUp(ctx, userID); err != nil {
+		logger.Logger.Error().Err(err).Str("user", userID).Msg("failed to record top-up for auto-unfreeze")
+	}
+}
+
+// SetLedger installs a Ledger so every balance-affecting method (PlaceHold,
+// CaptureHold, ReleaseHold, DebitActualCost/DebitUpfront, Refund) appends a
+// hash-chained audit entry alongside its Commerce call. Defaults to unset,
+// in which case no local ledger is kept (Ledger.Record is nil-safe, so
+// every call site below works unconditionally either way).
+func (b *BillingService) SetLedger(l *ledger.Ledger) {
+	b.ledger = l
+}
+
+// Ledger returns the Ledger installed by SetLedger, or nil if none was, for
+// callers that record ledger entries on BillingService's behalf (e.g.
+// PricingService.PurchasePackage).
+func (b *BillingService) Ledger() *ledger.Ledger {
+	return b.ledger
+}
+
+// SetPricingService installs a PricingService so CheckCoverage can draw down
+// a user's prepaid package balance ahead of metered billing. Defaults to
+// unset, in which case every execution is metered.
+func (b *BillingService) SetPricingService(packages *PricingService) {
+	b.packages = packages
+}
+
+// CheckCoverage draws down estimatedMinutes/estimatedTokens from userID's
+// package balance for platform/teamID, if a PricingService is installed. It
+// returns covered=true when the package balance fully absorbed the request,
+// in which case callers should skip the metered CheckBalance/debit path
+// entirely. Returns covered=false (with a nil error) whenever no
+// PricingService is installed, so callers can unconditionally check
+// coverage before falling back to metered billing.
+func (b *BillingService) CheckCoverage(ctx context.Context, userID, platform, teamID string, estimatedMinutes float64, estimatedTokens int64) (bool, error) {
+	if b.packages == nil {
+		return false, nil
+	}
+	return b.packages.DrawDown(ctx, userID, platform, teamID, estimatedMinutes, estimatedTokens)
 }
 
 // NewBillingService creates a new BillingService from config.
@@ -87,13 +299,24 @@ func NewBillingService(cfg BillingConfig) *BillingService {
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		currency: cur,
+		currency:     cur,
+		outboxMetric: newBillingOutboxMetrics(nil),
+		outboxStopCh: make(chan struct{}),
 	}
 }
 
 // CheckBalance queries Commerce for the user's available balance (in cents).
 // Returns ErrCommerceUnavailable if Commerce is down (fail-safe: blocks execution).
 func (b *BillingService) CheckBalance(ctx context.Context, userID string) (int64, error) {
+	if b.freezes != nil {
+		frozen, err := b.freezes.IsFrozen(ctx, userID)
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("user", userID).Msg("freeze check failed")
+		} else if frozen {
+			return 0, ErrAccountFrozen
+		}
+	}
+
 	u, err := url.Parse(b.commerceURL + "/api/v1/billing/balance")
 	if err != nil {
 		return 0, fmt.Errorf("parse commerce URL: %w", err)
@@ -132,16 +355,495 @@ func (b *BillingService) CheckBalance(ctx context.Context, userID string) (int64
 	return bal.Available, nil
 }
 
+// GetBalanceDetail queries Commerce for userID's full balance breakdown,
+// including funds reserved by open holds, for callers (e.g. a summary view)
+// that need held-vs-available rather than just the plain available balance
+// CheckBalance returns.
+func (b *BillingService) GetBalanceDetail(ctx context.Context, userID string) (*BalanceResponse, error) {
+	u, err := url.Parse(b.commerceURL + "/api/v1/billing/balance")
+	if err != nil {
+		return nil, fmt.Errorf("parse commerce URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("user", userID)
+	q.Set("currency", b.currency)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create balance request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+b.adminToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("user", userID).Msg("commerce balance check failed")
+		return nil, ErrCommerceUnavailable
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return nil, ErrCommerceUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("commerce balance error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var bal BalanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bal); err != nil {
+		return nil, fmt.Errorf("decode balance response: %w", err)
+	}
+	return &bal, nil
+}
+
+// PlaceHold reserves params.AmountCents against the user's balance without
+// debiting it, returning a hold ID. Callers use this to reserve the max
+// plausible cost of a run before starting it, then resolve the hold with
+// CaptureHold or ReleaseHold once the actual cost is known.
+func (b *BillingService) PlaceHold(ctx context.Context, params HoldParams) (string, error) {
+	cur := params.Currency
+	if cur == "" {
+		cur = b.currency
+	}
+
+	ttlSeconds := int64(params.TTL / time.Second)
+	payload := map[string]interface{}{
+		"user":     params.User,
+		"currency": cur,
+		"amount":   params.AmountCents,
+		"notes":    params.Notes,
+	}
+	if ttlSeconds > 0 {
+		payload["ttlSeconds"] = ttlSeconds
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("marshal hold request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.commerceURL+"/api/v1/billing/holds", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create hold request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+b.adminToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("hold request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPaymentRequired {
+		return "", ErrInsufficientFunds
+	}
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("hold error (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result HoldResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode hold response: %w", err)
+	}
+
+	// There's no outbox row backing a hold, so this write can't join a
+	// transaction with anything - Commerce has already placed the hold by
+	// this point, so a failed ledger write here is drift for the
+	// Reconciler to catch later, not a failed PlaceHold.
+	if _, err := b.ledger.Record(ctx, ledger.RecordParams{
+		User:          params.User,
+		Action:        types.LedgerActionHold,
+		DebitAccount:  ledger.UserAccount(params.User),
+		CreditAccount: ledger.HoldAccount(result.HoldID),
+		AmountCents:   params.AmountCents,
+		Currency:      cur,
+		Metadata:      map[string]interface{}{"hold_id": result.HoldID, "notes": params.Notes},
+	}); err != nil {
+		logger.Logger.Error().Err(err).Str("hold_id", result.HoldID).Msg("failed to record hold in billing ledger")
+	}
+
+	return result.HoldID, nil
+}
+
+// CaptureHold settles holdID for actualCents, debiting that amount and
+// releasing the rest of the hold back to the user's available balance. It
+// returns the transaction ID of the resulting Withdraw.
+func (b *BillingService) CaptureHold(ctx context.Context, holdID string, actualCents int64) (string, error) {
+	payload := map[string]interface{}{
+		"amount": actualCents,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("marshal capture request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.commerceURL+"/api/v1/billing/holds/"+holdID+"/capture", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create capture request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+b.adminToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("capture request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("capture error (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result UsageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode capture response: %w", err)
+	}
+
+	// Same as PlaceHold above: no outbox row to transact with, and Commerce
+	// has already captured the hold, so a failed write here is reconciled
+	// later rather than surfaced as a failed CaptureHold.
+	if _, err := b.ledger.Record(ctx, ledger.RecordParams{
+		Action:        types.LedgerActionCapture,
+		DebitAccount:  ledger.HoldAccount(holdID),
+		CreditAccount: ledger.RevenueAccount,
+		AmountCents:   actualCents,
+		Currency:      b.currency,
+		Metadata:      map[string]interface{}{"hold_id": holdID, "transaction_id": result.TransactionID},
+	}); err != nil {
+		logger.Logger.Error().Err(err).Str("hold_id", holdID).Msg("failed to record capture in billing ledger")
+	}
+
+	return result.TransactionID, nil
+}
+
+// ReleaseHold cancels holdID outright, returning the full reserved amount
+// to the user's available balance without debiting anything. Used when a
+// run never happens (e.g. provisioning failed before launch).
+func (b *BillingService) ReleaseHold(ctx context.Context, holdID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.commerceURL+"/api/v1/billing/holds/"+holdID+"/release", nil)
+	if err != nil {
+		return fmt.Errorf("create release request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+b.adminToken)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("release request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("release error (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	// BillingService doesn't track a hold's reserved amount locally (that's
+	// Commerce-side state looked up by holdID), so this entry can't net the
+	// released amount back out of the hold account — it's recorded with
+	// AmountCents: 0, as a marker that the hold closed, not a balance move.
+	// As with PlaceHold/CaptureHold, there's no outbox row to pair this
+	// write with, so a failed write is left for the Reconciler.
+	if _, err := b.ledger.Record(ctx, ledger.RecordParams{
+		Action:       types.LedgerActionRelease,
+		DebitAccount: ledger.HoldAccount(holdID),
+		Metadata:     map[string]interface{}{"hold_id": holdID},
+	}); err != nil {
+		logger.Logger.Error().Err(err).Str("hold_id", holdID).Msg("failed to record release in billing ledger")
+	}
+
+	return nil
+}
+
+// ExtendHold pushes holdID's expiry out by ttl, for a run that's lasting
+// longer than the hold's original TTL.
+func (b *BillingService) ExtendHold(ctx context.Context, holdID string, ttl time.Duration) error {
+	payload := map[string]interface{}{
+		"ttlSeconds": int64(ttl / time.Second),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal extend request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.commerceURL+"/api/v1/billing/holds/"+holdID+"/extend", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create extend request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+b.adminToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("extend request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("extend error (%d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
 // DebitActualCost charges the user for actual usage after execution completes.
 // Calls Commerce POST /billing/usage to create a Withdraw transaction.
 func (b *BillingService) DebitActualCost(ctx context.Context, params DebitParams) (string, error) {
-	return b.recordUsage(ctx, params)
+	return b.debit(ctx, params)
 }
 
 // DebitUpfront charges a known cost before execution (e.g. Mac VM 24hr minimum).
 // Uses the same usage endpoint — the cost is known in advance.
 func (b *BillingService) DebitUpfront(ctx context.Context, params DebitParams) (string, error) {
-	return b.recordUsage(ctx, params)
+	return b.debit(ctx, params)
+}
+
+// debitIdempotencyKey derives a stable idempotency key from the fields that
+// identify a debit intent, so a redelivered debit (a manual retry, or the
+// outbox worker retrying an already-partially-applied attempt) dedupes on
+// the Commerce side instead of double-charging.
+func debitIdempotencyKey(params DebitParams) string {
+	return fmt.Sprintf("%s:%s:%d", params.ExecutionID, params.BotID, params.AmountCents)
+}
+
+// debit records params in the durable outbox (if one is installed) and
+// attempts an immediate send. On failure the outbox row is left pending for
+// StartOutboxWorker to retry with backoff, so the debit isn't silently
+// dropped even though this call still returns the error to its caller. With
+// no outbox installed, this behaves exactly as before: one attempt, no
+// durability.
+func (b *BillingService) debit(ctx context.Context, params DebitParams) (string, error) {
+	key := debitIdempotencyKey(params)
+
+	if b.outbox == nil {
+		return b.sendUsageRequestAndRecord(ctx, params, key)
+	}
+
+	existing, err := b.outbox.GetBillingOutboxRecordByIdempotencyKey(ctx, key)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("key", key).Msg("billing outbox lookup failed, attempting debit without durability")
+		return b.sendUsageRequestAndRecord(ctx, params, key)
+	}
+	if existing != nil && existing.Status == string(storage.BillingOutboxCompleted) {
+		return existing.TransactionID, nil
+	}
+
+	if existing == nil {
+		now := time.Now().UTC()
+		existing = &storage.BillingDebitOutboxModel{
+			ID:             uuid.New().String(),
+			IdempotencyKey: key,
+			User:           params.User,
+			AmountCents:    params.AmountCents,
+			Currency:       params.Currency,
+			Model:          params.Model,
+			Provider:       params.Provider,
+			Tokens:         params.Tokens,
+			ExecutionID:    params.ExecutionID,
+			BotID:          params.BotID,
+			Notes:          params.Notes,
+			Status:         string(storage.BillingOutboxPending),
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := b.outbox.CreateBillingOutboxRecord(ctx, existing); err != nil {
+			logger.Logger.Error().Err(err).Str("key", key).Msg("failed to persist billing outbox record, attempting debit without durability")
+			return b.sendUsageRequestAndRecord(ctx, params, key)
+		}
+	}
+
+	txID, sendErr := b.sendUsageRequest(ctx, params, key)
+	b.applyOutboxAttempt(ctx, existing, txID, sendErr)
+	return txID, sendErr
+}
+
+// sendUsageRequestAndRecord is sendUsageRequest plus a ledger entry on
+// success, for the debit paths that never go through the outbox (no outbox
+// installed, or the outbox lookup/insert itself failed). There's no outbox
+// row here to pair the ledger write with in a transaction, so a failed
+// ledger write is only logged - it's caught later by the Reconciler
+// comparing the ledger's balance against Commerce's, the same tradeoff
+// PlaceHold/CaptureHold/ReleaseHold/Refund make below.
+func (b *BillingService) sendUsageRequestAndRecord(ctx context.Context, params DebitParams, idempotencyKey string) (string, error) {
+	txID, err := b.sendUsageRequest(ctx, params, idempotencyKey)
+	if err == nil {
+		if lerr := b.recordDebitLedgerEntry(ctx, params, txID); lerr != nil {
+			logger.Logger.Error().Err(lerr).Str("transaction_id", txID).Msg("failed to record debit in billing ledger")
+		}
+	}
+	return txID, err
+}
+
+// recordDebitLedgerEntry appends a Debit ledger entry for a successfully
+// sent usage request. A no-op (nil error) if no Ledger is installed.
+func (b *BillingService) recordDebitLedgerEntry(ctx context.Context, params DebitParams, txID string) error {
+	cur := params.Currency
+	if cur == "" {
+		cur = b.currency
+	}
+	_, err := b.ledger.Record(ctx, ledger.RecordParams{
+		User:          params.User,
+		Action:        types.LedgerActionDebit,
+		DebitAccount:  ledger.UserAccount(params.User),
+		CreditAccount: ledger.RevenueAccount,
+		AmountCents:   params.AmountCents,
+		Currency:      cur,
+		Metadata: map[string]interface{}{
+			"transaction_id": txID,
+			"execution_id":   params.ExecutionID,
+			"bot_id":         params.BotID,
+		},
+	})
+	return err
+}
+
+// applyOutboxAttempt records the outcome of one send attempt against
+// record, advancing it to completed, dead-letter, or a backed-off retry.
+func (b *BillingService) applyOutboxAttempt(ctx context.Context, record *storage.BillingDebitOutboxModel, txID string, sendErr error) {
+	record.Attempts++
+	record.UpdatedAt = time.Now().UTC()
+
+	if sendErr == nil {
+		record.Status = string(storage.BillingOutboxCompleted)
+		record.TransactionID = txID
+		record.LastError = ""
+
+		// The ledger entry and the outbox row's transition to completed have
+		// to land together: a send that succeeds but whose ledger entry or
+		// completion marker doesn't commit is exactly the ledger-vs-reality
+		// drift the outbox and ledger were both built to prevent, so both
+		// writes go through one DB transaction rather than two independent
+		// calls.
+		err := b.outbox.WithTransaction(ctx, func(txCtx context.Context) error {
+			if err := b.recordDebitLedgerEntry(txCtx, DebitParams{
+				User:        record.User,
+				AmountCents: record.AmountCents,
+				Currency:    record.Currency,
+				ExecutionID: record.ExecutionID,
+				BotID:       record.BotID,
+			}, txID); err != nil {
+				return fmt.Errorf("record debit ledger entry: %w", err)
+			}
+			return b.outbox.UpdateBillingOutboxRecord(txCtx, record)
+		})
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("key", record.IdempotencyKey).
+				Msg("failed to atomically record debit ledger entry and outbox completion")
+		}
+		return
+	}
+
+	record.LastError = sendErr.Error()
+	if record.Attempts >= b.outboxConfig.withDefaults().MaxAttempts {
+		record.Status = string(storage.BillingOutboxDeadLetter)
+		logger.Logger.Error().Err(sendErr).Str("key", record.IdempotencyKey).Int("attempts", record.Attempts).
+			Msg("billing debit exhausted retries, moved to dead letter")
+	} else {
+		record.NextAttemptAt = time.Now().UTC().Add(b.outboxBackoff(record.Attempts))
+	}
+	b.outboxMetric.retries.Inc()
+
+	if err := b.outbox.UpdateBillingOutboxRecord(ctx, record); err != nil {
+		logger.Logger.Error().Err(err).Str("key", record.IdempotencyKey).Msg("failed to persist billing outbox attempt")
+	}
+}
+
+// outboxBackoff computes a jittered exponential backoff for the given
+// attempt number (1-indexed), capped at BillingOutboxConfig.MaxBackoff.
+func (b *BillingService) outboxBackoff(attempt int) time.Duration {
+	cfg := b.outboxConfig.withDefaults()
+	d := cfg.BaseBackoff << uint(attempt-1)
+	if d <= 0 || d > cfg.MaxBackoff {
+		d = cfg.MaxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d/2 + jitter
+}
+
+// StartOutboxWorker runs the debit retry loop until StopOutboxWorker is
+// called. A no-op if no outbox store is installed.
+func (b *BillingService) StartOutboxWorker() {
+	if b.outbox == nil {
+		return
+	}
+
+	cfg := b.outboxConfig.withDefaults()
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	logger.Logger.Info().Dur("interval", cfg.PollInterval).Msg("billing outbox worker started")
+
+	for {
+		select {
+		case <-b.outboxStopCh:
+			logger.Logger.Info().Msg("billing outbox worker stopped")
+			return
+		case <-ticker.C:
+			b.drainOutboxOnce()
+		}
+	}
+}
+
+// StopOutboxWorker terminates the outbox worker loop started by
+// StartOutboxWorker.
+func (b *BillingService) StopOutboxWorker() {
+	b.outboxStopOnce.Do(func() {
+		close(b.outboxStopCh)
+	})
+}
+
+func (b *BillingService) drainOutboxOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cfg := b.outboxConfig.withDefaults()
+	due, err := b.outbox.ListDueBillingOutboxRecords(ctx, cfg.BatchSize)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("billing outbox worker: failed to list due records")
+		return
+	}
+
+	for _, record := range due {
+		params := DebitParams{
+			User:        record.User,
+			AmountCents: record.AmountCents,
+			Currency:    record.Currency,
+			Model:       record.Model,
+			Provider:    record.Provider,
+			Tokens:      record.Tokens,
+			ExecutionID: record.ExecutionID,
+			BotID:       record.BotID,
+			Notes:       record.Notes,
+		}
+		txID, sendErr := b.sendUsageRequest(ctx, params, record.IdempotencyKey)
+		b.applyOutboxAttempt(ctx, record, txID, sendErr)
+	}
+
+	b.refreshOutboxGauges(ctx)
+}
+
+func (b *BillingService) refreshOutboxGauges(ctx context.Context) {
+	if pending, err := b.outbox.CountBillingOutboxByStatus(ctx, storage.BillingOutboxPending); err == nil {
+		b.outboxMetric.queueDepth.Set(float64(pending))
+	}
+	if dlq, err := b.outbox.CountBillingOutboxByStatus(ctx, storage.BillingOutboxDeadLetter); err == nil {
+		b.outboxMetric.dlqSize.Set(float64(dlq))
+	}
+}
+
+// ListDeadLetterDebits returns every debit that exhausted its retry budget,
+// for an admin dead-letter view. Returns an empty slice if no outbox store
+// is installed.
+func (b *BillingService) ListDeadLetterDebits(ctx context.Context) ([]*storage.BillingDebitOutboxModel, error) {
+	if b.outbox == nil {
+		return nil, nil
+	}
+	return b.outbox.ListDeadLetterBillingOutboxRecords(ctx)
 }
 
 // Refund creates a correction deposit for an overcharge.
@@ -177,11 +879,30 @@ func (b *BillingService) Refund(ctx context.Context, userID string, amountCents
 		respBody, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("refund error (%d): %s", resp.StatusCode, string(respBody))
 	}
+
+	// Same tradeoff as PlaceHold/CaptureHold/ReleaseHold: no outbox row to
+	// transact with, and Commerce has already applied the refund, so a
+	// failed write here is drift for the Reconciler to catch rather than a
+	// failed Refund.
+	if _, err := b.ledger.Record(ctx, ledger.RecordParams{
+		User:          userID,
+		Action:        types.LedgerActionRefund,
+		DebitAccount:  ledger.RevenueAccount,
+		CreditAccount: ledger.UserAccount(userID),
+		AmountCents:   amountCents,
+		Currency:      cur,
+		Metadata:      map[string]interface{}{"original_transaction_id": originalTxID, "notes": notes},
+	}); err != nil {
+		logger.Logger.Error().Err(err).Str("user", userID).Msg("failed to record refund in billing ledger")
+	}
+
 	return nil
 }
 
-// recordUsage creates a Withdraw transaction in Commerce for usage billing.
-func (b *BillingService) recordUsage(ctx context.Context, params DebitParams) (string, error) {
+// sendUsageRequest makes one attempt at POST /api/v1/billing/usage, tagging
+// the request with idempotencyKey so a redelivered debit (outbox retry, or a
+// caller's own retry) dedupes on the Commerce side instead of double-charging.
+func (b *BillingService) sendUsageRequest(ctx context.Context, params DebitParams, idempotencyKey string) (string, error) {
 	cur := params.Currency
 	if cur == "" {
 		cur = b.currency
@@ -212,6 +933,9 @@ func (b *BillingService) recordUsage(ctx context.Context, params DebitParams) (s
 	}
 	req.Header.Set("Authorization", "Bearer "+b.adminToken)
 	req.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
 
 	resp, err := b.client.Do(req)
 	if err != nil {
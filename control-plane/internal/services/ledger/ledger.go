@@ -0,0 +1,260 @@
+// Package ledger provides an append-only, hash-chained double-entry ledger
+// for balance-affecting billing actions (Hold, Capture, Release, Debit,
+// Refund, PackageCredit, FreezeAdjustment). It's a separate package from
+// internal/services so callers that only need BillingService's Commerce
+// calls don't pull in the ledger, and so the ledger itself stays free of any
+// dependency back on BillingService.
+package ledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+	"github.com/hanzoai/agents/control-plane/pkg/types"
+)
+
+// genesisHash is PrevHash for the very first entry ever recorded.
+const genesisHash = "0000000000000000000000000000000000000000000000000000000000000000000000000000"
+
+// defaultBufferSize bounds how many recent entries Recent can return,
+// mirroring cloud.EventBus's default.
+const defaultBufferSize = 100
+
+// Ledger records every balance-affecting action as an append-only,
+// hash-chained types.LedgerEntry. Entries are serialized through mu so Seq
+// and PrevHash always chain correctly even under concurrent Record calls.
+type Ledger struct {
+	store *storage.LocalStorage
+
+	mu sync.Mutex
+
+	subMu       sync.RWMutex
+	subscribers map[string]chan *types.LedgerEntry
+	buffer      []*types.LedgerEntry
+}
+
+// NewLedger creates a Ledger backed by store.
+func NewLedger(store *storage.LocalStorage) *Ledger {
+	return &Ledger{
+		store:       store,
+		subscribers: make(map[string]chan *types.LedgerEntry),
+	}
+}
+
+// RecordParams describes one balance-affecting action to append to the
+// ledger.
+type RecordParams struct {
+	Actor         string
+	User          string
+	Action        types.LedgerAction
+	DebitAccount  string
+	CreditAccount string
+	AmountCents   int64
+	Currency      string
+	Metadata      map[string]interface{}
+}
+
+// Record appends a new hash-chained entry for params. A nil Ledger is a
+// no-op that returns (nil, nil), so callers can install one optionally (see
+// services.BillingService.SetLedger) without guarding every call site.
+func (l *Ledger) Record(ctx context.Context, params RecordParams) (*types.LedgerEntry, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	last, err := l.store.GetLastLedgerEntry(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("look up last ledger entry: %w", err)
+	}
+
+	entry := &types.LedgerEntry{
+		ID:            uuid.New().String(),
+		Seq:           1,
+		PrevHash:      genesisHash,
+		Timestamp:     time.Now().UTC(),
+		Actor:         params.Actor,
+		User:          params.User,
+		Action:        params.Action,
+		DebitAccount:  params.DebitAccount,
+		CreditAccount: params.CreditAccount,
+		AmountCents:   params.AmountCents,
+		Currency:      params.Currency,
+		Metadata:      params.Metadata,
+	}
+	if last != nil {
+		entry.Seq = last.Seq + 1
+		entry.PrevHash = last.Hash
+	}
+	entry.Hash = hashEntry(entry)
+
+	if err := l.store.CreateLedgerEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("persist ledger entry: %w", err)
+	}
+
+	l.publish(entry)
+	return entry, nil
+}
+
+// hashEntry commits to every field of entry except Hash, ID and Metadata,
+// chained off PrevHash, so tampering with one of those fields or reordering
+// a past entry changes every hash after it (detected by Verify). Edits to
+// ID or Metadata leave the hash unchanged.
+func hashEntry(entry *types.LedgerEntry) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%d|%s|%s|%s|%s|%s|%s|%d|%s",
+		entry.PrevHash,
+		entry.Seq,
+		entry.Timestamp.Format(time.RFC3339Nano),
+		entry.Actor,
+		entry.User,
+		entry.Action,
+		entry.DebitAccount,
+		entry.CreditAccount,
+		entry.AmountCents,
+		entry.Currency,
+	)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// List returns userID's ledger entries oldest-first, paginated by
+// limit/offset, for the UI audit log.
+func (l *Ledger) List(ctx context.Context, userID string, limit, offset int) ([]*types.LedgerEntry, error) {
+	return l.store.ListLedgerEntries(ctx, userID, limit, offset)
+}
+
+// VerifyResult is the outcome of Verify walking the hash chain.
+type VerifyResult struct {
+	OK bool `json:"ok"`
+	// EntriesChecked is how many entries were walked before OK was
+	// determined (every entry, when OK is true).
+	EntriesChecked int `json:"entries_checked"`
+	// BadSeq is the seq of the first entry whose hash didn't match what
+	// recomputing the chain produced, or 0 when OK is true.
+	BadSeq int64 `json:"bad_seq,omitempty"`
+}
+
+// Verify recomputes the hash chain over every recorded entry, in seq order,
+// to detect tampering: an altered field, a deleted entry, or entries
+// reordered out of their recorded sequence.
+func (l *Ledger) Verify(ctx context.Context) (*VerifyResult, error) {
+	entries, err := l.store.ListAllLedgerEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list ledger entries: %w", err)
+	}
+
+	prevHash := genesisHash
+	for i, entry := range entries {
+		wantSeq := int64(i + 1)
+		if entry.Seq != wantSeq || entry.PrevHash != prevHash || hashEntry(entry) != entry.Hash {
+			return &VerifyResult{OK: false, EntriesChecked: i + 1, BadSeq: entry.Seq}, nil
+		}
+		prevHash = entry.Hash
+	}
+	return &VerifyResult{OK: true, EntriesChecked: len(entries)}, nil
+}
+
+// UserBalance returns userID's net ledger-tracked balance in cents: total
+// credited to "user:<userID>" minus total debited from it. Used by the
+// reconciliation job to compare against Commerce's BalanceResponse.
+func (l *Ledger) UserBalance(ctx context.Context, userID string) (int64, error) {
+	credited, debited, err := l.store.SumLedgerAccountCents(ctx, UserAccount(userID))
+	if err != nil {
+		return 0, fmt.Errorf("sum ledger account: %w", err)
+	}
+	return credited - debited, nil
+}
+
+// UserAccount is the ledger account identifier for a user's available
+// balance.
+func UserAccount(userID string) string {
+	return "user:" + userID
+}
+
+// HoldAccount is the ledger account identifier for funds reserved by a
+// single open hold, released back to UserAccount (ReleaseHold) or moved on
+// to RevenueAccount (CaptureHold) once the hold resolves.
+func HoldAccount(holdID string) string {
+	return "hold:" + holdID
+}
+
+// PackageAccount is the ledger account identifier for a purchased
+// PackageBalance, credited by PackageCredit and drawn down as usage is
+// metered against it.
+func PackageAccount(packageBalanceID string) string {
+	return "package:" + packageBalanceID
+}
+
+// RevenueAccount is the ledger account identifier for Commerce's own side
+// of every debit/refund — money that left or returned to the platform
+// itself, as opposed to a specific user's or hold's balance.
+const RevenueAccount = "commerce:revenue"
+
+// Subscribe returns a channel of newly recorded entries, for the SSE stream.
+func (l *Ledger) Subscribe() (string, <-chan *types.LedgerEntry) {
+	id := uuid.New().String()
+	ch := make(chan *types.LedgerEntry, 32)
+
+	l.subMu.Lock()
+	l.subscribers[id] = ch
+	l.subMu.Unlock()
+
+	return id, ch
+}
+
+// Unsubscribe removes a subscriber registered via Subscribe.
+func (l *Ledger) Unsubscribe(id string) {
+	l.subMu.Lock()
+	defer l.subMu.Unlock()
+
+	if ch, ok := l.subscribers[id]; ok {
+		close(ch)
+		delete(l.subscribers, id)
+	}
+}
+
+// Recent returns up to limit of the most recently recorded entries, for a
+// new SSE subscriber's initial batch.
+func (l *Ledger) Recent(limit int) []*types.LedgerEntry {
+	l.subMu.RLock()
+	defer l.subMu.RUnlock()
+
+	if limit <= 0 || limit > len(l.buffer) {
+		limit = len(l.buffer)
+	}
+	start := len(l.buffer) - limit
+	result := make([]*types.LedgerEntry, limit)
+	copy(result, l.buffer[start:])
+	return result
+}
+
+func (l *Ledger) publish(entry *types.LedgerEntry) {
+	l.subMu.Lock()
+	if len(l.buffer) >= defaultBufferSize {
+		l.buffer = l.buffer[1:]
+	}
+	l.buffer = append(l.buffer, entry)
+
+	subs := make([]chan *types.LedgerEntry, 0, len(l.subscribers))
+	for _, ch := range l.subscribers {
+		subs = append(subs, ch)
+	}
+	l.subMu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- entry:
+		default:
+			// Drop if subscriber is slow.
+		}
+	}
+}
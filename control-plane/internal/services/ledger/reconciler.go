@@ -0,0 +1,112 @@
+package ledger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hanzoai/agents/control-plane/internal/logger"
+)
+
+// defaultReconcileInterval is how often Reconciler diffs ledger totals
+// against Commerce, absent a configured interval.
+const defaultReconcileInterval = 15 * time.Minute
+
+// BalanceChecker is the subset of services.BillingService that Reconciler
+// consults, defined here rather than imported so this package doesn't
+// depend on internal/services (which already depends on this package, to
+// wire a Ledger into BillingService). Satisfied structurally by
+// *services.BillingService.
+type BalanceChecker interface {
+	CheckBalance(ctx context.Context, userID string) (int64, error)
+}
+
+// Reconciler periodically diffs each user's ledger-tracked balance
+// (Ledger.UserBalance) against Commerce's own balance (BalanceChecker) and
+// logs an error on drift, since an undetected mismatch would mean the
+// local ledger's audit log no longer reflects what Commerce actually
+// charged.
+type Reconciler struct {
+	ledger   *Ledger
+	balances BalanceChecker
+	interval time.Duration
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewReconciler creates a Reconciler. An interval of 0 defaults to 15m.
+func NewReconciler(ledger *Ledger, balances BalanceChecker, interval time.Duration) *Reconciler {
+	if interval <= 0 {
+		interval = defaultReconcileInterval
+	}
+	return &Reconciler{
+		ledger:   ledger,
+		balances: balances,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start runs the reconcile loop until Stop is called.
+func (r *Reconciler) Start() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	logger.Logger.Info().Dur("interval", r.interval).Msg("billing ledger reconciler started")
+
+	for {
+		select {
+		case <-r.stopCh:
+			logger.Logger.Info().Msg("billing ledger reconciler stopped")
+			return
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+// Stop terminates the reconcile loop started by Start.
+func (r *Reconciler) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+func (r *Reconciler) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	users, err := r.ledger.store.ListLedgerUsers(ctx)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("billing ledger reconciler: failed to list users")
+		return
+	}
+
+	for _, userID := range users {
+		r.reconcileUser(ctx, userID)
+	}
+}
+
+func (r *Reconciler) reconcileUser(ctx context.Context, userID string) {
+	ledgerBalance, err := r.ledger.UserBalance(ctx, userID)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("user", userID).Msg("billing ledger reconciler: failed to compute ledger balance")
+		return
+	}
+
+	commerceBalance, err := r.balances.CheckBalance(ctx, userID)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("user", userID).Msg("billing ledger reconciler: failed to fetch commerce balance")
+		return
+	}
+
+	if drift := commerceBalance - ledgerBalance; drift != 0 {
+		logger.Logger.Error().
+			Str("user", userID).
+			Int64("ledger_balance_cents", ledgerBalance).
+			Int64("commerce_balance_cents", commerceBalance).
+			Int64("drift_cents", drift).
+			Msg("billing ledger reconciler: ledger and commerce balances have drifted")
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hanzoai/agents/control-plane/internal/logger"
+	"github.com/hanzoai/agents/control-plane/internal/services/ledger"
+	"github.com/hanzoai/agents/control-plane/internal/storage"
+	"github.com/hanzoai/agents/control-plane/pkg/types"
+)
+
+// PricingService sells prepaid Package plans (Storj's PackagePlans model)
+// alongside BillingService's metered Commerce billing: a user draws down a
+// purchased package's included minutes/tokens before falling back to
+// per-use debits.
+type PricingService struct {
+	billing *BillingService
+	store   *storage.LocalStorage
+}
+
+// NewPricingService creates a PricingService. billing is used to charge
+// Commerce once per package purchase; store persists package plans and
+// per-user balances.
+func NewPricingService(billing *BillingService, store *storage.LocalStorage) *PricingService {
+	return &PricingService{billing: billing, store: store}
+}
+
+// ListPackages returns every configured package plan.
+func (p *PricingService) ListPackages(ctx context.Context) ([]*types.Package, error) {
+	return p.store.ListPackages(ctx)
+}
+
+// PurchasePackage debits Commerce once for packageID's price and credits
+// userID a new PackageBalance seeded with the package's included
+// minutes/tokens.
+func (p *PricingService) PurchasePackage(ctx context.Context, userID, packageID, teamID string) (*types.PackageBalance, error) {
+	pkg, err := p.store.GetPackage(ctx, packageID)
+	if err != nil {
+		return nil, fmt.Errorf("look up package: %w", err)
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("package %q not found", packageID)
+	}
+
+	txID, err := p.billing.DebitUpfront(ctx, DebitParams{
+		User:               userID,
+		AmountCents:        pkg.PriceCents,
+		Notes:              fmt.Sprintf("package purchase: %s", pkg.Name),
+		PartnerAttribution: pkg.PartnerAttribution,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("debit package purchase: %w", err)
+	}
+
+	remainingMinutes := make(map[string]float64, len(pkg.IncludedMinutes))
+	for platform, minutes := range pkg.IncludedMinutes {
+		remainingMinutes[platform] = minutes
+	}
+
+	now := time.Now().UTC()
+	balance := &types.PackageBalance{
+		ID:               uuid.New().String(),
+		UserID:           userID,
+		PackageID:        pkg.ID,
+		TeamID:           teamID,
+		RemainingMinutes: remainingMinutes,
+		RemainingTokens:  pkg.IncludedTokens,
+		TransactionID:    txID,
+		PurchasedAt:      now,
+	}
+	if pkg.ValidityDays > 0 {
+		expires := now.AddDate(0, 0, pkg.ValidityDays)
+		balance.ExpiresAt = &expires
+	}
+
+	if err := p.store.CreatePackageBalance(ctx, balance); err != nil {
+		return nil, fmt.Errorf("persist package balance: %w", err)
+	}
+
+	// DebitUpfront above already recorded the user's payment as a Debit
+	// entry (user -> commerce revenue); this entry tracks where that money
+	// went on the ledger's books — into the purchased package balance
+	// itself, for audit purposes. A no-op if BillingService has no Ledger
+	// installed.
+	if _, err := p.billing.Ledger().Record(ctx, ledger.RecordParams{
+		User:          userID,
+		Action:        types.LedgerActionPackageCredit,
+		DebitAccount:  ledger.RevenueAccount,
+		CreditAccount: ledger.PackageAccount(balance.ID),
+		AmountCents:   pkg.PriceCents,
+		Metadata:      map[string]interface{}{"package_id": pkg.ID, "transaction_id": balance.TransactionID},
+	}); err != nil {
+		logger.Logger.Error().Err(err).Str("user", userID).Str("balance_id", balance.ID).
+			Msg("failed to record package credit in billing ledger")
+	}
+
+	return balance, nil
+}
+
+// DrawDown consumes up to minutes of platform and tokens from userID's
+// package balances, most recently purchased first, matching on platform. It
+// returns covered=true only if the full request was absorbed by package
+// balances; a partially-covered request is left uncharged against the
+// package (the caller falls back to metered billing for the whole amount)
+// so a single execution's cost isn't split across two billing paths.
+func (p *PricingService) DrawDown(ctx context.Context, userID, platform, teamID string, minutes float64, tokens int64) (bool, error) {
+	balances, err := p.store.ListPackageBalances(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("list package balances: %w", err)
+	}
+
+	for _, balance := range balances {
+		if balance.IsExpired() {
+			continue
+		}
+		if teamID != "" && balance.TeamID != "" && balance.TeamID != teamID {
+			continue
+		}
+		available := balance.RemainingMinutes[platform]
+		if available < minutes || balance.RemainingTokens < tokens {
+			continue
+		}
+
+		balance.RemainingMinutes[platform] = available - minutes
+		balance.RemainingTokens -= tokens
+		if err := p.store.UpdatePackageBalance(ctx, balance); err != nil {
+			logger.Logger.Error().Err(err).Str("user", userID).Str("balance_id", balance.ID).
+				Msg("failed to persist package draw-down")
+			return false, fmt.Errorf("persist package draw-down: %w", err)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// GetSummary returns userID's remaining package balances, for
+// GetSummaryHandler to surface alongside metered billing info.
+func (p *PricingService) GetSummary(ctx context.Context, userID string) ([]*types.PackageBalance, error) {
+	balances, err := p.store.ListPackageBalances(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*types.PackageBalance, 0, len(balances))
+	for _, b := range balances {
+		if !b.IsExpired() {
+			active = append(active, b)
+		}
+	}
+	return active, nil
+}
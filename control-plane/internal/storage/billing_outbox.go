@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BillingOutboxStatus is the lifecycle state of a BillingDebitOutboxModel row.
+type BillingOutboxStatus string
+
+const (
+	BillingOutboxPending    BillingOutboxStatus = "pending"
+	BillingOutboxCompleted  BillingOutboxStatus = "completed"
+	BillingOutboxDeadLetter BillingOutboxStatus = "dead_letter"
+)
+
+// BillingDebitOutboxModel is a durable record of a debit intent
+// (DebitActualCost/DebitUpfront), written atomically with the intent so a
+// failed Commerce call can be retried by BillingService's drain worker
+// instead of being silently dropped.
+type BillingDebitOutboxModel struct {
+	ID string `gorm:"primaryKey"`
+	// IdempotencyKey is sent as the Idempotency-Key header on every retry,
+	// so Commerce dedupes a redelivered debit instead of double-charging.
+	IdempotencyKey string `gorm:"uniqueIndex"`
+	User           string
+	AmountCents    int64
+	Currency       string
+	Model          string
+	Provider       string
+	Tokens         int
+	ExecutionID    string
+	BotID          string
+	Notes          string
+	Status         string `gorm:"index"`
+	Attempts       int
+	// NextAttemptAt is when the drain worker will next try this row;
+	// advanced by exponential backoff after each failed attempt.
+	NextAttemptAt time.Time `gorm:"index"`
+	LastError     string
+	// TransactionID is filled in once Commerce confirms the debit,
+	// closing out the row (Status becomes completed).
+	TransactionID string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (BillingDebitOutboxModel) TableName() string { return "billing_debit_outbox" }
+
+// CreateBillingOutboxRecord writes a new pending outbox row, to be called
+// atomically alongside recording the debit intent.
+func (ls *LocalStorage) CreateBillingOutboxRecord(ctx context.Context, record *BillingDebitOutboxModel) error {
+	defer observeOp("CreateBillingOutboxRecord", "billing_debit_outbox", time.Now())
+
+	gormDB, err := ls.txOrSession(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	if result := gormDB.Create(record); result.Error != nil {
+		return fmt.Errorf("failed to create billing outbox record: %w", result.Error)
+	}
+	return nil
+}
+
+// UpdateBillingOutboxRecord persists changes to an existing outbox row
+// (attempt count, next retry time, status, captured transaction ID, ...).
+func (ls *LocalStorage) UpdateBillingOutboxRecord(ctx context.Context, record *BillingDebitOutboxModel) error {
+	defer observeOp("UpdateBillingOutboxRecord", "billing_debit_outbox", time.Now())
+
+	gormDB, err := ls.txOrSession(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	if result := gormDB.Save(record); result.Error != nil {
+		return fmt.Errorf("failed to update billing outbox record: %w", result.Error)
+	}
+	return nil
+}
+
+// GetBillingOutboxRecordByIdempotencyKey looks up an existing outbox row by
+// its idempotency key, so DebitActualCost/DebitUpfront can detect (and
+// return) an in-flight or already-completed debit instead of enqueueing a
+// duplicate for the same intent.
+func (ls *LocalStorage) GetBillingOutboxRecordByIdempotencyKey(ctx context.Context, key string) (*BillingDebitOutboxModel, error) {
+	defer observeOp("GetBillingOutboxRecordByIdempotencyKey", "billing_debit_outbox", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var record BillingDebitOutboxModel
+	if err := gormDB.Where("idempotency_key = ?", key).Take(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get billing outbox record: %w", err)
+	}
+	return &record, nil
+}
+
+// ListDueBillingOutboxRecords returns pending rows whose NextAttemptAt has
+// passed, for the drain worker's next retry batch.
+func (ls *LocalStorage) ListDueBillingOutboxRecords(ctx context.Context, limit int) ([]*BillingDebitOutboxModel, error) {
+	defer observeOp("ListDueBillingOutboxRecords", "billing_debit_outbox", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	query := gormDB.Where("status = ? AND next_attempt_at <= ?", string(BillingOutboxPending), time.Now().UTC()).
+		Order("next_attempt_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var records []*BillingDebitOutboxModel
+	if err := query.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list due billing outbox records: %w", err)
+	}
+	return records, nil
+}
+
+// ListDeadLetterBillingOutboxRecords returns every row that exhausted its
+// retry budget, for the admin dead-letter view.
+func (ls *LocalStorage) ListDeadLetterBillingOutboxRecords(ctx context.Context) ([]*BillingDebitOutboxModel, error) {
+	defer observeOp("ListDeadLetterBillingOutboxRecords", "billing_debit_outbox", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var records []*BillingDebitOutboxModel
+	if err := gormDB.Where("status = ?", string(BillingOutboxDeadLetter)).
+		Order("updated_at DESC").
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered billing outbox records: %w", err)
+	}
+	return records, nil
+}
+
+// CountBillingOutboxByStatus returns how many outbox rows are currently in
+// status, for the queue-depth/DLQ-size Prometheus gauges.
+func (ls *LocalStorage) CountBillingOutboxByStatus(ctx context.Context, status BillingOutboxStatus) (int, error) {
+	defer observeOp("CountBillingOutboxByStatus", "billing_debit_outbox", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var count int64
+	if err := gormDB.Model(&BillingDebitOutboxModel{}).Where("status = ?", string(status)).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count billing outbox records: %w", err)
+	}
+	return int(count), nil
+}
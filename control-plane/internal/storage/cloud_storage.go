@@ -13,6 +13,8 @@ import (
 // --- CloudInstance CRUD ---
 
 func (ls *LocalStorage) CreateCloudInstance(ctx context.Context, instance *types.CloudInstance) error {
+	defer observeOp("CreateCloudInstance", "cloud_instances", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to prepare gorm: %w", err)
@@ -30,6 +32,8 @@ func (ls *LocalStorage) CreateCloudInstance(ctx context.Context, instance *types
 }
 
 func (ls *LocalStorage) GetCloudInstance(ctx context.Context, id string) (*types.CloudInstance, error) {
+	defer observeOp("GetCloudInstance", "cloud_instances", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
@@ -47,6 +51,8 @@ func (ls *LocalStorage) GetCloudInstance(ctx context.Context, id string) (*types
 }
 
 func (ls *LocalStorage) UpdateCloudInstance(ctx context.Context, instance *types.CloudInstance) error {
+	defer observeOp("UpdateCloudInstance", "cloud_instances", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to prepare gorm: %w", err)
@@ -64,6 +70,8 @@ func (ls *LocalStorage) UpdateCloudInstance(ctx context.Context, instance *types
 }
 
 func (ls *LocalStorage) ListCloudInstances(ctx context.Context, filters types.InstanceFilters) ([]*types.CloudInstance, error) {
+	defer observeOp("ListCloudInstances", "cloud_instances", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
@@ -110,6 +118,8 @@ func (ls *LocalStorage) ListCloudInstances(ctx context.Context, filters types.In
 }
 
 func (ls *LocalStorage) DeleteCloudInstance(ctx context.Context, id string) error {
+	defer observeOp("DeleteCloudInstance", "cloud_instances", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to prepare gorm: %w", err)
@@ -122,6 +132,8 @@ func (ls *LocalStorage) DeleteCloudInstance(ctx context.Context, id string) erro
 }
 
 func (ls *LocalStorage) GetCloudInstanceByAgentNodeID(ctx context.Context, agentNodeID string) (*types.CloudInstance, error) {
+	defer observeOp("GetCloudInstanceByAgentNodeID", "cloud_instances", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
@@ -139,6 +151,8 @@ func (ls *LocalStorage) GetCloudInstanceByAgentNodeID(ctx context.Context, agent
 }
 
 func (ls *LocalStorage) CountCloudInstancesByTeam(ctx context.Context, teamID string) (int, error) {
+	defer observeOp("CountCloudInstancesByTeam", "cloud_instances", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to prepare gorm: %w", err)
@@ -153,9 +167,28 @@ func (ls *LocalStorage) CountCloudInstancesByTeam(ctx context.Context, teamID st
 	return int(count), nil
 }
 
+// PruneTerminatedInstances deletes CloudInstance rows that have been in the
+// terminated state since before olderThan, returning the number removed.
+func (ls *LocalStorage) PruneTerminatedInstances(ctx context.Context, olderThan time.Time) (int, error) {
+	defer observeOp("PruneTerminatedInstances", "cloud_instances", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	result := gormDB.Where("state = ? AND terminated_at < ?", "terminated", olderThan).Delete(&CloudInstanceModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to prune terminated instances: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
 // --- DedicatedHost CRUD ---
 
 func (ls *LocalStorage) CreateDedicatedHost(ctx context.Context, host *types.DedicatedHost) error {
+	defer observeOp("CreateDedicatedHost", "dedicated_hosts", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to prepare gorm: %w", err)
@@ -169,6 +202,8 @@ func (ls *LocalStorage) CreateDedicatedHost(ctx context.Context, host *types.Ded
 }
 
 func (ls *LocalStorage) GetDedicatedHost(ctx context.Context, id string) (*types.DedicatedHost, error) {
+	defer observeOp("GetDedicatedHost", "dedicated_hosts", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
@@ -186,6 +221,8 @@ func (ls *LocalStorage) GetDedicatedHost(ctx context.Context, id string) (*types
 }
 
 func (ls *LocalStorage) UpdateDedicatedHost(ctx context.Context, host *types.DedicatedHost) error {
+	defer observeOp("UpdateDedicatedHost", "dedicated_hosts", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to prepare gorm: %w", err)
@@ -199,6 +236,8 @@ func (ls *LocalStorage) UpdateDedicatedHost(ctx context.Context, host *types.Ded
 }
 
 func (ls *LocalStorage) ListDedicatedHosts(ctx context.Context) ([]*types.DedicatedHost, error) {
+	defer observeOp("ListDedicatedHosts", "dedicated_hosts", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
@@ -217,6 +256,8 @@ func (ls *LocalStorage) ListDedicatedHosts(ctx context.Context) ([]*types.Dedica
 }
 
 func (ls *LocalStorage) GetAvailableDedicatedHost(ctx context.Context) (*types.DedicatedHost, error) {
+	defer observeOp("GetAvailableDedicatedHost", "dedicated_hosts", time.Now())
+
 	gormDB, err := ls.gormWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
@@ -275,6 +316,8 @@ func cloudInstanceToModel(inst *types.CloudInstance) (*CloudInstanceModel, error
 		HourlyRateCents:  inst.HourlyRateCents,
 		AccruedCostCents: inst.AccruedCostCents,
 		BillingTier:      inst.BillingTier,
+		BillingHoldID:    inst.BillingHoldID,
+		WindowsPasswordReady: inst.WindowsPasswordReady,
 		ConnectionInfo:   connInfoBytes,
 		Metadata:         inst.Metadata,
 		Tags:             tagsBytes,
@@ -305,6 +348,8 @@ func modelToCloudInstance(m *CloudInstanceModel) (*types.CloudInstance, error) {
 		HourlyRateCents:  m.HourlyRateCents,
 		AccruedCostCents: m.AccruedCostCents,
 		BillingTier:      m.BillingTier,
+		BillingHoldID:    m.BillingHoldID,
+		WindowsPasswordReady: m.WindowsPasswordReady,
 		Metadata:         m.Metadata,
 		ErrorMessage:     m.ErrorMessage,
 		RequestedAt:      m.RequestedAt,
@@ -338,6 +383,8 @@ func dedicatedHostToModel(h *types.DedicatedHost) *DedicatedHostModel {
 		InstanceType:      h.InstanceType,
 		State:             h.State,
 		CurrentInstanceID: h.CurrentInstanceID,
+		AvailabilityZone:  h.AvailabilityZone,
+		AutoAllocated:     h.AutoAllocated,
 		AllocatedAt:       h.AllocatedAt,
 		ReleasedAt:        h.ReleasedAt,
 		MinAllocationSec:  int64(h.MinAllocation / time.Second),
@@ -351,6 +398,8 @@ func modelToDedicatedHost(m *DedicatedHostModel) *types.DedicatedHost {
 		InstanceType:      m.InstanceType,
 		State:             m.State,
 		CurrentInstanceID: m.CurrentInstanceID,
+		AvailabilityZone:  m.AvailabilityZone,
+		AutoAllocated:     m.AutoAllocated,
 		AllocatedAt:       m.AllocatedAt,
 		ReleasedAt:        m.ReleasedAt,
 		MinAllocation:     time.Duration(m.MinAllocationSec) * time.Second,
@@ -358,3 +407,81 @@ func modelToDedicatedHost(m *DedicatedHostModel) *types.DedicatedHost {
 		UpdatedAt:         m.UpdatedAt,
 	}
 }
+
+// --- SSHKeyPair CRUD ---
+
+func (ls *LocalStorage) CreateSSHKeyPair(ctx context.Context, keyPair *types.SSHKeyPair) error {
+	defer observeOp("CreateSSHKeyPair", "ssh_key_pairs", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	model := sshKeyPairToModel(keyPair)
+	if result := gormDB.Create(model); result.Error != nil {
+		return fmt.Errorf("failed to create ssh key pair: %w", result.Error)
+	}
+	return nil
+}
+
+func (ls *LocalStorage) GetSSHKeyPairByFingerprint(ctx context.Context, fingerprint string) (*types.SSHKeyPair, error) {
+	defer observeOp("GetSSHKeyPairByFingerprint", "ssh_key_pairs", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var model SSHKeyPairModel
+	if err := gormDB.Where("fingerprint = ?", fingerprint).Take(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("ssh key pair not found for fingerprint: %s", fingerprint)
+		}
+		return nil, fmt.Errorf("failed to get ssh key pair: %w", err)
+	}
+
+	return modelToSSHKeyPair(&model), nil
+}
+
+func (ls *LocalStorage) GetSSHKeyPairByTeam(ctx context.Context, teamID string) (*types.SSHKeyPair, error) {
+	defer observeOp("GetSSHKeyPairByTeam", "ssh_key_pairs", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var model SSHKeyPairModel
+	if err := gormDB.Where("team_id = ?", teamID).Order("created_at DESC").Take(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("no ssh key pair cached for team: %s", teamID)
+		}
+		return nil, fmt.Errorf("failed to get ssh key pair for team: %w", err)
+	}
+
+	return modelToSSHKeyPair(&model), nil
+}
+
+func sshKeyPairToModel(k *types.SSHKeyPair) *SSHKeyPairModel {
+	return &SSHKeyPairModel{
+		ID:              k.ID,
+		TeamID:          k.TeamID,
+		KeyName:         k.KeyName,
+		Fingerprint:     k.Fingerprint,
+		FingerprintSHA1: k.FingerprintSHA1,
+		PublicKeyPEM:    k.PublicKeyPEM,
+	}
+}
+
+func modelToSSHKeyPair(m *SSHKeyPairModel) *types.SSHKeyPair {
+	return &types.SSHKeyPair{
+		ID:              m.ID,
+		TeamID:          m.TeamID,
+		KeyName:         m.KeyName,
+		Fingerprint:     m.Fingerprint,
+		FingerprintSHA1: m.FingerprintSHA1,
+		PublicKeyPEM:    m.PublicKeyPEM,
+		CreatedAt:       m.CreatedAt,
+	}
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hanzoai/agents/control-plane/pkg/types"
+	"gorm.io/gorm"
+)
+
+// --- AccountFreeze CRUD ---
+
+// AccountFreezeModel is the gorm row backing a types.AccountFreeze.
+type AccountFreezeModel struct {
+	ID             string `gorm:"primaryKey"`
+	UserID         string `gorm:"index"`
+	Type           string
+	Reason         string
+	Metadata       []byte
+	GracePeriodSec int64
+	EscalatedAt    *time.Time
+	UnfrozenAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (AccountFreezeModel) TableName() string { return "account_freezes" }
+
+func (ls *LocalStorage) CreateAccountFreeze(ctx context.Context, freeze *types.AccountFreeze) error {
+	defer observeOp("CreateAccountFreeze", "account_freezes", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	model := accountFreezeToModel(freeze)
+	if result := gormDB.Create(model); result.Error != nil {
+		return fmt.Errorf("failed to create account freeze: %w", result.Error)
+	}
+	return nil
+}
+
+func (ls *LocalStorage) UpdateAccountFreeze(ctx context.Context, freeze *types.AccountFreeze) error {
+	defer observeOp("UpdateAccountFreeze", "account_freezes", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	model := accountFreezeToModel(freeze)
+	if result := gormDB.Save(model); result.Error != nil {
+		return fmt.Errorf("failed to update account freeze: %w", result.Error)
+	}
+	return nil
+}
+
+// GetActiveAccountFreeze returns the most recent still-active freeze for
+// userID, or nil if the account isn't frozen.
+func (ls *LocalStorage) GetActiveAccountFreeze(ctx context.Context, userID string) (*types.AccountFreeze, error) {
+	defer observeOp("GetActiveAccountFreeze", "account_freezes", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var model AccountFreezeModel
+	if err := gormDB.Where("user_id = ? AND unfrozen_at IS NULL", userID).
+		Order("created_at DESC").
+		Take(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get active account freeze: %w", err)
+	}
+
+	return modelToAccountFreeze(&model), nil
+}
+
+// ListAccountFreezes returns freezes for userID, most recent first. If
+// userID is empty, every account's freezes are returned (for the admin
+// listing view). If activeOnly is set, only freezes without an
+// UnfrozenAt are returned.
+func (ls *LocalStorage) ListAccountFreezes(ctx context.Context, userID string, activeOnly bool) ([]*types.AccountFreeze, error) {
+	defer observeOp("ListAccountFreezes", "account_freezes", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	query := gormDB.Order("created_at DESC")
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if activeOnly {
+		query = query.Where("unfrozen_at IS NULL")
+	}
+
+	var models []AccountFreezeModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list account freezes: %w", err)
+	}
+
+	freezes := make([]*types.AccountFreeze, 0, len(models))
+	for _, m := range models {
+		freezes = append(freezes, modelToAccountFreeze(&m))
+	}
+	return freezes, nil
+}
+
+func accountFreezeToModel(f *types.AccountFreeze) *AccountFreezeModel {
+	return &AccountFreezeModel{
+		ID:             f.ID,
+		UserID:         f.UserID,
+		Type:           string(f.Type),
+		Reason:         f.Reason,
+		Metadata:       f.Metadata,
+		GracePeriodSec: int64(f.GracePeriod / time.Second),
+		EscalatedAt:    f.EscalatedAt,
+		UnfrozenAt:     f.UnfrozenAt,
+		CreatedAt:      f.CreatedAt,
+		UpdatedAt:      f.UpdatedAt,
+	}
+}
+
+func modelToAccountFreeze(m *AccountFreezeModel) *types.AccountFreeze {
+	return &types.AccountFreeze{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Type:        types.FreezeType(m.Type),
+		Reason:      m.Reason,
+		Metadata:    m.Metadata,
+		GracePeriod: time.Duration(m.GracePeriodSec) * time.Second,
+		EscalatedAt: m.EscalatedAt,
+		UnfrozenAt:  m.UnfrozenAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
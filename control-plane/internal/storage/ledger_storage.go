@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hanzoai/agents/control-plane/pkg/types"
+	"gorm.io/gorm"
+)
+
+// LedgerEntryModel is the gorm row backing a types.LedgerEntry.
+type LedgerEntryModel struct {
+	ID            string `gorm:"primaryKey"`
+	Seq           int64  `gorm:"uniqueIndex"`
+	PrevHash      string
+	Hash          string
+	Timestamp     time.Time
+	Actor         string
+	User          string `gorm:"index"`
+	Action        string
+	DebitAccount  string `gorm:"index"`
+	CreditAccount string `gorm:"index"`
+	AmountCents   int64
+	Currency      string
+	Metadata      []byte // JSON-encoded map[string]interface{}
+}
+
+func (LedgerEntryModel) TableName() string { return "billing_ledger_entries" }
+
+// CreateLedgerEntry persists a new ledger row. entry.Seq/PrevHash/Hash must
+// already be set by the caller (ledger.Ledger.Record computes them while
+// holding its append lock, so two entries can never race for the same seq).
+func (ls *LocalStorage) CreateLedgerEntry(ctx context.Context, entry *types.LedgerEntry) error {
+	defer observeOp("CreateLedgerEntry", "billing_ledger_entries", time.Now())
+
+	gormDB, err := ls.txOrSession(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	model, err := ledgerEntryToModel(entry)
+	if err != nil {
+		return err
+	}
+	if result := gormDB.Create(model); result.Error != nil {
+		return fmt.Errorf("failed to create ledger entry: %w", result.Error)
+	}
+	return nil
+}
+
+// GetLastLedgerEntry returns the highest-seq ledger entry, or nil if the
+// ledger is empty, so Ledger.Record can chain the next entry's PrevHash/Seq
+// off of it.
+func (ls *LocalStorage) GetLastLedgerEntry(ctx context.Context) (*types.LedgerEntry, error) {
+	defer observeOp("GetLastLedgerEntry", "billing_ledger_entries", time.Now())
+
+	gormDB, err := ls.txOrSession(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var model LedgerEntryModel
+	if err := gormDB.Order("seq DESC").Take(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get last ledger entry: %w", err)
+	}
+	return modelToLedgerEntry(&model)
+}
+
+// ListLedgerEntries returns userID's ledger entries oldest-first, paginated
+// by limit/offset, for the UI audit log.
+func (ls *LocalStorage) ListLedgerEntries(ctx context.Context, userID string, limit, offset int) ([]*types.LedgerEntry, error) {
+	defer observeOp("ListLedgerEntries", "billing_ledger_entries", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	query := gormDB.Where("user = ?", userID).Order("seq ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var models []LedgerEntryModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+	return modelsToLedgerEntries(models)
+}
+
+// ListAllLedgerEntries returns every ledger entry ever recorded, oldest
+// first, for Ledger.Verify to walk the whole hash chain.
+func (ls *LocalStorage) ListAllLedgerEntries(ctx context.Context) ([]*types.LedgerEntry, error) {
+	defer observeOp("ListAllLedgerEntries", "billing_ledger_entries", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var models []LedgerEntryModel
+	if err := gormDB.Order("seq ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list all ledger entries: %w", err)
+	}
+	return modelsToLedgerEntries(models)
+}
+
+// ListLedgerUsers returns every distinct non-empty User that has at least
+// one ledger entry, for the reconciliation job to iterate over.
+func (ls *LocalStorage) ListLedgerUsers(ctx context.Context) ([]string, error) {
+	defer observeOp("ListLedgerUsers", "billing_ledger_entries", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var users []string
+	if err := gormDB.Model(&LedgerEntryModel{}).
+		Where("user != ?", "").
+		Distinct("user").
+		Pluck("user", &users).Error; err != nil {
+		return nil, fmt.Errorf("failed to list ledger users: %w", err)
+	}
+	return users, nil
+}
+
+// SumLedgerAccountCents returns the total cents credited to and debited from
+// account across every ledger entry, so Ledger.UserBalance can net them
+// into that account's running balance.
+func (ls *LocalStorage) SumLedgerAccountCents(ctx context.Context, account string) (creditedCents int64, debitedCents int64, err error) {
+	defer observeOp("SumLedgerAccountCents", "billing_ledger_entries", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	if err := gormDB.Model(&LedgerEntryModel{}).
+		Where("credit_account = ?", account).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Scan(&creditedCents).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to sum ledger credits: %w", err)
+	}
+	if err := gormDB.Model(&LedgerEntryModel{}).
+		Where("debit_account = ?", account).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Scan(&debitedCents).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to sum ledger debits: %w", err)
+	}
+	return creditedCents, debitedCents, nil
+}
+
+func ledgerEntryToModel(e *types.LedgerEntry) (*LedgerEntryModel, error) {
+	metadata, err := json.Marshal(e.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshal ledger entry metadata: %w", err)
+	}
+	return &LedgerEntryModel{
+		ID:            e.ID,
+		Seq:           e.Seq,
+		PrevHash:      e.PrevHash,
+		Hash:          e.Hash,
+		Timestamp:     e.Timestamp,
+		Actor:         e.Actor,
+		User:          e.User,
+		Action:        string(e.Action),
+		DebitAccount:  e.DebitAccount,
+		CreditAccount: e.CreditAccount,
+		AmountCents:   e.AmountCents,
+		Currency:      e.Currency,
+		Metadata:      metadata,
+	}, nil
+}
+
+func modelToLedgerEntry(m *LedgerEntryModel) (*types.LedgerEntry, error) {
+	metadata := map[string]interface{}{}
+	if len(m.Metadata) > 0 {
+		if err := json.Unmarshal(m.Metadata, &metadata); err != nil {
+			return nil, fmt.Errorf("unmarshal ledger entry metadata: %w", err)
+		}
+	}
+	return &types.LedgerEntry{
+		ID:            m.ID,
+		Seq:           m.Seq,
+		PrevHash:      m.PrevHash,
+		Hash:          m.Hash,
+		Timestamp:     m.Timestamp,
+		Actor:         m.Actor,
+		User:          m.User,
+		Action:        types.LedgerAction(m.Action),
+		DebitAccount:  m.DebitAccount,
+		CreditAccount: m.CreditAccount,
+		AmountCents:   m.AmountCents,
+		Currency:      m.Currency,
+		Metadata:      metadata,
+	}, nil
+}
+
+func modelsToLedgerEntries(models []LedgerEntryModel) ([]*types.LedgerEntry, error) {
+	entries := make([]*types.LedgerEntry, 0, len(models))
+	for _, m := range models {
+		entry, err := modelToLedgerEntry(&m)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/hanzoai/agents/control-plane/internal/metrics"
+)
+
+// opMetrics holds the histogram used to time LocalStorage's CRUD methods.
+type opMetrics struct {
+	duration *prometheus.HistogramVec
+}
+
+func newOpMetrics(reg prometheus.Registerer) *opMetrics {
+	r := metrics.New(reg)
+	return &opMetrics{
+		duration: r.HistogramVec(prometheus.HistogramOpts{
+			Name: "hanzo_storage_op_duration_seconds",
+			Help: "Duration of LocalStorage CRUD operations, by method and table.",
+		}, []string{"method", "table"}),
+	}
+}
+
+// storageMetrics is process-wide rather than a field on LocalStorage: every
+// CRUD method in this package is a free function on *LocalStorage defined
+// outside this file, so there's no single construction point to thread a
+// registerer through. SetMetricsRegisterer gives server wiring a hook to
+// point it at a real registry; until called, operations are still timed,
+// just never exposed to a scraper.
+var storageMetrics = newOpMetrics(nil)
+
+// SetMetricsRegisterer rebuilds the storage operation-duration histogram
+// against reg. Call once during server wiring, before traffic starts;
+// concurrent CRUD calls during the swap may have their duration recorded
+// against whichever histogram was current at call time.
+func SetMetricsRegisterer(reg prometheus.Registerer) {
+	storageMetrics = newOpMetrics(reg)
+}
+
+// observeOp records how long a CRUD method took against the given table.
+func observeOp(method, table string, start time.Time) {
+	storageMetrics.duration.WithLabelValues(method, table).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hanzoai/agents/control-plane/pkg/types"
+	"gorm.io/gorm"
+)
+
+// --- Package CRUD ---
+
+// PackageModel is the gorm row backing a types.Package.
+type PackageModel struct {
+	ID                 string `gorm:"primaryKey"`
+	Name               string
+	IncludedMinutes    []byte // JSON-encoded map[string]float64
+	IncludedTokens     int64
+	PriceCents         int64
+	ValidityDays       int
+	PartnerAttribution string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (PackageModel) TableName() string { return "packages" }
+
+// CreatePackage persists a new package plan.
+func (ls *LocalStorage) CreatePackage(ctx context.Context, pkg *types.Package) error {
+	defer observeOp("CreatePackage", "packages", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	model, err := packageToModel(pkg)
+	if err != nil {
+		return err
+	}
+	if result := gormDB.Create(model); result.Error != nil {
+		return fmt.Errorf("failed to create package: %w", result.Error)
+	}
+	return nil
+}
+
+// GetPackage looks up a package plan by ID.
+func (ls *LocalStorage) GetPackage(ctx context.Context, packageID string) (*types.Package, error) {
+	defer observeOp("GetPackage", "packages", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var model PackageModel
+	if err := gormDB.Where("id = ?", packageID).Take(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get package: %w", err)
+	}
+	return modelToPackage(&model)
+}
+
+// ListPackages returns every configured package plan.
+func (ls *LocalStorage) ListPackages(ctx context.Context) ([]*types.Package, error) {
+	defer observeOp("ListPackages", "packages", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var models []PackageModel
+	if err := gormDB.Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list packages: %w", err)
+	}
+
+	packages := make([]*types.Package, 0, len(models))
+	for _, m := range models {
+		pkg, err := modelToPackage(&m)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+func packageToModel(p *types.Package) (*PackageModel, error) {
+	minutes, err := json.Marshal(p.IncludedMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("marshal included minutes: %w", err)
+	}
+	return &PackageModel{
+		ID:                 p.ID,
+		Name:               p.Name,
+		IncludedMinutes:    minutes,
+		IncludedTokens:     p.IncludedTokens,
+		PriceCents:         p.PriceCents,
+		ValidityDays:       p.ValidityDays,
+		PartnerAttribution: p.PartnerAttribution,
+	}, nil
+}
+
+func modelToPackage(m *PackageModel) (*types.Package, error) {
+	minutes := map[string]float64{}
+	if len(m.IncludedMinutes) > 0 {
+		if err := json.Unmarshal(m.IncludedMinutes, &minutes); err != nil {
+			return nil, fmt.Errorf("unmarshal included minutes: %w", err)
+		}
+	}
+	return &types.Package{
+		ID:                 m.ID,
+		Name:               m.Name,
+		IncludedMinutes:    minutes,
+		IncludedTokens:     m.IncludedTokens,
+		PriceCents:         m.PriceCents,
+		ValidityDays:       m.ValidityDays,
+		PartnerAttribution: m.PartnerAttribution,
+	}, nil
+}
+
+// --- PackageBalance CRUD ---
+
+// PackageBalanceModel is the gorm row backing a types.PackageBalance.
+type PackageBalanceModel struct {
+	ID               string `gorm:"primaryKey"`
+	UserID           string `gorm:"index"`
+	PackageID        string
+	TeamID           string
+	RemainingMinutes []byte // JSON-encoded map[string]float64
+	RemainingTokens  int64
+	TransactionID    string
+	PurchasedAt      time.Time
+	ExpiresAt        *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (PackageBalanceModel) TableName() string { return "package_balances" }
+
+// CreatePackageBalance persists a newly purchased package balance.
+func (ls *LocalStorage) CreatePackageBalance(ctx context.Context, balance *types.PackageBalance) error {
+	defer observeOp("CreatePackageBalance", "package_balances", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	model, err := packageBalanceToModel(balance)
+	if err != nil {
+		return err
+	}
+	if result := gormDB.Create(model); result.Error != nil {
+		return fmt.Errorf("failed to create package balance: %w", result.Error)
+	}
+	return nil
+}
+
+// UpdatePackageBalance persists changes to an existing balance (a draw-down
+// against RemainingMinutes/RemainingTokens).
+func (ls *LocalStorage) UpdatePackageBalance(ctx context.Context, balance *types.PackageBalance) error {
+	defer observeOp("UpdatePackageBalance", "package_balances", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	model, err := packageBalanceToModel(balance)
+	if err != nil {
+		return err
+	}
+	if result := gormDB.Save(model); result.Error != nil {
+		return fmt.Errorf("failed to update package balance: %w", result.Error)
+	}
+	return nil
+}
+
+// ListPackageBalances returns every package balance held by userID, most
+// recently purchased first, for draw-down precedence and the UI summary.
+func (ls *LocalStorage) ListPackageBalances(ctx context.Context, userID string) ([]*types.PackageBalance, error) {
+	defer observeOp("ListPackageBalances", "package_balances", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var models []PackageBalanceModel
+	if err := gormDB.Where("user_id = ?", userID).
+		Order("purchased_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list package balances: %w", err)
+	}
+
+	balances := make([]*types.PackageBalance, 0, len(models))
+	for _, m := range models {
+		balance, err := modelToPackageBalance(&m)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
+
+func packageBalanceToModel(b *types.PackageBalance) (*PackageBalanceModel, error) {
+	minutes, err := json.Marshal(b.RemainingMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("marshal remaining minutes: %w", err)
+	}
+	return &PackageBalanceModel{
+		ID:               b.ID,
+		UserID:           b.UserID,
+		PackageID:        b.PackageID,
+		TeamID:           b.TeamID,
+		RemainingMinutes: minutes,
+		RemainingTokens:  b.RemainingTokens,
+		TransactionID:    b.TransactionID,
+		PurchasedAt:      b.PurchasedAt,
+		ExpiresAt:        b.ExpiresAt,
+	}, nil
+}
+
+func modelToPackageBalance(m *PackageBalanceModel) (*types.PackageBalance, error) {
+	minutes := map[string]float64{}
+	if len(m.RemainingMinutes) > 0 {
+		if err := json.Unmarshal(m.RemainingMinutes, &minutes); err != nil {
+			return nil, fmt.Errorf("unmarshal remaining minutes: %w", err)
+		}
+	}
+	return &types.PackageBalance{
+		ID:               m.ID,
+		UserID:           m.UserID,
+		PackageID:        m.PackageID,
+		TeamID:           m.TeamID,
+		RemainingMinutes: minutes,
+		RemainingTokens:  m.RemainingTokens,
+		TransactionID:    m.TransactionID,
+		PurchasedAt:      m.PurchasedAt,
+		ExpiresAt:        m.ExpiresAt,
+	}, nil
+}
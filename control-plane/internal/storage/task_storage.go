@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hanzoai/agents/control-plane/pkg/types"
+	"gorm.io/gorm"
+)
+
+// TaskModel is the gorm model backing types.Task. Status is stored as a JSON
+// blob of the task's full history rather than a child table, the same way
+// CloudInstanceModel stores Tags/ConnectionInfo, since it's always read and
+// written as a whole alongside its parent task.
+type TaskModel struct {
+	ID         string `gorm:"primaryKey"`
+	Owner      string `gorm:"index"`
+	Op         string
+	InstanceID string `gorm:"index"`
+	State      string
+	Error      string
+	Status     []byte
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// --- Task CRUD ---
+
+func (ls *LocalStorage) CreateTask(ctx context.Context, task *types.Task) error {
+	defer observeOp("CreateTask", "tasks", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	model, err := taskToModel(task)
+	if err != nil {
+		return err
+	}
+
+	if result := gormDB.Create(model); result.Error != nil {
+		return fmt.Errorf("failed to create task: %w", result.Error)
+	}
+	return nil
+}
+
+func (ls *LocalStorage) GetTask(ctx context.Context, id string) (*types.Task, error) {
+	defer observeOp("GetTask", "tasks", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var model TaskModel
+	if err := gormDB.Where("id = ?", id).Take(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("task not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return modelToTask(&model)
+}
+
+func (ls *LocalStorage) UpdateTask(ctx context.Context, task *types.Task) error {
+	defer observeOp("UpdateTask", "tasks", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	model, err := taskToModel(task)
+	if err != nil {
+		return err
+	}
+
+	if result := gormDB.Save(model); result.Error != nil {
+		return fmt.Errorf("failed to update task: %w", result.Error)
+	}
+	return nil
+}
+
+func (ls *LocalStorage) ListTasksByOwner(ctx context.Context, owner string) ([]*types.Task, error) {
+	defer observeOp("ListTasksByOwner", "tasks", time.Now())
+
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	var models []TaskModel
+	if err := gormDB.Where("owner = ?", owner).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	tasks := make([]*types.Task, 0, len(models))
+	for _, m := range models {
+		task, err := modelToTask(&m)
+		if err != nil {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func taskToModel(task *types.Task) (*TaskModel, error) {
+	statusBytes, err := json.Marshal(task.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task status: %w", err)
+	}
+
+	return &TaskModel{
+		ID:         task.ID,
+		Owner:      task.Owner,
+		Op:         task.Op,
+		InstanceID: task.InstanceID,
+		State:      string(task.State),
+		Error:      task.Error,
+		Status:     statusBytes,
+		CreatedAt:  task.CreatedAt,
+		UpdatedAt:  task.UpdatedAt,
+	}, nil
+}
+
+func modelToTask(m *TaskModel) (*types.Task, error) {
+	task := &types.Task{
+		ID:         m.ID,
+		Owner:      m.Owner,
+		Op:         m.Op,
+		InstanceID: m.InstanceID,
+		State:      types.TaskState(m.State),
+		Error:      m.Error,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+
+	if len(m.Status) > 0 {
+		var status []types.TaskStatusEntry
+		if err := json.Unmarshal(m.Status, &status); err == nil {
+			task.Status = status
+		}
+	}
+
+	return task, nil
+}
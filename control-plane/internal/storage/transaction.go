@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// txContextKey is the context key WithTransaction stashes its *gorm.DB
+// transaction under, so a write made with the context it hands to fn
+// reuses that transaction instead of opening its own session.
+type txContextKey struct{}
+
+// WithTransaction runs fn inside a single database transaction: every
+// LocalStorage write fn makes with the ctx it's given commits or rolls back
+// together, instead of each landing (or failing) independently. Use this
+// wherever two or more writes - e.g. a billing outbox row and its paired
+// ledger entry - must never be left half-applied relative to each other.
+func (ls *LocalStorage) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	gormDB, err := ls.gormWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare gorm: %w", err)
+	}
+
+	return gormDB.Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+// txOrSession returns the transaction WithTransaction stashed in ctx, if
+// any, else opens a normal scoped session via gormWithContext. Every
+// LocalStorage method that writes or reads should call this instead of
+// gormWithContext directly, so it automatically joins a surrounding
+// WithTransaction rather than opening a second, independent session.
+func (ls *LocalStorage) txOrSession(ctx context.Context) (*gorm.DB, error) {
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return tx, nil
+	}
+	return ls.gormWithContext(ctx)
+}
@@ -24,6 +24,7 @@ const (
 	InstanceStateStopped      InstanceState = "stopped"
 	InstanceStateTerminated   InstanceState = "terminated"
 	InstanceStateFailed       InstanceState = "failed"
+	InstanceStateDraining     InstanceState = "draining" // spot interruption in progress
 )
 
 // ConnectionProtocol represents the protocol used to connect to an instance.
@@ -37,6 +38,23 @@ const (
 	ConnectionProtocolSSM  ConnectionProtocol = "ssm"
 )
 
+// ConnectionMode selects how GetConnectionInfo should reach an instance,
+// independent of the underlying provider/platform's native default
+// (e.g. a macOS instance defaults to VNC but can be asked for SSH instead).
+type ConnectionMode string
+
+const (
+	// ConnectionModeSSHDirect connects straight to the instance's public or
+	// private IP over SSH using a per-team generated/cached key pair.
+	ConnectionModeSSHDirect ConnectionMode = "ssh-direct"
+	// ConnectionModeSSHViaSSM tunnels SSH through an SSM port-forwarding
+	// session, so instances on private subnets with no inbound access are
+	// still reachable without a bastion host.
+	ConnectionModeSSHViaSSM ConnectionMode = "ssh-via-ssm"
+	// ConnectionModeVNC is the macOS default: a direct VNC connection.
+	ConnectionModeVNC ConnectionMode = "vnc"
+)
+
 // CloudInstance represents a provisioned cloud instance.
 type CloudInstance struct {
 	ID         string        `json:"id"`
@@ -66,20 +84,40 @@ type CloudInstance struct {
 	DedicatedHostID string `json:"dedicated_host_id,omitempty"`
 
 	// Billing
-	HourlyRateCents int    `json:"hourly_rate_cents,omitempty"`
-	AccruedCostCents int   `json:"accrued_cost_cents,omitempty"`
-	BillingTier     string `json:"billing_tier,omitempty"`
+	HourlyRateCents  int    `json:"hourly_rate_cents,omitempty"`
+	AccruedCostCents int    `json:"accrued_cost_cents,omitempty"`
+	BillingTier      string `json:"billing_tier,omitempty"`
+	// BillingHoldID is the Commerce hold reserving this instance's
+	// estimated cost for the duration of its run, if one was placed. A
+	// restart-time sweeper matches open holds against instances still
+	// missing a terminal capture/release to catch holds orphaned by a
+	// crash between termination and CaptureHold/ReleaseHold.
+	BillingHoldID string `json:"billing_hold_id,omitempty"`
 
 	// Connection
 	ConnectionInfo *ConnectionInfo `json:"connection_info,omitempty"`
+	// WindowsPasswordReady marks that the instance's RDP admin password has
+	// finished generating (GetPasswordData stopped returning empty) and
+	// EventWindowsPasswordReady has been published, so the poller driving
+	// that check doesn't re-publish it on every subsequent reconcile pass.
+	WindowsPasswordReady bool `json:"windows_password_ready,omitempty"`
 
 	// Metadata
-	Metadata json.RawMessage `json:"metadata,omitempty"`
+	Metadata json.RawMessage   `json:"metadata,omitempty"`
 	Tags     map[string]string `json:"tags,omitempty"`
 
 	// Error tracking
 	ErrorMessage string `json:"error_message,omitempty"`
 
+	// StateReason is a short machine-readable reason for why State is what
+	// it is, beyond the coarse InstanceState itself (e.g.
+	// "ImagePullBackOff", "CrashLoopBackOff", "InitContainerPending").
+	// Populated by providers that can observe more detail than the
+	// InstanceState enum captures; empty when State needs no elaboration.
+	StateReason string `json:"state_reason,omitempty"`
+	// StateMessage is a human-readable elaboration of StateReason.
+	StateMessage string `json:"state_message,omitempty"`
+
 	// Timestamps
 	RequestedAt   time.Time  `json:"requested_at"`
 	ProvisionedAt *time.Time `json:"provisioned_at,omitempty"`
@@ -90,13 +128,24 @@ type CloudInstance struct {
 
 // ConnectionInfo holds the connection details for a cloud instance.
 type ConnectionInfo struct {
-	Protocol ConnectionProtocol `json:"protocol"`
-	Host     string             `json:"host"`
-	Port     int                `json:"port"`
-	Username string             `json:"username,omitempty"`
-	Password string             `json:"password,omitempty"`
-	KeyData  string             `json:"key_data,omitempty"`
-	Extra    map[string]string  `json:"extra,omitempty"`
+	Protocol    ConnectionProtocol `json:"protocol"`
+	Host        string             `json:"host"`
+	Port        int                `json:"port"`
+	Username    string             `json:"username,omitempty"`
+	Password    string             `json:"password,omitempty"`
+	KeyData     string             `json:"key_data,omitempty"`
+	Extra       map[string]string  `json:"extra,omitempty"`
+	Credentials *Credentials       `json:"credentials,omitempty"`
+}
+
+// Credentials holds short-lived cloud credentials vended to an agent so it
+// can authenticate to its own provider APIs without embedding long-lived
+// secrets on the instance.
+type Credentials struct {
+	AccessKeyID     string    `json:"access_key_id"`
+	SecretAccessKey string    `json:"secret_access_key"`
+	SessionToken    string    `json:"session_token"`
+	Expiry          time.Time `json:"expiry"`
 }
 
 // ProvisionRequest represents a request to provision a new cloud instance.
@@ -108,6 +157,85 @@ type ProvisionRequest struct {
 	TeamID       string            `json:"team_id" binding:"required"`
 	Tags         map[string]string `json:"tags,omitempty"`
 	Metadata     json.RawMessage   `json:"metadata,omitempty"`
+	// ExpectedLifetimeHours estimates how long the instance will run, used to
+	// project total cost for budget admission checks. Defaults to 1 hour.
+	ExpectedLifetimeHours float64 `json:"expected_lifetime_hours,omitempty"`
+
+	// Spot requests a discounted, interruptible instance (AWS spot / GCP
+	// preemptible / Azure Spot VM) instead of on-demand capacity.
+	UseSpot bool `json:"use_spot,omitempty"`
+	// MaxSpotPriceCents caps the hourly bid price; 0 lets the provider use
+	// its default (usually the on-demand price).
+	MaxSpotPriceCents int `json:"max_spot_price_cents,omitempty"`
+	// FallbackToOnDemand provisions an on-demand replacement automatically
+	// when the spot instance receives an interruption warning.
+	FallbackToOnDemand bool `json:"fallback_to_on_demand,omitempty"`
+
+	// ImageOverride pins the instance to a specific provider image ID
+	// (e.g. an AMI ID), bypassing the platform's configured default image.
+	// Set directly by callers such as the image builder, or resolved from
+	// BotPackage/BotVersion by a CloudManager ImageResolver when empty.
+	ImageOverride string `json:"image_override,omitempty"`
+
+	// SSHPublicKey is an optional user-supplied OpenSSH public key
+	// ("ssh-rsa AAAA...") to import and use for this instance instead of
+	// generating/reusing the team's cached key pair.
+	SSHPublicKey string `json:"ssh_public_key,omitempty"`
+	// ConnectionMode selects how GetConnectionInfo should reach the
+	// instance once it's running. Empty uses the platform's native default
+	// (VNC for macOS, RDP for Windows, SSM for everything else).
+	ConnectionMode ConnectionMode `json:"connection_mode,omitempty"`
+
+	// PartnerAttribution identifies which partner's price model this
+	// instance's cost should be attributed to, so the UI can split cost
+	// estimates per partner instead of a single blended rate.
+	PartnerAttribution string `json:"partner_attribution,omitempty"`
+
+	// Requirements describes the instance in terms of resources instead of
+	// a specific InstanceType, so a provider's InstanceTypeProvider can
+	// pick the cheapest fit (Karpenter-style) rather than the caller
+	// hardcoding e.g. "t3.medium". When set, it takes precedence over
+	// InstanceType for providers that implement instance
+	// type selection; a provider that doesn't falls back to its configured
+	// default instance type.
+	Requirements *InstanceRequirements `json:"requirements,omitempty"`
+
+	// WorkloadKind overrides the K8s provisioner's configured default
+	// workload type ("pod", "job", "statefulset", "deployment") for this
+	// instance. Ignored by providers other than k8s.
+	WorkloadKind string `json:"workload_kind,omitempty"`
+
+	// AssumeRoleARN targets this instance at a specific cross-account IAM
+	// role: the aws provisioner tags the instance with it and resolves a
+	// role-scoped SSM/EC2 client for every subsequent operation against it,
+	// instead of the control plane's own configured credentials. Ignored by
+	// providers other than aws.
+	AssumeRoleARN string `json:"assume_role_arn,omitempty"`
+
+	// ExternalID is passed as sts:ExternalId when assuming AssumeRoleARN, for
+	// cross-account trust policies that require it as a confused-deputy
+	// defense. Ignored unless AssumeRoleARN is also set.
+	ExternalID string `json:"external_id,omitempty"`
+}
+
+// InstanceRequirements describes the compute shape a ProvisionRequest
+// needs, letting a caller ask for "the cheapest ARM box with >=8 GiB"
+// instead of naming a specific instance type.
+type InstanceRequirements struct {
+	MinVCPU int `json:"min_vcpu,omitempty"`
+	MaxVCPU int `json:"max_vcpu,omitempty"`
+	// MinMemoryGiB/MaxMemoryGiB bound usable memory after subtracting the
+	// provider's estimated system-reserved overhead (kubelet, OS, ENI),
+	// not the instance type's advertised total.
+	MinMemoryGiB float64 `json:"min_memory_gib,omitempty"`
+	MaxMemoryGiB float64 `json:"max_memory_gib,omitempty"`
+	// GPUCount requires at least this many GPUs. Zero means no GPU needed.
+	GPUCount int `json:"gpu_count,omitempty"`
+	// Architecture restricts candidates to "x86_64" or "arm64". Empty
+	// allows either.
+	Architecture string `json:"architecture,omitempty"`
+	// CapacityType is "on-demand" or "spot", overriding UseSpot when set.
+	CapacityType string `json:"capacity_type,omitempty"`
 }
 
 // CommandResult represents the result of a command executed on an instance.
@@ -117,18 +245,59 @@ type CommandResult struct {
 	Stderr   string `json:"stderr"`
 }
 
+// LogTailOptions configures a streaming log tail request.
+type LogTailOptions struct {
+	// Follow keeps the stream open and delivers new lines as they're
+	// written, instead of returning the requested backlog and closing.
+	Follow bool `json:"follow,omitempty"`
+	// SinceTime, if non-zero, excludes lines written before it.
+	SinceTime time.Time `json:"since_time,omitempty"`
+	// Previous tails the logs of the instance's previous run (e.g. a
+	// crashed container) instead of its current one.
+	Previous bool `json:"previous,omitempty"`
+	// Lines caps how many lines of existing backlog are sent before
+	// following begins. Zero means the provider's default.
+	Lines int `json:"lines,omitempty"`
+}
+
+// LogLine is a single line delivered by a streaming log tail.
+type LogLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Text      string    `json:"text"`
+}
+
 // DedicatedHost represents an AWS Dedicated Host for macOS instances.
 type DedicatedHost struct {
-	ID             string        `json:"id"`
-	HostID         string        `json:"host_id"`
-	InstanceType   string        `json:"instance_type"`
-	State          string        `json:"state"` // "available", "allocated", "released"
-	CurrentInstanceID string     `json:"current_instance_id,omitempty"`
-	AllocatedAt    *time.Time    `json:"allocated_at,omitempty"`
-	ReleasedAt     *time.Time    `json:"released_at,omitempty"`
-	MinAllocation  time.Duration `json:"min_allocation"`
-	CreatedAt      time.Time     `json:"created_at"`
-	UpdatedAt      time.Time     `json:"updated_at"`
+	ID                string `json:"id"`
+	HostID            string `json:"host_id"`
+	InstanceType      string `json:"instance_type"`
+	State             string `json:"state"` // "available", "allocated", "released"
+	CurrentInstanceID string `json:"current_instance_id,omitempty"`
+	AvailabilityZone  string `json:"availability_zone,omitempty"`
+	// AutoAllocated marks hosts created by the auto-scaling path (via
+	// ec2.AllocateHosts) rather than pre-seeded from AWSMacOSConfig's
+	// DedicatedHostIDs. Only auto-allocated hosts are eligible for the idle
+	// reaper to actually release back to AWS; pre-seeded hosts are assumed
+	// to be managed by whoever configured them.
+	AutoAllocated bool          `json:"auto_allocated,omitempty"`
+	AllocatedAt   *time.Time    `json:"allocated_at,omitempty"`
+	ReleasedAt    *time.Time    `json:"released_at,omitempty"`
+	MinAllocation time.Duration `json:"min_allocation"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// SSHKeyPair is an EC2 key pair imported for SSH access, cached by
+// fingerprint so repeated launches for the same team reuse it instead of
+// generating and importing a new one every time.
+type SSHKeyPair struct {
+	ID              string    `json:"id"`
+	TeamID          string    `json:"team_id,omitempty"`
+	KeyName         string    `json:"key_name"`
+	Fingerprint     string    `json:"fingerprint"`      // EC2-style MD5 of the DER public key
+	FingerprintSHA1 string    `json:"fingerprint_sha1"` // OpenSSH-style SHA-1
+	PublicKeyPEM    string    `json:"public_key_pem"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // InstanceFilters holds filters for querying cloud instances.
@@ -141,21 +310,207 @@ type InstanceFilters struct {
 	Offset   int            `json:"offset,omitempty"`
 }
 
-// CloudEvent represents a cloud infrastructure event.
+// CloudEvent represents a cloud infrastructure event. Its exported fields
+// follow the CNCF CloudEvents 1.0 spec (https://cloudevents.io): SpecVersion,
+// ID, Source, and Type are required; Subject, DataContentType, and Data are
+// optional. InstanceID is kept alongside Subject (which is always set to the
+// same value for instance-lifecycle events) since it's cheaper for in-process
+// subscribers to read than re-parsing Subject.
 type CloudEvent struct {
-	ID         string          `json:"id"`
-	Type       string          `json:"type"` // "instance.created", "instance.running", etc.
-	InstanceID string          `json:"instance_id"`
-	Timestamp  time.Time       `json:"timestamp"`
-	Data       json.RawMessage `json:"data,omitempty"`
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"` // "instance.created", "instance.running", etc.
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+
+	// InstanceID is the instance this event concerns. Set to the same
+	// value as Subject for instance-lifecycle events.
+	InstanceID string `json:"instance_id,omitempty"`
+}
+
+// TaskState represents the lifecycle state of an async provision/terminate
+// Task.
+type TaskState string
+
+const (
+	TaskStatePending TaskState = "pending"
+	TaskStateRunning TaskState = "running"
+	TaskStateDone    TaskState = "done"
+	TaskStateFailed  TaskState = "failed"
+)
+
+// TaskStatusEntry is one timestamped progress note in a Task's history, e.g.
+// "instance requested" or "waiting for running state".
+type TaskStatusEntry struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// Task tracks one long-running provision/terminate operation end-to-end: the
+// synchronous provisioner call that kicks it off, plus the asynchronous
+// state convergence (the instance reaching running or failed) that follows.
+// Clients poll GET /api/v1/tasks/:id, or stream Status via SSE, instead of
+// blocking on the original request until the instance is ready.
+type Task struct {
+	ID         string            `json:"id"`
+	Owner      string            `json:"owner"` // team ID
+	Op         string            `json:"op"`    // "provision" or "terminate"
+	InstanceID string            `json:"instance_id,omitempty"`
+	State      TaskState         `json:"state"`
+	Error      string            `json:"error,omitempty"`
+	Status     []TaskStatusEntry `json:"status"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
 }
 
 // CloudSummary holds dashboard summary data.
 type CloudSummary struct {
-	TotalInstances    int                        `json:"total_instances"`
-	ByPlatform        map[Platform]int           `json:"by_platform"`
-	ByState           map[InstanceState]int      `json:"by_state"`
-	ActiveHosts       int                        `json:"active_hosts"`
-	EstimatedCostUSD  float64                    `json:"estimated_cost_usd"`
-	TotalAccruedCents int                        `json:"total_accrued_cents"`
+	TotalInstances int                   `json:"total_instances"`
+	ByPlatform     map[Platform]int      `json:"by_platform"`
+	ByState        map[InstanceState]int `json:"by_state"`
+	ActiveHosts    int                   `json:"active_hosts"`
+	// AllocatedHosts and AvailableHosts break ActiveHosts down by whether the
+	// host currently has an instance placed on it. HostUtilization is
+	// AllocatedHosts/ActiveHosts (0 if there are no hosts).
+	AllocatedHosts    int     `json:"allocated_hosts"`
+	AvailableHosts    int     `json:"available_hosts"`
+	HostUtilization   float64 `json:"host_utilization"`
+	EstimatedCostUSD  float64 `json:"estimated_cost_usd"`
+	TotalAccruedCents int     `json:"total_accrued_cents"`
+	// SpotPrices maps "instanceType@availabilityZone" to the latest cached
+	// EC2 spot price in cents/hour, when a provisioner tracks spot pricing.
+	SpotPrices map[string]int `json:"spot_prices,omitempty"`
+}
+
+// FreezeType distinguishes why an account was frozen, which determines its
+// default grace period and who can lift it.
+type FreezeType string
+
+const (
+	// FreezeBilling marks an account delinquent on payment. Auto-applied
+	// after repeated ErrInsufficientFunds results, auto-lifted on a
+	// successful top-up.
+	FreezeBilling FreezeType = "billing"
+	// FreezeViolation marks an account frozen for a Terms of Service
+	// violation. Requires manual review to lift.
+	FreezeViolation FreezeType = "violation"
+	// FreezeLegal marks an account under a legal hold. Requires manual
+	// review to lift.
+	FreezeLegal FreezeType = "legal"
+)
+
+// AccountFreeze records one freeze placed on a user's account, gating new
+// cloud provisioning and, once its grace period elapses, running instances
+// too.
+type AccountFreeze struct {
+	ID     string     `json:"id"`
+	UserID string     `json:"user_id"`
+	Type   FreezeType `json:"type"`
+	Reason string     `json:"reason"`
+	// Metadata carries freeze-type-specific context (e.g. the Commerce
+	// transaction ID that triggered a billing freeze, or a case number for
+	// a legal hold).
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	// GracePeriod is how long running instances are left alone after the
+	// freeze before the reconciler suspends or terminates them.
+	GracePeriod time.Duration `json:"grace_period"`
+	// EscalatedAt is set when Escalate is called on this freeze - e.g. a
+	// billing freeze whose grace period expired without payment,
+	// escalated to suspend instances immediately rather than waiting out
+	// a second grace period.
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+	UnfrozenAt  *time.Time `json:"unfrozen_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// IsActive reports whether the freeze is still in effect.
+func (f *AccountFreeze) IsActive() bool {
+	return f != nil && f.UnfrozenAt == nil
+}
+
+// Package is a prepaid bundle of included usage, sold as a flat-rate
+// alternative to metered billing (Storj's PackagePlans model). A user who
+// purchases one draws down its included minutes/tokens before falling back
+// to per-use Commerce debits.
+type Package struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// IncludedMinutes maps platform ("linux", "macos", "windows") to the
+	// minutes of compute included by this package for that platform.
+	IncludedMinutes map[string]float64 `json:"included_minutes"`
+	// IncludedTokens is the total LLM token allowance included, shared
+	// across platforms.
+	IncludedTokens int64 `json:"included_tokens"`
+	PriceCents     int64 `json:"price_cents"`
+	// ValidityDays is how long after purchase the package balance can still
+	// be drawn down; 0 means it never expires.
+	ValidityDays int `json:"validity_days,omitempty"`
+	// PartnerAttribution attributes this package's revenue to a partner's
+	// price model, mirroring ProvisionRequest.PartnerAttribution.
+	PartnerAttribution string `json:"partner_attribution,omitempty"`
+}
+
+// PackageBalance tracks one user's remaining balance on a purchased
+// Package.
+type PackageBalance struct {
+	ID               string             `json:"id"`
+	UserID           string             `json:"user_id"`
+	PackageID        string             `json:"package_id"`
+	TeamID           string             `json:"team_id,omitempty"`
+	RemainingMinutes map[string]float64 `json:"remaining_minutes"`
+	RemainingTokens  int64              `json:"remaining_tokens"`
+	TransactionID    string             `json:"transaction_id,omitempty"`
+	PurchasedAt      time.Time          `json:"purchased_at"`
+	ExpiresAt        *time.Time         `json:"expires_at,omitempty"`
+}
+
+// IsExpired reports whether this balance can no longer be drawn down.
+func (p *PackageBalance) IsExpired() bool {
+	return p != nil && p.ExpiresAt != nil && time.Now().After(*p.ExpiresAt)
+}
+
+// LedgerAction identifies which kind of balance-affecting action a
+// LedgerEntry records.
+type LedgerAction string
+
+const (
+	LedgerActionHold             LedgerAction = "hold"
+	LedgerActionCapture          LedgerAction = "capture"
+	LedgerActionRelease          LedgerAction = "release"
+	LedgerActionDebit            LedgerAction = "debit"
+	LedgerActionRefund           LedgerAction = "refund"
+	LedgerActionPackageCredit    LedgerAction = "package_credit"
+	LedgerActionFreezeAdjustment LedgerAction = "freeze_adjustment"
+)
+
+// LedgerEntry is one append-only, hash-chained record of a balance-affecting
+// action (Formance-style double-entry accounting): it moves AmountCents from
+// DebitAccount to CreditAccount. Hash commits to PrevHash plus every other
+// field except ID and Metadata, so altering one of those fields or
+// reordering a past entry is detectable by recomputing the chain (see
+// ledger.Ledger.Verify).
+type LedgerEntry struct {
+	ID  string `json:"id"`
+	Seq int64  `json:"seq"`
+	// PrevHash is the Hash of the entry immediately before this one in the
+	// chain, or the genesis value for the first entry ever recorded.
+	PrevHash string `json:"prev_hash"`
+	// Hash commits to this entry's own fields (except ID and Metadata) plus
+	// PrevHash.
+	Hash      string       `json:"hash"`
+	Timestamp time.Time    `json:"timestamp"`
+	Actor     string       `json:"actor"`
+	User      string       `json:"user"`
+	Action    LedgerAction `json:"action"`
+	// DebitAccount and CreditAccount identify the two sides of this entry,
+	// e.g. "user:<id>" or "commerce:revenue".
+	DebitAccount  string                 `json:"debit_account"`
+	CreditAccount string                 `json:"credit_account"`
+	AmountCents   int64                  `json:"amount_cents"`
+	Currency      string                 `json:"currency"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 }
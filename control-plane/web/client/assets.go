@@ -0,0 +1,131 @@
+//go:build embedded
+
+package client
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// precompressedAssets holds the gzip/brotli siblings produced by
+// precompress.sh for every hashed Vite asset under dist/assets. Embedded
+// separately from UIFiles since its glob needs to match .br/.gz files
+// specifically, rather than relying on dist/**'s recursive behavior.
+//
+//go:embed dist/assets/*.br dist/assets/*.gz
+var precompressedAssets embed.FS
+
+// assetEntry caches everything RegisterUIRoutes needs to serve one embedded
+// UI file without re-reading or re-hashing it on every request: its bytes,
+// a content-hash ETag, and any pre-compressed siblings.
+type assetEntry struct {
+	etag      string
+	plain     []byte
+	brotli    []byte
+	gzip      []byte
+	immutable bool // hashed Vite assets under /assets/ get a long max-age
+}
+
+// buildAssetIndex walks uiFS (the dist-rooted sub-filesystem) once at
+// startup, reading each file and computing its SHA-256 ETag, and pairs it
+// with any .br/.gz sibling found in precompressedAssets.
+func buildAssetIndex(uiFS fs.FS) (map[string]*assetEntry, error) {
+	index := make(map[string]*assetEntry)
+
+	err := fs.WalkDir(uiFS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(p, ".br") || strings.HasSuffix(p, ".gz") {
+			return nil
+		}
+
+		data, err := fs.ReadFile(uiFS, p)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(data)
+		entry := &assetEntry{
+			etag:      `"` + hex.EncodeToString(sum[:]) + `"`,
+			plain:     data,
+			immutable: strings.HasPrefix(p, "assets/"),
+		}
+
+		if br, err := precompressedAssets.ReadFile("dist/" + p + ".br"); err == nil {
+			entry.brotli = br
+		}
+		if gz, err := precompressedAssets.ReadFile("dist/" + p + ".gz"); err == nil {
+			entry.gzip = gz
+		}
+
+		index["/"+p] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// serveAsset writes reqPath's contents with a strong ETag and the best
+// compressed variant Accept-Encoding allows, short-circuiting HEAD and
+// If-None-Match. Returns false if reqPath isn't in the index, so the caller
+// can fall back to the SPA index.
+func serveAsset(c *gin.Context, index map[string]*assetEntry, reqPath string) bool {
+	entry, ok := index[reqPath]
+	if !ok {
+		return false
+	}
+
+	if entry.immutable {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+	c.Header("ETag", entry.etag)
+	c.Header("Vary", "Accept-Encoding")
+	if ct := mime.TypeByExtension(path.Ext(reqPath)); ct != "" {
+		c.Header("Content-Type", ct)
+	} else {
+		c.Header("Content-Type", "application/octet-stream")
+	}
+
+	if c.GetHeader("If-None-Match") == entry.etag {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	if c.Request.Method == http.MethodHead {
+		c.Status(http.StatusOK)
+		return true
+	}
+
+	body, encoding := selectEncoding(c.GetHeader("Accept-Encoding"), entry)
+	if encoding != "" {
+		c.Header("Content-Encoding", encoding)
+	}
+	c.Writer.WriteHeader(http.StatusOK)
+	_, _ = c.Writer.Write(body)
+	return true
+}
+
+// selectEncoding picks the smallest variant the client's Accept-Encoding
+// header permits, preferring brotli over gzip over the uncompressed body.
+func selectEncoding(acceptEncoding string, entry *assetEntry) ([]byte, string) {
+	if entry.brotli != nil && strings.Contains(acceptEncoding, "br") {
+		return entry.brotli, "br"
+	}
+	if entry.gzip != nil && strings.Contains(acceptEncoding, "gzip") {
+		return entry.gzip, "gzip"
+	}
+	return entry.plain, ""
+}
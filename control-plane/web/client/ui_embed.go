@@ -27,20 +27,21 @@ func RegisterUIRoutes(router *gin.Engine) {
 		panic("Failed to create UI filesystem: " + err.Error())
 	}
 
-	fileServer := http.FileServer(http.FS(uiFS))
+	assetIndex, err := buildAssetIndex(uiFS)
+	if err != nil {
+		panic("Failed to index embedded UI assets: " + err.Error())
+	}
+
 	serveIndex := func(c *gin.Context) {
-		indexHTML, err := UIFiles.ReadFile("dist/index.html")
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to load UI index",
-			})
+		if serveAsset(c, assetIndex, "/index.html") {
 			return
 		}
-		c.Header("Content-Type", "text/html; charset=utf-8")
-		c.String(http.StatusOK, string(indexHTML))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load UI index",
+		})
 	}
 
-	router.GET("/ui/*filepath", func(c *gin.Context) {
+	uiHandler := func(c *gin.Context) {
 		path := c.Param("filepath")
 
 		// If accessing root UI path or a directory, serve index.html
@@ -49,42 +50,28 @@ func RegisterUIRoutes(router *gin.Engine) {
 			return
 		}
 
+		if serveAsset(c, assetIndex, path) {
+			return
+		}
+
 		// Check if it's a static asset by looking for common web asset file extensions
 		// This prevents reasoner IDs with dots (like "deepresearchagent.meta_research_methodology_reasoner")
 		// from being treated as static assets
-		pathLower := strings.ToLower(path)
-		isStaticAsset := strings.HasSuffix(pathLower, ".js") ||
-			strings.HasSuffix(pathLower, ".css") ||
-			strings.HasSuffix(pathLower, ".html") ||
-			strings.HasSuffix(pathLower, ".ico") ||
-			strings.HasSuffix(pathLower, ".png") ||
-			strings.HasSuffix(pathLower, ".jpg") ||
-			strings.HasSuffix(pathLower, ".jpeg") ||
-			strings.HasSuffix(pathLower, ".gif") ||
-			strings.HasSuffix(pathLower, ".svg") ||
-			strings.HasSuffix(pathLower, ".woff") ||
-			strings.HasSuffix(pathLower, ".woff2") ||
-			strings.HasSuffix(pathLower, ".ttf") ||
-			strings.HasSuffix(pathLower, ".eot") ||
-			strings.HasSuffix(pathLower, ".map") ||
-			strings.HasSuffix(pathLower, ".json") ||
-			strings.HasSuffix(pathLower, ".xml") ||
-			strings.HasSuffix(pathLower, ".txt")
-
-		if isStaticAsset {
-			// Try to serve the static file
-			http.StripPrefix("/ui", fileServer).ServeHTTP(c.Writer, c.Request)
+		if isStaticAssetPath(path) {
+			c.Status(http.StatusNotFound)
 			return
 		}
 
 		// For all other paths (SPA routes), serve index.html
 		serveIndex(c)
-	})
+	}
+
+	router.GET("/ui/*filepath", uiHandler)
+	router.HEAD("/ui/*filepath", uiHandler)
 
 	// Root serves the same Canvas SPA as /ui/
-	router.GET("/", func(c *gin.Context) {
-		serveIndex(c)
-	})
+	router.GET("/", serveIndex)
+	router.HEAD("/", serveIndex)
 
 	// SPA fallback for both /ui/* and root-based routes.
 	router.NoRoute(func(c *gin.Context) {
@@ -95,32 +82,13 @@ func RegisterUIRoutes(router *gin.Engine) {
 		}
 
 		// Serve static assets regardless of /ui prefix.
-		isStaticAsset := strings.HasSuffix(path, ".js") ||
-			strings.HasSuffix(path, ".css") ||
-			strings.HasSuffix(path, ".html") ||
-			strings.HasSuffix(path, ".ico") ||
-			strings.HasSuffix(path, ".png") ||
-			strings.HasSuffix(path, ".jpg") ||
-			strings.HasSuffix(path, ".jpeg") ||
-			strings.HasSuffix(path, ".gif") ||
-			strings.HasSuffix(path, ".svg") ||
-			strings.HasSuffix(path, ".woff") ||
-			strings.HasSuffix(path, ".woff2") ||
-			strings.HasSuffix(path, ".ttf") ||
-			strings.HasSuffix(path, ".eot") ||
-			strings.HasSuffix(path, ".map") ||
-			strings.HasSuffix(path, ".json") ||
-			strings.HasSuffix(path, ".xml") ||
-			strings.HasSuffix(path, ".txt")
-
-		if isStaticAsset {
-			// /ui/* static files.
-			if strings.HasPrefix(path, "/ui/") {
-				http.StripPrefix("/ui", fileServer).ServeHTTP(c.Writer, c.Request)
-				return
-			}
-			// Root static files (for Vite base="/").
-			fileServer.ServeHTTP(c.Writer, c.Request)
+		assetPath := strings.TrimPrefix(c.Request.URL.Path, "/ui")
+		if serveAsset(c, assetIndex, assetPath) {
+			return
+		}
+
+		if isStaticAssetPath(path) {
+			c.Status(http.StatusNotFound)
 			return
 		}
 
@@ -128,6 +96,22 @@ func RegisterUIRoutes(router *gin.Engine) {
 	})
 }
 
+// isStaticAssetPath reports whether path looks like a static web asset by
+// extension, so unmatched requests for missing assets 404 instead of
+// falling through to the SPA index.
+func isStaticAssetPath(path string) bool {
+	pathLower := strings.ToLower(path)
+	for _, ext := range []string{
+		".js", ".css", ".html", ".ico", ".png", ".jpg", ".jpeg", ".gif",
+		".svg", ".woff", ".woff2", ".ttf", ".eot", ".map", ".json", ".xml", ".txt",
+	} {
+		if strings.HasSuffix(pathLower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsUIEmbedded checks if UI files are embedded in the binary.
 func IsUIEmbedded() bool {
 	// Try to read a file that should exist in the embedded UI